@@ -0,0 +1,67 @@
+// Команда carsales-server открывает базу данных и предоставляет каталог, покупки, вход
+// и операции администратора по HTTP/JSON. Рабочие места подключаются к ней флагом -server,
+// сайт — через открытое API /v1, описанное в /v1/openapi.yaml.
+package main
+
+import (
+	"car-sales-system/internal/config"
+	"car-sales-system/internal/db"
+	"car-sales-system/internal/remote"
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+func main() {
+	settings := config.AddFlags(flag.CommandLine)
+	flag.Parse()
+
+	cfg, err := settings.Load()
+	if err != nil {
+		log.Fatalf("Ошибка настроек: %v", err)
+	}
+	db.MinCarYear, db.MaxCarYear = cfg.Cars.MinYear, cfg.Cars.MaxYear
+	level, _ := cfg.LogLevel() // уровень уже проверен в Load
+	slog.SetLogLoggerLevel(level)
+
+	database, err := db.InitializeDatabase(cfg.Database.Path)
+	if err != nil {
+		log.Fatalf("Ошибка инициализации базы данных: %v", err)
+	}
+	defer database.Close()
+
+	server := &http.Server{
+		Addr:              cfg.Server.Listen,
+		Handler:           remote.NewServer(db.NewSQLStore(database)),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	// По сигналу остановки сервер дожидается выполняющихся запросов и закрывает базу
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-stop
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			slog.Warn("Сервер остановлен с ошибкой", "error", err)
+		}
+	}()
+
+	slog.Info("Сервер принимает запросы", "address", cfg.Server.Listen)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("Ошибка сервера: %v", err)
+	}
+	// ListenAndServe возвращается в самом начале Shutdown, а база должна оставаться открытой,
+	// пока не завершатся выполняющиеся запросы
+	<-stopped
+}
@@ -1,18 +1,163 @@
 package main
 
 import (
+	"car-sales-system/internal/auth"
+	"car-sales-system/internal/cli"
+	"car-sales-system/internal/config"
 	"car-sales-system/internal/db"
 	"car-sales-system/internal/gui"
+	"car-sales-system/internal/remote"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
+	"log/slog"
+	"os"
+	"strings"
 )
 
 func main() {
-	database, err := db.InitializeDatabase()
+	args := os.Args[1:]
+	switch {
+	case len(args) > 0 && args[0] == "gui":
+		runGUI(args[1:])
+	case len(args) > 0 && cli.IsCommand(args[0]):
+		runCLICommand(args)
+	case len(args) > 0 && !strings.HasPrefix(args[0], "-"):
+		printUsage()
+		os.Exit(2)
+	default:
+		// Без подкоманды, как и раньше, запускается программа с окнами
+		runGUI(args)
+	}
+}
+
+// printUsage печатает список подкоманд
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "Использование: carsales [подкоманда] [флаги]")
+	fmt.Fprintln(os.Stderr, "Подкоманды:")
+	fmt.Fprintf(os.Stderr, "  %-30s %s\n", "gui", "запустить программу с окнами; по умолчанию")
+	cli.Usage(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Флаги подкоманды: carsales <подкоманда> -h")
+}
+
+// runGUI запускает программу с окнами. Флаги миграций и импорта оставлены для совместимости:
+// они выполняют подкоманды migrate и import и выходят.
+func runGUI(args []string) {
+	flags := flag.NewFlagSet("gui", flag.ExitOnError)
+	migrateStatus := flags.Bool("migrate-status", false, "то же, что carsales migrate status")
+	migrateDown := flags.Bool("migrate-down", false, "то же, что carsales migrate down")
+	importPath := flags.String("import", "", "то же, что carsales import ФАЙЛ")
+	dryRun := flags.Bool("dry-run", false, "с -import: то же, что carsales import -dry-run ФАЙЛ")
+	settings := config.AddFlags(flags)
+	flags.Parse(args)
+
+	// Флаги настроек передаются подкоманде как есть
+	var forwarded []string
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "migrate-status", "migrate-down", "import", "dry-run":
+		default:
+			forwarded = append(forwarded, "-"+f.Name+"="+f.Value.String())
+		}
+	})
+	switch {
+	case *migrateDown:
+		runCLICommand(append([]string{"migrate", "down"}, forwarded...))
+		return
+	case *migrateStatus:
+		runCLICommand(append([]string{"migrate", "status"}, forwarded...))
+		return
+	case *importPath != "":
+		if *dryRun {
+			forwarded = append(forwarded, "-dry-run")
+		}
+		runCLICommand(append(append([]string{"import"}, forwarded...), "--", *importPath))
+		return
+	}
+
+	cfg, err := loadConfig(settings)
+	if err != nil {
+		log.Fatalf("Ошибка настроек: %v", err)
+	}
+
+	settingsUI := gui.Settings{Currency: cfg.UI.Currency}
+	if cfg.Server.URL != "" {
+		// Работа через сервер: база открыта на нём, пароли проверяет тоже он
+		client, err := remote.NewClient(cfg.Server.URL)
+		if err != nil {
+			log.Fatalf("Ошибка настроек: %v", err)
+		}
+		gui.StartMainGUI(client.Store(), client, settingsUI)
+		return
+	}
+
+	database, err := db.InitializeDatabase(cfg.Database.Path)
 	if err != nil {
 		log.Fatalf("Ошибка инициализации базы данных: %v", err)
 	}
 	defer database.Close()
 
-	gui.StartMainGUI(database)
+	store := db.NewSQLStore(database)
+	gui.StartMainGUI(store, auth.NewService(store), settingsUI)
+}
+
+// runCLICommand выполняет команду без окон, например cars list или export
+func runCLICommand(args []string) {
+	env := cli.Env{
+		Stdin: os.Stdin, Stdout: os.Stdout, Stderr: os.Stderr,
+		Open: func(settings *config.Flags) (*db.Store, func() error, error) {
+			cfg, err := loadConfig(settings)
+			if err != nil {
+				return nil, nil, err
+			}
+			dbPath, err := cfg.LocalDatabase()
+			if err != nil {
+				return nil, nil, err
+			}
+			database, err := db.InitializeDatabase(dbPath)
+			if err != nil {
+				return nil, nil, err
+			}
+			return db.NewSQLStore(database), database.Close, nil
+		},
+		OpenDatabase: func(settings *config.Flags) (*db.DB, func() error, error) {
+			cfg, err := loadConfig(settings)
+			if err != nil {
+				return nil, nil, err
+			}
+			dbPath, err := cfg.LocalDatabase()
+			if err != nil {
+				return nil, nil, err
+			}
+			database, err := db.OpenDatabase(dbPath)
+			if err != nil {
+				return nil, nil, err
+			}
+			return database, database.Close, nil
+		},
+	}
+	err := cli.Run(env, args)
+	switch {
+	case errors.Is(err, cli.ErrUsage):
+		if err != cli.ErrUsage {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		os.Exit(2)
+	case err != nil:
+		log.Fatalf("Ошибка: %v", err)
+	}
+}
 
+// loadConfig собирает настройки после разбора флагов и передаёт пакету db диапазон года выпуска,
+// а журналу — уровень сообщений
+func loadConfig(settings *config.Flags) (config.Config, error) {
+	cfg, err := settings.Load()
+	if err != nil {
+		return cfg, err
+	}
+	db.MinCarYear, db.MaxCarYear = cfg.Cars.MinYear, cfg.Cars.MaxYear
+	level, _ := cfg.LogLevel() // уровень уже проверен в Load
+	slog.SetLogLoggerLevel(level)
+	return cfg, nil
 }
@@ -0,0 +1,166 @@
+package analytics
+
+import (
+	"car-sales-system/internal/db"
+	"fmt"
+	"time"
+)
+
+// Granularity — вид отчётного периода
+type Granularity string
+
+const (
+	Day    Granularity = "day"
+	Week   Granularity = "week"
+	Month  Granularity = "month"
+	Custom Granularity = "custom"
+)
+
+// Granularities — виды периодов в порядке отображения
+var Granularities = []Granularity{Day, Week, Month, Custom}
+
+var granularityTitles = map[Granularity]string{
+	Day:    "День",
+	Week:   "Неделя",
+	Month:  "Месяц",
+	Custom: "Произвольный период",
+}
+
+// Title возвращает название вида периода для интерфейса
+func (g Granularity) Title() string {
+	if title, ok := granularityTitles[g]; ok {
+		return title
+	}
+	return string(g)
+}
+
+// Period — промежуток времени [From, To)
+type Period struct {
+	Granularity Granularity
+	From        time.Time
+	To          time.Time
+}
+
+// PeriodContaining возвращает день, неделю (с понедельника) или календарный месяц, в который попадает t
+func PeriodContaining(g Granularity, t time.Time) (Period, error) {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch g {
+	case Day:
+		return Period{Granularity: Day, From: day, To: day.AddDate(0, 0, 1)}, nil
+	case Week:
+		offset := (int(day.Weekday()) + 6) % 7 // понедельник — первый день недели
+		from := day.AddDate(0, 0, -offset)
+		return Period{Granularity: Week, From: from, To: from.AddDate(0, 0, 7)}, nil
+	case Month:
+		from := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+		return Period{Granularity: Month, From: from, To: from.AddDate(0, 1, 0)}, nil
+	default:
+		return Period{}, fmt.Errorf("для периода %q нужны даты начала и конца", g)
+	}
+}
+
+// CustomPeriod возвращает период с первого по последний день включительно
+func CustomPeriod(first, last time.Time) (Period, error) {
+	from := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, first.Location())
+	to := time.Date(last.Year(), last.Month(), last.Day(), 0, 0, 0, 0, last.Location()).AddDate(0, 0, 1)
+	if !from.Before(to) {
+		return Period{}, fmt.Errorf("дата начала периода позже даты конца")
+	}
+	return Period{Granularity: Custom, From: from, To: to}, nil
+}
+
+// Previous возвращает предыдущий период той же длины в календарных днях.
+// Для месяца это предыдущий календарный месяц. Границы сдвигаются по календарю,
+// а не на длительность, чтобы переход на летнее время не сдвигал их с полуночи.
+func (p Period) Previous() Period {
+	var from time.Time
+	switch p.Granularity {
+	case Day:
+		from = p.From.AddDate(0, 0, -1)
+	case Week:
+		from = p.From.AddDate(0, 0, -7)
+	case Month:
+		from = p.From.AddDate(0, -1, 0)
+	default:
+		from = p.From.AddDate(0, 0, -p.days())
+	}
+	return Period{Granularity: p.Granularity, From: from, To: p.From}
+}
+
+// days возвращает число календарных дней в периоде
+func (p Period) days() int {
+	from := time.Date(p.From.Year(), p.From.Month(), p.From.Day(), 0, 0, 0, 0, time.UTC)
+	to := time.Date(p.To.Year(), p.To.Month(), p.To.Day(), 0, 0, 0, 0, time.UTC)
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// String возвращает период в виде «01.10.2026 – 31.10.2026» (последний день включительно)
+func (p Period) String() string {
+	last := p.To.AddDate(0, 0, -1)
+	if p.From.Equal(last) {
+		return p.From.Format("02.01.2006")
+	}
+	return p.From.Format("02.01.2006") + " – " + last.Format("02.01.2006")
+}
+
+// PeriodReport сравнивает продажи за период с предыдущим периодом той же длины
+type PeriodReport struct {
+	Period   Period
+	Previous Period
+	Current  db.SalesSummary
+	Prior    db.SalesSummary
+}
+
+// BuildPeriodReport подводит итоги продаж за период и за предыдущий период
+func BuildPeriodReport(checks db.CheckRepository, period Period) (PeriodReport, error) {
+	report := PeriodReport{Period: period, Previous: period.Previous()}
+
+	var err error
+	if report.Current, err = checks.SalesSummary(report.Period.From, report.Period.To); err != nil {
+		return PeriodReport{}, err
+	}
+	if report.Prior, err = checks.SalesSummary(report.Previous.From, report.Previous.To); err != nil {
+		return PeriodReport{}, err
+	}
+	return report, nil
+}
+
+// Change описывает изменение показателя относительно предыдущего периода
+type Change struct {
+	Delta   float64
+	Percent float64
+	// HasPercent равен false, если в предыдущем периоде показатель был нулевым
+	HasPercent bool
+}
+
+func change(current, prior float64) Change {
+	c := Change{Delta: current - prior}
+	if prior != 0 {
+		c.Percent = c.Delta / prior * 100
+		c.HasPercent = true
+	}
+	return c
+}
+
+// String возвращает изменение в виде «+1500.00 (+12.5%)»
+func (c Change) String() string {
+	if !c.HasPercent {
+		return fmt.Sprintf("%+.2f", c.Delta)
+	}
+	return fmt.Sprintf("%+.2f (%+.1f%%)", c.Delta, c.Percent)
+}
+
+// RevenueChange — изменение выручки
+func (r PeriodReport) RevenueChange() Change {
+	return change(r.Current.Revenue, r.Prior.Revenue)
+}
+
+// UnitsChange — изменение числа проданных автомобилей
+func (r PeriodReport) UnitsChange() Change {
+	return change(float64(r.Current.Units), float64(r.Prior.Units))
+}
+
+// AveragePriceChange — изменение средней цены продажи
+func (r PeriodReport) AveragePriceChange() Change {
+	return change(r.Current.AveragePrice(), r.Prior.AveragePrice())
+}
@@ -0,0 +1,115 @@
+package analytics
+
+import (
+	"car-sales-system/internal/db"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestPeriodContaining(t *testing.T) {
+	ref := time.Date(2026, time.October, 15, 13, 45, 0, 0, time.UTC) // четверг
+
+	tests := []struct {
+		granularity  Granularity
+		from, to     time.Time
+		prevFrom     time.Time
+		wantTitleStr string
+	}{
+		{Day, date(2026, 10, 15), date(2026, 10, 16), date(2026, 10, 14), "15.10.2026"},
+		{Week, date(2026, 10, 12), date(2026, 10, 19), date(2026, 10, 5), "12.10.2026 – 18.10.2026"},
+		{Month, date(2026, 10, 1), date(2026, 11, 1), date(2026, 9, 1), "01.10.2026 – 31.10.2026"},
+	}
+	for _, tt := range tests {
+		p, err := PeriodContaining(tt.granularity, ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !p.From.Equal(tt.from) || !p.To.Equal(tt.to) {
+			t.Errorf("%s: период %s – %s", tt.granularity, p.From, p.To)
+		}
+		if prev := p.Previous(); !prev.From.Equal(tt.prevFrom) || !prev.To.Equal(p.From) {
+			t.Errorf("%s: предыдущий период %s – %s", tt.granularity, prev.From, prev.To)
+		}
+		if p.String() != tt.wantTitleStr {
+			t.Errorf("%s: String() = %q", tt.granularity, p.String())
+		}
+	}
+
+	if _, err := PeriodContaining(Custom, ref); err == nil {
+		t.Error("произвольный период без дат должен возвращать ошибку")
+	}
+}
+
+func TestPreviousAcrossDST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("нет базы часовых поясов: %v", err)
+	}
+	midnight := func(month time.Month, day int) time.Time {
+		return time.Date(2026, month, day, 0, 0, 0, 0, berlin)
+	}
+
+	// 29 марта 2026 года в Берлине длится 23 часа, 25 октября — 25 часов
+	tests := []struct {
+		name     string
+		period   func() (Period, error)
+		prevFrom time.Time
+	}{
+		{"день после перехода на летнее время", func() (Period, error) { return PeriodContaining(Day, midnight(3, 30)) }, midnight(3, 29)},
+		{"неделя после перехода на летнее время", func() (Period, error) { return PeriodContaining(Week, midnight(4, 1)) }, midnight(3, 23)},
+		{"день после перехода на зимнее время", func() (Period, error) { return PeriodContaining(Day, midnight(10, 26)) }, midnight(10, 25)},
+		{"неделя с переходом на зимнее время", func() (Period, error) { return CustomPeriod(midnight(10, 25), midnight(10, 31)) }, midnight(10, 18)},
+	}
+	for _, tt := range tests {
+		p, err := tt.period()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if prev := p.Previous(); !prev.From.Equal(tt.prevFrom) || !prev.To.Equal(p.From) {
+			t.Errorf("%s: предыдущий период %s – %s, ожидалось начало %s", tt.name, prev.From, prev.To, tt.prevFrom)
+		}
+	}
+}
+
+func TestBuildPeriodReport(t *testing.T) {
+	store := db.NewMemoryStore()
+	sales := []db.Check{
+		// Продажа относится к периоду, в котором её подтвердили, а не оформили
+		{Price: 1000, Status: db.CheckApproved, CreatedAt: date(2026, 9, 28), DecidedAt: date(2026, 10, 3)},
+		{Price: 3000, Status: db.CheckApproved, CreatedAt: date(2026, 10, 20), DecidedAt: date(2026, 10, 20)},
+		{Price: 500, Status: db.CheckPending, CreatedAt: date(2026, 10, 21)},
+		{Price: 2000, Status: db.CheckApproved, CreatedAt: date(2026, 9, 10), DecidedAt: date(2026, 9, 10)},
+		{Price: 9000, Status: db.CheckApproved, CreatedAt: date(2026, 10, 30), DecidedAt: date(2026, 11, 1)},
+	}
+	for i := range sales {
+		if err := store.Checks.Create(&sales[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	period, err := CustomPeriod(date(2026, 10, 1), date(2026, 10, 31))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := BuildPeriodReport(store.Checks, period)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Current.Units != 2 || report.Current.Revenue != 4000 || report.Current.AveragePrice() != 2000 {
+		t.Errorf("текущий период: %+v", report.Current)
+	}
+	if report.Prior.Units != 1 || report.Prior.Revenue != 2000 {
+		t.Errorf("предыдущий период: %+v", report.Prior)
+	}
+	if c := report.RevenueChange(); c.Delta != 2000 || c.Percent != 100 || !c.HasPercent {
+		t.Errorf("изменение выручки: %+v", c)
+	}
+	if c := report.AveragePriceChange(); c.Delta != 0 {
+		t.Errorf("изменение средней цены: %+v", c)
+	}
+}
@@ -0,0 +1,207 @@
+package analytics
+
+import (
+	"car-sales-system/internal/db"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GroupBy — признак, по которому группируются продажи
+type GroupBy string
+
+const (
+	ByBrand GroupBy = "brand"
+	ByModel GroupBy = "model"
+	ByYear  GroupBy = "year"
+	ByColor GroupBy = "color"
+	ByAdmin GroupBy = "admin"
+)
+
+// Groupings — варианты группировки в порядке отображения
+var Groupings = []GroupBy{ByBrand, ByModel, ByYear, ByColor, ByAdmin}
+
+var groupTitles = map[GroupBy]string{
+	ByBrand: "Марка",
+	ByModel: "Модель",
+	ByYear:  "Год выпуска",
+	ByColor: "Цвет",
+	ByAdmin: "Администратор",
+}
+
+// Title возвращает название группировки для интерфейса
+func (g GroupBy) Title() string {
+	if title, ok := groupTitles[g]; ok {
+		return title
+	}
+	return string(g)
+}
+
+// SortBy — показатель, по которому упорядочиваются группы
+type SortBy string
+
+const (
+	SortRevenue  SortBy = "revenue"
+	SortUnits    SortBy = "units"
+	SortAverage  SortBy = "average"
+	SortDiscount SortBy = "discount"
+)
+
+// SortOrders — показатели сортировки в порядке отображения
+var SortOrders = []SortBy{SortRevenue, SortUnits, SortAverage, SortDiscount}
+
+var sortTitles = map[SortBy]string{
+	SortRevenue:  "Выручка",
+	SortUnits:    "Продано",
+	SortAverage:  "Средняя цена",
+	SortDiscount: "Скидка",
+}
+
+// Title возвращает название показателя для интерфейса
+func (s SortBy) Title() string {
+	if title, ok := sortTitles[s]; ok {
+		return title
+	}
+	return string(s)
+}
+
+// SalesQuery — настройки анализа продаж
+type SalesQuery struct {
+	GroupBy GroupBy
+	SortBy  SortBy
+	// Ascending упорядочивает группы по возрастанию показателя, по умолчанию — по убыванию
+	Ascending bool
+	// Limit — число групп в результате, 0 — без ограничения
+	Limit int
+	// IncludeArchived учитывает продажи архивных и удалённых из базы автомобилей
+	IncludeArchived bool
+}
+
+// SalesRow — итог продаж одной группы
+type SalesRow struct {
+	Group   string
+	Revenue float64
+	Units   int
+	// Listed — сумма первых выставленных цен проданных автомобилей
+	Listed float64
+}
+
+// AveragePrice возвращает среднюю цену продажи в группе
+func (r SalesRow) AveragePrice() float64 {
+	if r.Units == 0 {
+		return 0
+	}
+	return r.Revenue / float64(r.Units)
+}
+
+// Discount возвращает сумму скидок от первой выставленной цены до цены продажи
+func (r SalesRow) Discount() float64 {
+	return r.Listed - r.Revenue
+}
+
+// DiscountPercent возвращает скидку в процентах от первых выставленных цен
+func (r SalesRow) DiscountPercent() float64 {
+	if r.Listed == 0 {
+		return 0
+	}
+	return r.Discount() / r.Listed * 100
+}
+
+func (r SalesRow) value(s SortBy) float64 {
+	switch s {
+	case SortDiscount:
+		return r.DiscountPercent()
+	case SortUnits:
+		return float64(r.Units)
+	case SortAverage:
+		return r.AveragePrice()
+	default:
+		return r.Revenue
+	}
+}
+
+// AnalyzeSales загружает подтверждённые продажи и группирует их согласно запросу
+func AnalyzeSales(checks db.CheckRepository, q SalesQuery) ([]SalesRow, error) {
+	sales, err := checks.ListSales(q.IncludeArchived)
+	if err != nil {
+		return nil, err
+	}
+	return GroupSales(sales, q), nil
+}
+
+// GroupSales группирует продажи, упорядочивает группы и оставляет первые q.Limit.
+// Группы с равным показателем идут в алфавитном порядке.
+func GroupSales(sales []db.Sale, q SalesQuery) []SalesRow {
+	index := make(map[string]int)
+	var rows []SalesRow
+	for _, s := range sales {
+		key := groupKey(s, q.GroupBy)
+		i, ok := index[key]
+		if !ok {
+			i = len(rows)
+			index[key] = i
+			rows = append(rows, SalesRow{Group: key})
+		}
+		rows[i].add(s)
+	}
+
+	SortRows(rows, q.SortBy, q.Ascending)
+	if q.Limit > 0 && len(rows) > q.Limit {
+		rows = rows[:q.Limit]
+	}
+	return rows
+}
+
+// TotalSales подводит итог всех продаж одной строкой
+func TotalSales(sales []db.Sale) SalesRow {
+	total := SalesRow{Group: "Итого"}
+	for _, s := range sales {
+		total.add(s)
+	}
+	return total
+}
+
+func (r *SalesRow) add(s db.Sale) {
+	r.Revenue += s.Price
+	r.Units++
+	r.Listed += s.ListedPrice
+}
+
+// SortRows упорядочивает уже сгруппированные строки по показателю
+func SortRows(rows []SalesRow, by SortBy, ascending bool) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i].value(by), rows[j].value(by)
+		if a == b {
+			return rows[i].Group < rows[j].Group
+		}
+		if ascending {
+			return a < b
+		}
+		return a > b
+	})
+}
+
+// groupKey возвращает подпись группы, в которую попадает продажа
+func groupKey(s db.Sale, g GroupBy) string {
+	if s.CarDeleted && g != ByAdmin {
+		return "(автомобиль удалён из базы)"
+	}
+	switch g {
+	case ByModel:
+		return s.Brand + " " + s.Model
+	case ByYear:
+		return strconv.Itoa(s.Year)
+	case ByColor:
+		return s.Color
+	case ByAdmin:
+		if s.AdminID == 0 {
+			return "(не указан)"
+		}
+		if name := strings.TrimSpace(s.AdminName + " " + s.AdminLastName); name != "" {
+			return name
+		}
+		return "Администратор №" + strconv.Itoa(s.AdminID)
+	default:
+		return s.Brand
+	}
+}
@@ -0,0 +1,58 @@
+package analytics
+
+import (
+	"car-sales-system/internal/db"
+	"testing"
+)
+
+func TestGroupSales(t *testing.T) {
+	sales := []db.Sale{
+		{Brand: "Toyota", Model: "Camry", Color: "Black", Year: 2020, AdminID: 1, AdminName: "Михаил", AdminLastName: "Филин", Price: 20000},
+		{Brand: "Toyota", Model: "Corolla", Color: "White", Year: 2021, AdminID: 1, AdminName: "Михаил", AdminLastName: "Филин", Price: 10000},
+		{Brand: "BMW", Model: "X5", Color: "Black", Year: 2020, Price: 50000},
+		{CarDeleted: true, Price: 5000},
+	}
+
+	rows := GroupSales(sales, SalesQuery{GroupBy: ByBrand, SortBy: SortUnits})
+	want := []SalesRow{
+		{Group: "Toyota", Revenue: 30000, Units: 2},
+		{Group: "(автомобиль удалён из базы)", Revenue: 5000, Units: 1},
+		{Group: "BMW", Revenue: 50000, Units: 1},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("группы: %+v", rows)
+	}
+	for i := range want {
+		if rows[i] != want[i] {
+			t.Errorf("строка %d: %+v, ожидалась %+v", i, rows[i], want[i])
+		}
+	}
+
+	rows = GroupSales(sales, SalesQuery{GroupBy: ByColor, SortBy: SortAverage, Limit: 1})
+	if len(rows) != 1 || rows[0].Group != "Black" || rows[0].AveragePrice() != 35000 {
+		t.Errorf("топ-1 по средней цене: %+v", rows)
+	}
+
+	rows = GroupSales(sales, SalesQuery{GroupBy: ByAdmin, SortBy: SortRevenue, Ascending: true})
+	if len(rows) != 2 || rows[0].Group != "Михаил Филин" || rows[1].Group != "(не указан)" {
+		t.Errorf("группировка по администратору: %+v", rows)
+	}
+}
+
+func TestSalesDiscount(t *testing.T) {
+	sales := []db.Sale{
+		{Brand: "Toyota", Price: 18000, ListedPrice: 20000},
+		{Brand: "Toyota", Price: 10000, ListedPrice: 10000},
+		{Brand: "BMW", Price: 45000, ListedPrice: 50000},
+	}
+
+	total := TotalSales(sales)
+	if total.Units != 3 || total.Listed != 80000 || total.Discount() != 7000 || total.DiscountPercent() != 8.75 {
+		t.Errorf("итог скидок: %+v", total)
+	}
+
+	rows := GroupSales(sales, SalesQuery{GroupBy: ByBrand, SortBy: SortDiscount})
+	if len(rows) != 2 || rows[0].Group != "BMW" || rows[0].DiscountPercent() != 10 || rows[1].Discount() != 2000 {
+		t.Errorf("скидки по маркам: %+v", rows)
+	}
+}
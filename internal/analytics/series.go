@@ -0,0 +1,76 @@
+package analytics
+
+import (
+	"car-sales-system/internal/db"
+	"time"
+)
+
+// SeriesPoint — выручка за один период временного ряда
+type SeriesPoint struct {
+	Period  Period
+	Revenue float64
+	Units   int
+}
+
+// Label возвращает короткую подпись периода для оси графика
+func (p SeriesPoint) Label() string {
+	if p.Period.Granularity == Month {
+		return p.Period.From.Format("01.2006")
+	}
+	return p.Period.From.Format("02.01")
+}
+
+// RevenueSeries раскладывает выручку по последним count периодам вида g, заканчивая периодом, содержащим now.
+// Продажа относится к периоду, в котором её подтвердили; продажи без времени подтверждения
+// (из старой базы) не учитываются.
+func RevenueSeries(sales []db.Sale, g Granularity, count int, now time.Time) ([]SeriesPoint, error) {
+	if count <= 0 {
+		return nil, nil
+	}
+	last, err := PeriodContaining(g, now)
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]SeriesPoint, count)
+	period := last
+	for i := count - 1; i >= 0; i-- {
+		points[i].Period = period
+		period = period.Previous()
+	}
+
+	for _, s := range sales {
+		if s.DecidedAt.IsZero() {
+			continue
+		}
+		decided := s.DecidedAt.In(now.Location())
+		for i := range points {
+			if !decided.Before(points[i].Period.From) && decided.Before(points[i].Period.To) {
+				points[i].Revenue += s.Price
+				points[i].Units++
+				break
+			}
+		}
+	}
+	return points, nil
+}
+
+// StatusCount — число автомобилей в одном состоянии
+type StatusCount struct {
+	Status db.CarStatus
+	Count  int
+}
+
+// InventoryByStatus считает автомобили по состояниям в порядке db.CarStatuses
+func InventoryByStatus(cars []db.Car) []StatusCount {
+	counts := make(map[db.CarStatus]int)
+	for _, car := range cars {
+		counts[car.Status]++
+	}
+
+	result := make([]StatusCount, 0, len(db.CarStatuses))
+	for _, status := range db.CarStatuses {
+		result = append(result, StatusCount{Status: status, Count: counts[status]})
+	}
+	return result
+}
@@ -0,0 +1,53 @@
+package analytics
+
+import (
+	"car-sales-system/internal/db"
+	"testing"
+	"time"
+)
+
+func TestRevenueSeries(t *testing.T) {
+	now := time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC)
+	sales := []db.Sale{
+		{Price: 1000, CreatedAt: date(2026, 9, 28), DecidedAt: date(2026, 10, 2)}, // заказан в сентябре, продан в октябре
+		{Price: 2000, CreatedAt: date(2026, 10, 17), DecidedAt: date(2026, 10, 17)},
+		{Price: 4000, CreatedAt: date(2026, 8, 31), DecidedAt: date(2026, 8, 31)},
+		{Price: 8000, CreatedAt: date(2026, 7, 1), DecidedAt: date(2026, 7, 1)}, // раньше начала ряда
+		{Price: 9000, CreatedAt: date(2026, 10, 1)},                             // без времени подтверждения
+	}
+
+	points, err := RevenueSeries(sales, Month, 3, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		label   string
+		revenue float64
+		units   int
+	}{
+		{"08.2026", 4000, 1},
+		{"09.2026", 0, 0},
+		{"10.2026", 3000, 2},
+	}
+	if len(points) != len(want) {
+		t.Fatalf("точек: %d", len(points))
+	}
+	for i, w := range want {
+		p := points[i]
+		if p.Label() != w.label || p.Revenue != w.revenue || p.Units != w.units {
+			t.Errorf("точка %d: %s %.0f %d, ожидалось %+v", i, p.Label(), p.Revenue, p.Units, w)
+		}
+	}
+}
+
+func TestInventoryByStatus(t *testing.T) {
+	cars := []db.Car{{Status: db.StatusInStock}, {Status: db.StatusInStock}, {Status: db.StatusSold}}
+
+	counts := InventoryByStatus(cars)
+	if len(counts) != len(db.CarStatuses) {
+		t.Fatalf("состояний: %d", len(counts))
+	}
+	if counts[0] != (StatusCount{db.StatusInStock, 2}) || counts[2] != (StatusCount{db.StatusSold, 1}) || counts[1].Count != 0 {
+		t.Errorf("остатки: %+v", counts)
+	}
+}
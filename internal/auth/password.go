@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// HashPassword возвращает bcrypt-хэш пароля; соль генерируется для каждого вызова
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("ошибка хэширования пароля: %w", err)
+	}
+	return string(hash), nil
+}
+
+// IsHashed сообщает, хранится ли пароль в виде bcrypt-хэша.
+// Всё остальное считается паролем старого формата в открытом виде.
+func IsHashed(stored string) bool {
+	for _, prefix := range []string{"$2a$", "$2b$", "$2y$"} {
+		if strings.HasPrefix(stored, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckPassword сравнивает введённый пароль с сохранённым значением.
+// legacy равен true, если пароль совпал с записью старого формата и её нужно перехэшировать.
+func CheckPassword(stored, password string) (ok, legacy bool) {
+	if !IsHashed(stored) {
+		return subtle.ConstantTimeCompare([]byte(stored), []byte(password)) == 1, true
+	}
+
+	err := bcrypt.CompareHashAndPassword([]byte(stored), []byte(password))
+	if err != nil && !errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, false
+	}
+	return err == nil, false
+}
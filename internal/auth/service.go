@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"car-sales-system/internal/db"
+	"errors"
+	"log/slog"
+)
+
+// ErrInvalidCredentials возвращается при неверном логине или пароле
+var ErrInvalidCredentials = errors.New("неверный логин или пароль")
+
+// Authenticator регистрирует клиентов и проверяет учётные данные при входе.
+// Реализуется сервисом поверх локального хранилища и клиентом сервера carsales-server.
+type Authenticator interface {
+	// RegisterClient сохраняет клиента и записывает присвоенный ID в client.ID
+	RegisterClient(client *db.Client) error
+	LoginClient(login, password string) (*db.Client, error)
+	LoginAdmin(login, password string) (*db.Admin, error)
+}
+
+// Logouter реализуют аутентификаторы, которые хранят сессию у себя, как клиент сервера
+// carsales-server: при выходе пользователя сессию нужно завершить и там
+type Logouter interface {
+	Logout() error
+}
+
+// Service проверяет учётные данные клиентов и администраторов
+type Service struct {
+	clients db.ClientRepository
+	admins  db.AdminRepository
+}
+
+// NewService создаёт сервис аутентификации поверх репозиториев хранилища
+func NewService(store *db.Store) *Service {
+	return &Service{clients: store.Clients, admins: store.Admins}
+}
+
+// RegisterClient сохраняет клиента, заменяя пароль его хэшем
+func (s *Service) RegisterClient(client *db.Client) error {
+	hash, err := HashPassword(client.Password)
+	if err != nil {
+		return err
+	}
+	client.Password = hash
+	return s.clients.Create(client)
+}
+
+// LoginClient проверяет логин и пароль клиента.
+// Пароль старого формата после успешного входа перехэшируется.
+func (s *Service) LoginClient(login, password string) (*db.Client, error) {
+	client, err := s.clients.GetByLogin(login)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ok, legacy := CheckPassword(client.Password, password)
+	if !ok {
+		return nil, ErrInvalidCredentials
+	}
+	if legacy {
+		s.upgrade(login, password, func(hash string) error {
+			return s.clients.UpdatePassword(client.ID, hash)
+		})
+	}
+	return client, nil
+}
+
+// LoginAdmin проверяет логин и пароль администратора.
+// Пароль старого формата после успешного входа перехэшируется.
+func (s *Service) LoginAdmin(login, password string) (*db.Admin, error) {
+	admin, err := s.admins.GetByLogin(login)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ok, legacy := CheckPassword(admin.Password, password)
+	if !ok {
+		return nil, ErrInvalidCredentials
+	}
+	if legacy {
+		s.upgrade(login, password, func(hash string) error {
+			return s.admins.UpdatePassword(admin.ID, hash)
+		})
+	}
+	return admin, nil
+}
+
+// upgrade заменяет пароль в открытом виде хэшем.
+// Ошибка не мешает входу: запись будет перехэширована при следующей попытке.
+func (s *Service) upgrade(login, password string, save func(hash string) error) {
+	hash, err := HashPassword(password)
+	if err == nil {
+		err = save(hash)
+	}
+	if err != nil {
+		slog.Warn("Не удалось перехэшировать пароль пользователя", "login", login, "error", err)
+	}
+}
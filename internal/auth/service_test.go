@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"car-sales-system/internal/db"
+	"errors"
+	"testing"
+)
+
+func TestLoginUpgradesLegacyPassword(t *testing.T) {
+	store := db.NewMemoryStore()
+	service := NewService(store)
+
+	// Клиент из старой базы: пароль хранится в открытом виде
+	legacy := db.Client{Name: "Иван", LastName: "Иванов", Login: "ivanov", Password: "password123"}
+	if err := store.Clients.Create(&legacy); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := service.LoginClient("ivanov", "wrong"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("неверный пароль: ожидалась ErrInvalidCredentials, получено %v", err)
+	}
+	if _, err := service.LoginClient("ivanov", "password123"); err != nil {
+		t.Fatalf("вход со старым паролем: %v", err)
+	}
+
+	stored, err := store.Clients.GetByLogin("ivanov")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsHashed(stored.Password) {
+		t.Fatalf("пароль не перехэширован: %q", stored.Password)
+	}
+
+	if _, err := service.LoginClient("ivanov", "password123"); err != nil {
+		t.Fatalf("вход после перехэширования: %v", err)
+	}
+	if _, err := service.LoginClient("ivanov", stored.Password); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("вход по самому хэшу: ожидалась ErrInvalidCredentials, получено %v", err)
+	}
+}
+
+func TestRegisterClientStoresHash(t *testing.T) {
+	store := db.NewMemoryStore()
+	service := NewService(store)
+
+	client := db.Client{Name: "Мария", LastName: "Петрова", Login: "maria", Password: "securepass"}
+	if err := service.RegisterClient(&client); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := store.Clients.GetByLogin("maria")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Password == "securepass" || !IsHashed(stored.Password) {
+		t.Fatalf("пароль сохранён в открытом виде: %q", stored.Password)
+	}
+	if _, err := service.LoginClient("maria", "securepass"); err != nil {
+		t.Fatalf("вход после регистрации: %v", err)
+	}
+	if _, err := service.LoginAdmin("maria", "securepass"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("клиент вошёл как администратор: %v", err)
+	}
+}
+
+func TestSessionLockAndUnlock(t *testing.T) {
+	store := db.NewMemoryStore()
+	service := NewService(store)
+
+	admin := db.Admin{Name: "Оля", LastName: "Ламонова", Login: "olya", Password: "1234q"}
+	if err := store.Admins.Create(&admin); err != nil {
+		t.Fatal(err)
+	}
+	session := NewSession(RoleAdmin, admin.ID, admin.Login)
+
+	if !session.Lock() || session.Active() {
+		t.Fatal("сессия не заблокировалась")
+	}
+	if session.Lock() {
+		t.Fatal("повторная блокировка должна возвращать false")
+	}
+	if err := service.Unlock(session, "wrong"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("неверный пароль: ожидалась ErrInvalidCredentials, получено %v", err)
+	}
+	if err := service.Unlock(session, "1234q"); err != nil || !session.Active() {
+		t.Fatalf("разблокировка: %v", err)
+	}
+
+	session.Close()
+	if session.Active() || session.Lock() {
+		t.Fatal("закрытая сессия осталась активной")
+	}
+	if err := service.Unlock(session, "1234q"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("разблокировка закрытой сессии: %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// Role — роль вошедшего пользователя
+type Role string
+
+const (
+	RoleClient Role = "client"
+	RoleAdmin  Role = "admin"
+)
+
+// Session описывает вход одного пользователя: кто вошёл, в какой роли и когда.
+// Сессия блокируется после простоя и закрывается при выходе.
+type Session struct {
+	UserID     int
+	Login      string
+	Role       Role
+	LoggedInAt time.Time
+
+	mu           sync.Mutex
+	lastActivity time.Time
+	locked       bool
+	closed       bool
+}
+
+// NewSession открывает сессию пользователя, прошедшего проверку пароля
+func NewSession(role Role, userID int, login string) *Session {
+	now := time.Now()
+	return &Session{
+		UserID:       userID,
+		Login:        login,
+		Role:         role,
+		LoggedInAt:   now,
+		lastActivity: now,
+	}
+}
+
+// Touch отмечает действие пользователя; в заблокированной сессии ничего не делает
+func (s *Session) Touch() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.locked && !s.closed {
+		s.lastActivity = time.Now()
+	}
+}
+
+// IdleSince возвращает время последнего действия пользователя
+func (s *Session) IdleSince() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastActivity
+}
+
+// Active сообщает, что сессия не заблокирована и не закрыта
+func (s *Session) Active() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return !s.locked && !s.closed
+}
+
+// Lock блокирует сессию до повторного ввода пароля.
+// Возвращает false, если сессия уже заблокирована или закрыта.
+func (s *Session) Lock() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.locked || s.closed {
+		return false
+	}
+	s.locked = true
+	return true
+}
+
+// Close завершает сессию; после выхода её нельзя разблокировать
+func (s *Session) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+}
+
+// Closed сообщает, что пользователь вышел
+func (s *Session) Closed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.closed
+}
+
+// Unlock снимает блокировку сессии, если пароль пользователя верен
+func (s *Service) Unlock(session *Session, password string) error {
+	return Unlock(s, session, password)
+}
+
+// Unlock снимает блокировку сессии, если authenticator подтверждает пароль пользователя
+func Unlock(authenticator Authenticator, session *Session, password string) error {
+	var err error
+	switch session.Role {
+	case RoleAdmin:
+		_, err = authenticator.LoginAdmin(session.Login, password)
+	default:
+		_, err = authenticator.LoginClient(session.Login, password)
+	}
+	if err != nil {
+		return err
+	}
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	if session.closed {
+		return ErrInvalidCredentials
+	}
+	session.locked = false
+	session.lastActivity = time.Now()
+	return nil
+}
@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"car-sales-system/internal/db"
+	"car-sales-system/internal/export"
+	"car-sales-system/internal/vin"
+	"flag"
+	"fmt"
+	"slices"
+)
+
+var carsAdd = command{
+	group: "cars", name: "add",
+	summary: "добавить автомобиль; проверяется так же, как в форме администратора",
+	setup: func(fs *flag.FlagSet) func(*invocation) error {
+		var car db.Car
+		var vinText, fuel, transmission, body, drive string
+		fs.StringVar(&car.Brand, "brand", "", "марка")
+		fs.StringVar(&car.Model, "model", "", "модель")
+		fs.IntVar(&car.Year, "year", 0, "год выпуска")
+		fs.StringVar(&car.Color, "color", "", "цвет")
+		fs.Float64Var(&car.Price, "price", 0, "цена")
+		fs.StringVar(&vinText, "vin", "", "VIN")
+		fs.IntVar(&car.Mileage, "mileage", 0, "пробег, км")
+		fs.Float64Var(&car.EngineVolume, "engine", 0, "объём двигателя, л")
+		fs.StringVar(&fuel, "fuel", "", "топливо: "+names(db.FuelTypes))
+		fs.StringVar(&transmission, "transmission", "", "коробка передач: "+names(db.Transmissions))
+		fs.StringVar(&body, "body", "", "кузов: "+names(db.BodyTypes))
+		fs.StringVar(&drive, "drive", "", "привод: "+names(db.DriveTypes))
+		fs.IntVar(&car.Owners, "owners", 0, "число предыдущих владельцев")
+
+		return func(inv *invocation) error {
+			car.VIN = vin.Normalize(vinText)
+			car.Fuel, car.Transmission = db.FuelType(fuel), db.Transmission(transmission)
+			car.Body, car.Drive = db.BodyType(body), db.DriveType(drive)
+			if err := inv.store.Cars.Create(&car); err != nil {
+				return err
+			}
+			added, err := inv.store.Cars.Get(car.ID)
+			if err != nil {
+				return err
+			}
+			return inv.print(export.CarTable([]db.Car{*added}))
+		}
+	},
+}
+
+var carsList = command{
+	group: "cars", name: "list",
+	summary: "список автомобилей",
+	setup: func(fs *flag.FlagSet) func(*invocation) error {
+		statuses := fs.String("status", "", "статусы через запятую: "+names(db.CarStatuses)+"; по умолчанию все")
+
+		return func(inv *invocation) error {
+			var filter []db.CarStatus
+			for _, name := range splitList(*statuses) {
+				if !slices.Contains(db.CarStatuses, db.CarStatus(name)) {
+					return fmt.Errorf("%w: неизвестный статус автомобиля %q, допустимы: %s", ErrUsage, name, names(db.CarStatuses))
+				}
+				filter = append(filter, db.CarStatus(name))
+			}
+			cars, err := inv.store.Cars.List(filter...)
+			if err != nil {
+				return err
+			}
+			return inv.print(export.CarTable(cars))
+		}
+	},
+}
+
+var carsArchive = command{
+	group: "cars", name: "archive", args: "ID...",
+	summary: "переместить автомобили в архив",
+	setup: func(*flag.FlagSet) func(*invocation) error {
+		return func(inv *invocation) error {
+			return inv.setCarStatus(db.StatusArchived, func(*db.Car) error { return nil })
+		}
+	},
+}
+
+var carsRestore = command{
+	group: "cars", name: "restore", args: "ID...",
+	summary: "вернуть автомобили из архива в продажу",
+	setup: func(*flag.FlagSet) func(*invocation) error {
+		return func(inv *invocation) error {
+			return inv.setCarStatus(db.StatusInStock, func(car *db.Car) error {
+				if car.Status != db.StatusArchived {
+					return fmt.Errorf("автомобиль %d не в архиве, а %s", car.ID, car.Status.Title())
+				}
+				return nil
+			})
+		}
+	},
+}
+
+// setCarStatus переводит автомобили из аргументов в состояние to и печатает их.
+// Все автомобили проверяются до первого изменения, чтобы опечатка в номере
+// не оставила часть списка изменённой.
+func (inv *invocation) setCarStatus(to db.CarStatus, check func(*db.Car) error) error {
+	ids, err := inv.ids()
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		car, err := inv.store.Cars.Get(id)
+		if err != nil {
+			return fmt.Errorf("автомобиль %d: %w", id, err)
+		}
+		if err := check(car); err != nil {
+			return err
+		}
+		if !car.Status.CanSetManually(to) {
+			return fmt.Errorf("автомобиль %d: %w: %s → %s", id, db.ErrInvalidTransition, car.Status.Title(), to.Title())
+		}
+	}
+
+	var changed []db.Car
+	for _, id := range ids {
+		if err := inv.store.Cars.SetStatus(id, to); err != nil {
+			return fmt.Errorf("автомобиль %d: %w", id, err)
+		}
+		car, err := inv.store.Cars.Get(id)
+		if err != nil {
+			return err
+		}
+		changed = append(changed, *car)
+	}
+	return inv.print(export.CarTable(changed))
+}
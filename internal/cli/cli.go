@@ -0,0 +1,231 @@
+// Package cli — команды администрирования без графического интерфейса, например
+// carsales cars list, carsales admins create, carsales import или carsales migrate status. Они работают с базой напрямую, подходят
+// для скриптов и работы по SSH и печатают результат таблицей или, с флагом -json, в JSON.
+package cli
+
+import (
+	"bufio"
+	"car-sales-system/internal/config"
+	"car-sales-system/internal/db"
+	"car-sales-system/internal/export"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Env — окружение, в котором выполняются команды
+type Env struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	// Open открывает хранилище по настройкам из флагов команды; close закрывает его
+	Open func(settings *config.Flags) (store *db.Store, close func() error, err error)
+	// OpenDatabase открывает базу без применения миграций; нужна командам migrate
+	OpenDatabase func(settings *config.Flags) (database *db.DB, close func() error, err error)
+}
+
+// ErrUsage возвращается, если команда вызвана неправильно. Сама ErrUsage означает, что подсказка
+// уже напечатана в Stderr; обёрнутая ошибка объясняет, что не так с аргументами.
+var ErrUsage = errors.New("неверный вызов команды")
+
+// command — одна команда вида «группа действие» или одно слово, если name пустое
+type command struct {
+	group, name string
+	// ownOutput — команда сама выбирает формат вывода, флаг -json не добавляется
+	ownOutput bool
+	// database — команде нужна база без применения миграций вместо хранилища
+	database bool
+	// args — позиционные аргументы для подсказки, например «ID...»
+	args    string
+	summary string
+	// setup регистрирует флаги команды и возвращает её выполнение
+	setup func(fs *flag.FlagSet) func(inv *invocation) error
+}
+
+// invocation — вызов команды с разобранными флагами и открытым хранилищем
+type invocation struct {
+	Env
+	store *db.Store
+	// database открыта только у команд с database
+	database *db.DB
+	args     []string
+	json     bool
+	// stdin читает стандартный ввод построчно; общий для всех паролей одного вызова
+	stdin *bufio.Reader
+}
+
+// commands перечисляет команды в порядке подсказки
+var commands = []command{
+	exportTable,
+	carsAdd, carsList, carsArchive, carsRestore,
+	clientsList, clientsDelete,
+	adminsCreate, adminsResetPassword,
+	checksList,
+	reportSales,
+	importCars,
+	migrateStatus, migrateDown,
+}
+
+// IsCommand сообщает, что name — группа команд этого пакета
+func IsCommand(name string) bool {
+	for _, c := range commands {
+		if c.group == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Usage печатает список команд
+func Usage(w io.Writer) {
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-30s %s\n", strings.TrimSpace(c.title()+" "+c.args), c.summary)
+	}
+}
+
+// Run выполняет команду; args начинаются с группы, например ["cars", "list", "-status", "in_stock"]
+func Run(env Env, args []string) error {
+	cmd, rest, ok := findCommand(args)
+	if !ok {
+		fmt.Fprintln(env.Stderr, "Команды:")
+		Usage(env.Stderr)
+		return ErrUsage
+	}
+
+	fs := flag.NewFlagSet(cmd.title(), flag.ContinueOnError)
+	fs.SetOutput(env.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "%s: %s\nФлаги:\n", strings.TrimSpace(cmd.title()+" [флаги] "+cmd.args), cmd.summary)
+		fs.PrintDefaults()
+	}
+	run := cmd.setup(fs)
+	jsonOutput := new(bool)
+	if !cmd.ownOutput {
+		jsonOutput = fs.Bool("json", false, "вывести результат в JSON")
+	}
+	settings := config.AddFlags(fs)
+	if err := fs.Parse(rest); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return ErrUsage
+	}
+	if err := checkArgs(cmd, rest, fs.Args()); err != nil {
+		return err
+	}
+
+	inv := &invocation{Env: env, args: fs.Args(), json: *jsonOutput, stdin: bufio.NewReader(env.Stdin)}
+	var closeStore func() error
+	var err error
+	if cmd.database {
+		inv.database, closeStore, err = env.OpenDatabase(settings)
+	} else {
+		inv.store, closeStore, err = env.Open(settings)
+	}
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	return run(inv)
+}
+
+// findCommand находит команду по первым аргументам и возвращает оставшиеся
+func findCommand(args []string) (command, []string, bool) {
+	for _, c := range commands {
+		switch {
+		case len(args) >= 1 && c.name == "" && c.group == args[0]:
+			return c, args[1:], true
+		case len(args) >= 2 && c.name != "" && c.group == args[0] && c.name == args[1]:
+			return c, args[2:], true
+		}
+	}
+	return command{}, nil, false
+}
+
+// checkArgs проверяет аргументы, оставшиеся после флагов. Пакет flag прекращает разбор
+// на первом позиционном аргументе, и флаг после него молча не применился бы.
+// Аргумент, начинающийся с '-', допустим только после разделителя "--".
+func checkArgs(cmd command, rest, args []string) error {
+	separated := len(rest) > len(args) && rest[len(rest)-len(args)-1] == "--"
+	for _, arg := range args {
+		if separated {
+			break
+		}
+		if len(arg) > 1 && strings.HasPrefix(arg, "-") {
+			return fmt.Errorf("%w: флаг %s указан после аргументов; флаги пишутся перед ними: %s",
+				ErrUsage, arg, strings.TrimSpace(cmd.title()+" [флаги] "+cmd.args))
+		}
+	}
+	if cmd.args == "" && len(args) > 0 {
+		return fmt.Errorf("%w: команда %s не принимает аргументов, лишний аргумент %q", ErrUsage, cmd.title(), args[0])
+	}
+	return nil
+}
+
+// title возвращает команду так, как её набирают
+func (c command) title() string {
+	return strings.TrimSpace(c.group + " " + c.name)
+}
+
+// print печатает таблицу результата
+func (inv *invocation) print(table *export.Table) error {
+	if inv.json {
+		return export.Write(inv.Stdout, export.FormatJSON, table)
+	}
+	return export.WriteText(inv.Stdout, table)
+}
+
+// ids разбирает позиционные аргументы как номера записей; нужен хотя бы один
+func (inv *invocation) ids() ([]int, error) {
+	if len(inv.args) == 0 {
+		return nil, fmt.Errorf("%w: укажите номера записей", ErrUsage)
+	}
+	ids := make([]int, len(inv.args))
+	for i, arg := range inv.args {
+		id, err := strconv.Atoi(arg)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("%w: неверный номер записи %q", ErrUsage, arg)
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+// readPassword читает пароль из очередной строки стандартного ввода: в аргументах
+// он попал бы в историю команд и список процессов
+func (inv *invocation) readPassword(prompt string) (string, error) {
+	fmt.Fprint(inv.Stderr, prompt)
+	line, err := inv.stdin.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		return "", fmt.Errorf("не удалось прочитать пароль из стандартного ввода: %w", err)
+	}
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", errors.New("пароль не может быть пустым")
+	}
+	return password, nil
+}
+
+// splitList разбирает значения через запятую, пропуская пустые
+func splitList(s string) []string {
+	var values []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// names перечисляет допустимые значения через запятую для сообщений об ошибках
+func names[T ~string](values []T) string {
+	s := make([]string, len(values))
+	for i, v := range values {
+		s[i] = string(v)
+	}
+	return strings.Join(s, ", ")
+}
@@ -0,0 +1,310 @@
+package cli
+
+import (
+	"bytes"
+	"car-sales-system/internal/auth"
+	"car-sales-system/internal/config"
+	"car-sales-system/internal/db"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// run выполняет команду над хранилищем в памяти и возвращает стандартный вывод
+func run(t *testing.T, store *db.Store, stdin string, args ...string) (string, error) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	env := Env{
+		Stdin: strings.NewReader(stdin), Stdout: &stdout, Stderr: &stderr,
+		Open: func(*config.Flags) (*db.Store, func() error, error) {
+			return store, func() error { return nil }, nil
+		},
+	}
+	err := Run(env, args)
+	return stdout.String(), err
+}
+
+// runJSON выполняет команду с -json и разбирает вывод; флаг ставится перед остальными аргументами
+func runJSON(t *testing.T, store *db.Store, stdin string, args ...string) []map[string]any {
+	t.Helper()
+	out, err := run(t, store, stdin, append([]string{args[0], args[1], "-json"}, args[2:]...)...)
+	if err != nil {
+		t.Fatalf("%v: %v", args, err)
+	}
+	var rows []map[string]any
+	if err := json.Unmarshal([]byte(out), &rows); err != nil {
+		t.Fatalf("%v: вывод не JSON: %v\n%s", args, err, out)
+	}
+	return rows
+}
+
+func TestCars(t *testing.T) {
+	store := db.NewMemoryStore()
+
+	rows := runJSON(t, store, "", "cars", "add", "-brand", "Toyota", "-model", "Camry", "-year", "2020",
+		"-color", "Black", "-price", "24000", "-vin", "1hgcm82633a004352", "-fuel", "petrol")
+	if len(rows) != 1 || rows[0]["brand"] != "Toyota" || rows[0]["status"] != string(db.StatusInStock) || rows[0]["vin"] != "1HGCM82633A004352" {
+		t.Fatalf("добавленный автомобиль: %v", rows)
+	}
+	id := rows[0]["id"].(float64)
+	if _, err := run(t, store, "", "cars", "add", "-brand", "Lada", "-model", "Vesta", "-year", "1500", "-color", "Grey", "-price", "9000"); !errors.Is(err, db.ErrInvalidCar) {
+		t.Errorf("неверный год: %v", err)
+	}
+
+	out, err := run(t, store, "", "cars", "archive", "1")
+	if err != nil || !strings.Contains(out, "archived") || !strings.HasPrefix(out, "ID") {
+		t.Fatalf("архив: %v\n%s", err, out)
+	}
+	if rows := runJSON(t, store, "", "cars", "list", "-status", "in_stock"); len(rows) != 0 {
+		t.Errorf("в наличии после архивации: %v", rows)
+	}
+	if _, err := run(t, store, "", "cars", "restore", "1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := run(t, store, "", "cars", "restore", "1"); err == nil {
+		t.Error("возврат из архива автомобиля не из архива")
+	}
+	if rows := runJSON(t, store, "", "cars", "list", "-status", "in_stock,reserved"); len(rows) != 1 || rows[0]["id"] != id {
+		t.Errorf("в наличии после возврата: %v", rows)
+	}
+
+	for _, args := range [][]string{
+		{"cars", "list", "-status", "lost"},
+		{"cars", "archive"},
+		{"cars", "archive", "abc"},
+		{"cars", "fly"},
+		{"cars", "list", "-unknown"},
+		{"cars", "list", "extra"},
+		{"cars", "archive", "1", "-json"},
+	} {
+		if _, err := run(t, store, "", args...); !errors.Is(err, ErrUsage) {
+			t.Errorf("%v: %v", args, err)
+		}
+	}
+	if car, _ := store.Cars.Get(1); car.Status != db.StatusInStock {
+		t.Errorf("команда с флагом после аргументов выполнена: %s", car.Status)
+	}
+	if _, err := run(t, store, "", "cars", "archive", "--", "-1"); !errors.Is(err, ErrUsage) || strings.Contains(err.Error(), "после аргументов") {
+		t.Errorf("аргумент после --: %v", err)
+	}
+	if _, err := run(t, store, "", "cars", "archive", "1", "99"); !errors.Is(err, db.ErrNotFound) {
+		t.Errorf("архивация несуществующего автомобиля: %v", err)
+	}
+	if car, _ := store.Cars.Get(1); car.Status != db.StatusInStock {
+		t.Errorf("часть списка изменена при ошибке: %s", car.Status)
+	}
+}
+
+func TestAdminsAndClients(t *testing.T) {
+	store := db.NewMemoryStore()
+	service := auth.NewService(store)
+
+	rows := runJSON(t, store, "s3cret\n", "admins", "create", "-name", "Ольга", "-last-name", "Петрова", "-login", "olya")
+	if len(rows) != 1 || rows[0]["login"] != "olya" || rows[0]["password"] != nil {
+		t.Fatalf("новый администратор: %v", rows)
+	}
+	if _, err := service.LoginAdmin("olya", "s3cret"); err != nil {
+		t.Fatalf("вход с паролем из стандартного ввода: %v", err)
+	}
+	if _, err := run(t, store, "other\n", "admins", "create", "-name", "О", "-last-name", "П", "-login", "olya"); err == nil {
+		t.Error("повторный логин принят")
+	}
+	if _, err := run(t, store, "", "admins", "create", "-name", "О", "-last-name", "П", "-login", "empty"); err == nil {
+		t.Error("пустой пароль принят")
+	}
+	if _, err := run(t, store, "x\n", "admins", "create", "-login", "nameless"); !errors.Is(err, ErrUsage) {
+		t.Errorf("без имени: %v", err)
+	}
+
+	if _, err := run(t, store, "n3w\r\n", "admins", "reset-password", "olya"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.LoginAdmin("olya", "n3w"); err != nil {
+		t.Errorf("вход с новым паролем: %v", err)
+	}
+	if _, err := run(t, store, "n3w\n", "admins", "reset-password", "nobody"); !errors.Is(err, db.ErrNotFound) {
+		t.Errorf("сброс пароля неизвестного администратора: %v", err)
+	}
+
+	client := db.Client{Name: "Иван", LastName: "Иванов", Phone: "123", Login: "ivanov", Password: "password"}
+	if err := service.RegisterClient(&client); err != nil {
+		t.Fatal(err)
+	}
+	if rows := runJSON(t, store, "", "clients", "list"); len(rows) != 1 || rows[0]["login"] != "ivanov" || rows[0]["password"] != nil {
+		t.Errorf("клиенты: %v", rows)
+	}
+	if _, err := run(t, store, "", "clients", "delete", "1", "2"); !errors.Is(err, db.ErrNotFound) {
+		t.Errorf("удаление несуществующего клиента: %v", err)
+	}
+	if rows := runJSON(t, store, "", "clients", "delete", "1"); len(rows) != 1 || rows[0]["login"] != "ivanov" {
+		t.Errorf("удалённые клиенты: %v", rows)
+	}
+	if rows := runJSON(t, store, "", "clients", "list"); len(rows) != 0 {
+		t.Errorf("клиенты после удаления: %v", rows)
+	}
+}
+
+func TestChecksAndSalesReport(t *testing.T) {
+	store := db.NewMemoryStore()
+	client := db.Client{Name: "Иван", LastName: "Иванов", Login: "ivanov", Password: "password"}
+	if err := store.Clients.Create(&client); err != nil {
+		t.Fatal(err)
+	}
+	for _, car := range []db.Car{
+		{Brand: "BMW", Model: "X5", Year: 2021, Color: "White", Price: 50000},
+		{Brand: "BMW", Model: "X3", Year: 2020, Color: "Black", Price: 40000},
+		{Brand: "Audi", Model: "A4", Year: 2019, Color: "Red", Price: 30000},
+	} {
+		if err := store.Cars.Create(&car); err != nil {
+			t.Fatal(err)
+		}
+		check, err := store.Purchases.Purchase(client.ID, car.ID, car.Price)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if car.Model != "A4" {
+			if _, err := store.Purchases.Approve(check.ID, 0); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if rows := runJSON(t, store, "", "checks", "list", "-status", "pending"); len(rows) != 1 || rows[0]["price"] != 30000.0 {
+		t.Errorf("ожидающие чеки: %v", rows)
+	}
+	if rows := runJSON(t, store, "", "checks", "list", "-to", "2000-01-01"); len(rows) != 0 {
+		t.Errorf("чеки до 2000 года: %v", rows)
+	}
+	if _, err := run(t, store, "", "checks", "list", "-from", "вчера"); !errors.Is(err, ErrUsage) {
+		t.Errorf("неверная дата: %v", err)
+	}
+
+	rows := runJSON(t, store, "", "report", "sales", "-by", "model")
+	if len(rows) != 3 || rows[0]["group"] != "BMW X5" || rows[2]["group"] != "Итого" || rows[2]["units"] != 2.0 || rows[2]["revenue"] != 90000.0 {
+		t.Errorf("отчёт по моделям: %v", rows)
+	}
+	if rows := runJSON(t, store, "", "report", "sales", "-from", "2000-01-01", "-to", "2000-12-31"); len(rows) != 1 || rows[0]["units"] != 0.0 {
+		t.Errorf("отчёт за 2000 год: %v", rows)
+	}
+	for _, args := range [][]string{{"report", "sales", "-by", "price"}, {"report", "sales", "-sort", "name"}} {
+		if _, err := run(t, store, "", args...); !errors.Is(err, ErrUsage) {
+			t.Errorf("%v: %v", args, err)
+		}
+	}
+}
+
+func TestExport(t *testing.T) {
+	store := db.NewMemoryStore()
+	car := db.Car{Brand: "Toyota", Model: "Camry", Year: 2020, Color: "Black", Price: 24000}
+	if err := store.Cars.Create(&car); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := run(t, store, "", "export", "-status", "in_stock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(out), "\n"); len(lines) != 2 || !strings.HasPrefix(strings.TrimPrefix(lines[0], "\ufeff"), "id,brand") || !strings.Contains(lines[1], "Toyota") {
+		t.Errorf("выгрузка CSV:\n%s", out)
+	}
+
+	path := t.TempDir() + "/cars.json"
+	if _, err := run(t, store, "", "export", "-o", path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rows []map[string]any
+	if err := json.Unmarshal(data, &rows); err != nil || len(rows) != 1 {
+		t.Errorf("выгрузка JSON по расширению файла: %v\n%s", err, data)
+	}
+
+	for _, args := range [][]string{
+		{"export", "-json"},
+		{"export", "-table", "orders"},
+		{"export", "-format", "pdf"},
+		{"export", "-status", "pending"},
+		{"export", "-from", "2024-01-01"},
+	} {
+		if _, err := run(t, store, "", args...); !errors.Is(err, ErrUsage) {
+			t.Errorf("%v: %v", args, err)
+		}
+	}
+}
+
+func TestMigrateAndImport(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cars.db")
+	var stdout, stderr bytes.Buffer
+	env := Env{
+		Stdin: strings.NewReader(""), Stdout: &stdout, Stderr: &stderr,
+		Open: func(*config.Flags) (*db.Store, func() error, error) {
+			database, err := db.InitializeDatabase(path)
+			if err != nil {
+				return nil, nil, err
+			}
+			return db.NewSQLStore(database), database.Close, nil
+		},
+		OpenDatabase: func(*config.Flags) (*db.DB, func() error, error) {
+			database, err := db.OpenDatabase(path)
+			if err != nil {
+				return nil, nil, err
+			}
+			return database, database.Close, nil
+		},
+	}
+	runSQL := func(args ...string) string {
+		t.Helper()
+		stdout.Reset()
+		if err := Run(env, args); err != nil {
+			t.Fatalf("%v: %v", args, err)
+		}
+		return stdout.String()
+	}
+
+	// Состояние миграций не применяет их
+	out := runSQL("migrate", "status", "-json")
+	var statuses []map[string]any
+	if err := json.Unmarshal([]byte(out), &statuses); err != nil || len(statuses) == 0 {
+		t.Fatalf("состояние миграций: %v\n%s", err, out)
+	}
+	for _, s := range statuses {
+		if s["state"] != "не применена" {
+			t.Errorf("миграция применена без команды: %v", s)
+		}
+	}
+
+	file := filepath.Join(dir, "cars.json")
+	data := `[{"brand": "Toyota", "model": "Corolla", "year": 2019, "color": "White", "price": 15000}, {"brand": "Toyota"}]`
+	if err := os.WriteFile(file, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if out := runSQL("import", "-dry-run", file); !strings.Contains(out, "Принято записей: 1, отклонено: 1") || !strings.Contains(out, "не изменена") {
+		t.Errorf("пробный импорт:\n%s", out)
+	}
+	if out := runSQL("import", file); !strings.Contains(out, "Добавлено автомобилей: 1") {
+		t.Errorf("импорт:\n%s", out)
+	}
+
+	statuses = nil
+	json.Unmarshal([]byte(runSQL("migrate", "status", "-json")), &statuses)
+	last := statuses[len(statuses)-1]
+	if last["state"] != "применена" {
+		t.Errorf("после импорта миграции применены: %v", last)
+	}
+	stderr.Reset()
+	runSQL("migrate", "down")
+	if want := fmt.Sprintf("Откачена миграция %v.", last["version"]); !strings.Contains(stderr.String(), want) {
+		t.Errorf("откат: %q, ожидалось %q", stderr.String(), want)
+	}
+
+	if err := Run(env, []string{"import", "a.json", "b.json"}); !errors.Is(err, ErrUsage) {
+		t.Errorf("два файла импорта: %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"car-sales-system/internal/db"
+	"car-sales-system/internal/export"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+)
+
+var exportTable = command{
+	group: "export", ownOutput: true,
+	summary: "выгрузить таблицу в CSV, JSON или XLSX",
+	setup: func(fs *flag.FlagSet) func(*invocation) error {
+		dataset := fs.String("table", string(export.Cars), "что выгрузить: "+names(export.Datasets))
+		formatName := fs.String("format", "", "формат: csv, json или xlsx; по умолчанию — по расширению файла -o или csv")
+		output := fs.String("o", "", "файл выгрузки; без него данные выводятся в стандартный вывод")
+		statuses := fs.String("status", "", "статусы автомобилей или чеков через запятую")
+		fromDate := fs.String("from", "", "чеки, оформленные начиная с даты ГГГГ-ММ-ДД")
+		toDate := fs.String("to", "", "чеки, оформленные по дату ГГГГ-ММ-ДД включительно")
+
+		return func(inv *invocation) error {
+			format := export.FormatCSV
+			var err error
+			switch {
+			case *formatName != "":
+				format, err = export.ParseFormat(*formatName)
+			case *output != "":
+				format, err = export.FormatFromPath(*output)
+			}
+			if err != nil {
+				return fmt.Errorf("%w: %w", ErrUsage, err)
+			}
+			filter, err := exportFilter(export.Dataset(*dataset), *statuses, *fromDate, *toDate)
+			if err != nil {
+				return err
+			}
+
+			table, err := export.Build(inv.store, export.Dataset(*dataset), filter)
+			if err != nil {
+				return err
+			}
+			if *output == "" {
+				return export.Write(inv.Stdout, format, table)
+			}
+			file, err := os.Create(*output)
+			if err != nil {
+				return err
+			}
+			if err := export.Write(file, format, table); err != nil {
+				file.Close()
+				return err
+			}
+			if err := file.Close(); err != nil {
+				return err
+			}
+			fmt.Fprintf(inv.Stderr, "Выгружено записей: %d в %s.\n", len(table.Rows), *output)
+			return nil
+		}
+	},
+}
+
+// exportFilter собирает условия выгрузки из флагов.
+// Статусы проверяются по списку статусов выгружаемой таблицы.
+func exportFilter(dataset export.Dataset, statuses, fromDate, toDate string) (export.Filter, error) {
+	var filter export.Filter
+	if !slices.Contains(export.Datasets, dataset) {
+		return filter, fmt.Errorf("%w: неизвестная таблица %q, допустимы: %s", ErrUsage, dataset, names(export.Datasets))
+	}
+	for _, name := range splitList(statuses) {
+		switch {
+		case dataset == export.Cars && slices.Contains(db.CarStatuses, db.CarStatus(name)):
+			filter.CarStatuses = append(filter.CarStatuses, db.CarStatus(name))
+		case dataset == export.Checks && slices.Contains(db.CheckStatuses, db.CheckStatus(name)):
+			filter.CheckStatuses = append(filter.CheckStatuses, db.CheckStatus(name))
+		default:
+			return filter, fmt.Errorf("%w: статус %q не подходит для выгрузки %s", ErrUsage, name, dataset)
+		}
+	}
+
+	if (fromDate != "" || toDate != "") && dataset != export.Checks {
+		return filter, fmt.Errorf("%w: период задаётся только для выгрузки чеков", ErrUsage)
+	}
+	var err error
+	filter.From, filter.To, err = dateRange(fromDate, toDate)
+	return filter, err
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"car-sales-system/internal/export"
+	"car-sales-system/internal/importer"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// importColumns — столбцы отчёта о проверке импортируемого файла
+var importColumns = []export.Column{
+	{Key: "row", Title: "Запись"}, {Key: "car", Title: "Автомобиль"}, {Key: "result", Title: "Результат"},
+}
+
+var importCars = command{
+	group: "import", ownOutput: true, args: "FILE",
+	summary: "проверить файл CSV или JSON с автомобилями и добавить принятые записи одной транзакцией",
+	setup: func(fs *flag.FlagSet) func(*invocation) error {
+		dryRun := fs.Bool("dry-run", false, "только проверить файл, ничего не добавляя в базу")
+
+		return func(inv *invocation) error {
+			if len(inv.args) != 1 {
+				return fmt.Errorf("%w: укажите один файл для импорта", ErrUsage)
+			}
+			path := inv.args[0]
+			format, err := importer.FormatFromPath(path)
+			if err != nil {
+				return fmt.Errorf("%w: %w", ErrUsage, err)
+			}
+			file, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+
+			report, err := importer.Prepare(file, format, inv.store.Cars)
+			if err != nil {
+				return err
+			}
+			table := &export.Table{Dataset: "import", Columns: importColumns}
+			for _, row := range report.Rows {
+				result := "принята"
+				if row.Err != nil {
+					result = "отклонена: " + row.Err.Error()
+				}
+				table.Rows = append(table.Rows, []any{row.Number, row.Title(), result})
+			}
+			if err := export.WriteText(inv.Stdout, table); err != nil {
+				return err
+			}
+			if len(report.Ignored) > 0 {
+				fmt.Fprintf(inv.Stdout, "Не распознаны и пропущены столбцы: %s.\n", strings.Join(report.Ignored, ", "))
+			}
+			fmt.Fprintf(inv.Stdout, "Принято записей: %d, отклонено: %d.\n", len(report.Accepted()), len(report.Rejected()))
+
+			if *dryRun {
+				fmt.Fprintln(inv.Stdout, "Пробный запуск: база данных не изменена.")
+				return nil
+			}
+			n, err := importer.Import(report, inv.store.Cars)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(inv.Stdout, "Добавлено автомобилей: %d.\n", n)
+			return nil
+		}
+	},
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"car-sales-system/internal/db"
+	"car-sales-system/internal/export"
+	"flag"
+	"fmt"
+)
+
+// migrationColumns — столбцы таблицы состояния миграций
+var migrationColumns = []export.Column{
+	{Key: "version", Title: "Версия"}, {Key: "name", Title: "Название"},
+	{Key: "state", Title: "Состояние"}, {Key: "applied_at", Title: "Применена"},
+}
+
+var migrateStatus = command{
+	group: "migrate", name: "status", database: true,
+	summary: "состояние миграций базы данных; сама команда миграции не применяет",
+	setup: func(*flag.FlagSet) func(*invocation) error {
+		return func(inv *invocation) error {
+			return inv.printMigrations()
+		}
+	},
+}
+
+var migrateDown = command{
+	group: "migrate", name: "down", database: true,
+	summary: "откатить последнюю применённую миграцию и показать состояние миграций",
+	setup: func(*flag.FlagSet) func(*invocation) error {
+		return func(inv *invocation) error {
+			version, err := db.RollbackLast(inv.database)
+			if err != nil {
+				return err
+			}
+			if version == 0 {
+				fmt.Fprintln(inv.Stderr, "Нет применённых миграций для отката.")
+			} else {
+				fmt.Fprintf(inv.Stderr, "Откачена миграция %d.\n", version)
+			}
+			return inv.printMigrations()
+		}
+	},
+}
+
+// printMigrations печатает состояние всех миграций базы
+func (inv *invocation) printMigrations() error {
+	statuses, err := db.MigrationsStatus(inv.database)
+	if err != nil {
+		return err
+	}
+	table := &export.Table{Dataset: "migrations", Columns: migrationColumns}
+	for _, s := range statuses {
+		var state string
+		var appliedAt any
+		if s.Applied {
+			state, appliedAt = "применена", s.AppliedAt
+		} else {
+			state = "не применена"
+		}
+		table.Rows = append(table.Rows, []any{s.Version, s.Name, state, appliedAt})
+	}
+	return inv.print(table)
+}
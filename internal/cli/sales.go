@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"car-sales-system/internal/analytics"
+	"car-sales-system/internal/db"
+	"car-sales-system/internal/export"
+	"flag"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+var checksList = command{
+	group: "checks", name: "list",
+	summary: "список чеков",
+	setup: func(fs *flag.FlagSet) func(*invocation) error {
+		statuses := fs.String("status", "", "статусы через запятую: "+names(db.CheckStatuses)+"; по умолчанию все")
+		fromDate := fs.String("from", "", "чеки, оформленные начиная с даты ГГГГ-ММ-ДД")
+		toDate := fs.String("to", "", "чеки, оформленные по дату ГГГГ-ММ-ДД включительно")
+
+		return func(inv *invocation) error {
+			var filter db.CheckFilter
+			for _, name := range splitList(*statuses) {
+				if !slices.Contains(db.CheckStatuses, db.CheckStatus(name)) {
+					return fmt.Errorf("%w: неизвестный статус чека %q, допустимы: %s", ErrUsage, name, names(db.CheckStatuses))
+				}
+				filter.Statuses = append(filter.Statuses, db.CheckStatus(name))
+			}
+			var err error
+			if filter.From, filter.To, err = dateRange(*fromDate, *toDate); err != nil {
+				return err
+			}
+			checks, err := inv.store.Checks.List(filter)
+			if err != nil {
+				return err
+			}
+			return inv.print(export.CheckTable(checks))
+		}
+	},
+}
+
+// salesColumns — столбцы отчёта о продажах; последняя строка отчёта — итог
+var salesColumns = []export.Column{
+	{Key: "group", Title: "Группа"}, {Key: "units", Title: "Продано"}, {Key: "revenue", Title: "Выручка"},
+	{Key: "average", Title: "Средняя цена"}, {Key: "discount", Title: "Скидка"}, {Key: "discount_percent", Title: "Скидка, %"},
+}
+
+var reportSales = command{
+	group: "report", name: "sales",
+	summary: "продажи по группам с итогом, как на вкладке аналитики",
+	setup: func(fs *flag.FlagSet) func(*invocation) error {
+		fromDate := fs.String("from", "", "продажи, подтверждённые начиная с даты ГГГГ-ММ-ДД")
+		toDate := fs.String("to", "", "продажи, подтверждённые по дату ГГГГ-ММ-ДД включительно")
+		groupBy := fs.String("by", string(analytics.ByBrand), "группировка: "+names(analytics.Groupings))
+		sortBy := fs.String("sort", string(analytics.SortRevenue), "показатель сортировки: "+names(analytics.SortOrders))
+		ascending := fs.Bool("asc", false, "упорядочить по возрастанию показателя")
+		limit := fs.Int("limit", 0, "число групп в отчёте, 0 — все")
+		archived := fs.Bool("archived", false, "учитывать продажи архивных и удалённых из базы автомобилей")
+
+		return func(inv *invocation) error {
+			query := analytics.SalesQuery{
+				GroupBy: analytics.GroupBy(*groupBy), SortBy: analytics.SortBy(*sortBy),
+				Ascending: *ascending, Limit: *limit, IncludeArchived: *archived,
+			}
+			if !slices.Contains(analytics.Groupings, query.GroupBy) {
+				return fmt.Errorf("%w: неизвестная группировка %q, допустимы: %s", ErrUsage, query.GroupBy, names(analytics.Groupings))
+			}
+			if !slices.Contains(analytics.SortOrders, query.SortBy) {
+				return fmt.Errorf("%w: неизвестный показатель %q, допустимы: %s", ErrUsage, query.SortBy, names(analytics.SortOrders))
+			}
+			if query.Limit < 0 {
+				return fmt.Errorf("%w: -limit не может быть отрицательным", ErrUsage)
+			}
+			from, to, err := dateRange(*fromDate, *toDate)
+			if err != nil {
+				return err
+			}
+
+			sales, err := inv.store.Checks.ListSales(query.IncludeArchived)
+			if err != nil {
+				return err
+			}
+			if !from.IsZero() || !to.IsZero() {
+				// Продажа относится к дню подтверждения; продажи без времени подтверждения в период не попадают
+				sales = slices.DeleteFunc(sales, func(s db.Sale) bool {
+					return s.DecidedAt.IsZero() || s.DecidedAt.Before(from) || (!to.IsZero() && !s.DecidedAt.Before(to))
+				})
+			}
+
+			table := &export.Table{Dataset: "sales", Columns: salesColumns}
+			for _, row := range append(analytics.GroupSales(sales, query), analytics.TotalSales(sales)) {
+				table.Rows = append(table.Rows, []any{
+					row.Group, row.Units, round(row.Revenue), round(row.AveragePrice()),
+					round(row.Discount()), round(row.DiscountPercent()),
+				})
+			}
+			return inv.print(table)
+		}
+	},
+}
+
+// dateRange разбирает даты ГГГГ-ММ-ДД в промежуток [from, to) по местному времени;
+// пустая дата оставляет границу открытой
+func dateRange(fromDate, toDate string) (from, to time.Time, err error) {
+	var first, last time.Time
+	for _, d := range []struct {
+		text string
+		dest *time.Time
+	}{{fromDate, &first}, {toDate, &last}} {
+		if d.text == "" {
+			continue
+		}
+		t, err := time.ParseInLocation("2006-01-02", d.text, time.Local)
+		if err != nil {
+			return from, to, fmt.Errorf("%w: неверная дата %q, используйте формат ГГГГ-ММ-ДД", ErrUsage, d.text)
+		}
+		*d.dest = t
+	}
+	return export.DayRange(first, last)
+}
+
+// round округляет сумму до копеек
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"car-sales-system/internal/auth"
+	"car-sales-system/internal/db"
+	"car-sales-system/internal/export"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+var clientsList = command{
+	group: "clients", name: "list",
+	summary: "список клиентов",
+	setup: func(*flag.FlagSet) func(*invocation) error {
+		return func(inv *invocation) error {
+			clients, err := inv.store.Clients.List()
+			if err != nil {
+				return err
+			}
+			return inv.print(export.ClientTable(clients))
+		}
+	},
+}
+
+var clientsDelete = command{
+	group: "clients", name: "delete", args: "ID...",
+	summary: "удалить клиентов вместе с их чеками; забронированные ими автомобили возвращаются в продажу",
+	setup: func(*flag.FlagSet) func(*invocation) error {
+		return func(inv *invocation) error {
+			ids, err := inv.ids()
+			if err != nil {
+				return err
+			}
+			clients, err := inv.store.Clients.List()
+			if err != nil {
+				return err
+			}
+			byID := make(map[int]db.Client, len(clients))
+			for _, c := range clients {
+				byID[c.ID] = c
+			}
+
+			// Сначала проверяются все номера, чтобы опечатка не оставила часть клиентов удалённой
+			deleted := make([]db.Client, len(ids))
+			for i, id := range ids {
+				client, ok := byID[id]
+				if !ok {
+					return fmt.Errorf("клиент %d: %w", id, db.ErrNotFound)
+				}
+				deleted[i] = client
+			}
+			for _, id := range ids {
+				if err := inv.store.Clients.Delete(id); err != nil {
+					return fmt.Errorf("клиент %d: %w", id, err)
+				}
+			}
+			return inv.print(export.ClientTable(deleted))
+		}
+	},
+}
+
+var adminsCreate = command{
+	group: "admins", name: "create",
+	summary: "добавить администратора; пароль читается из стандартного ввода",
+	setup: func(fs *flag.FlagSet) func(*invocation) error {
+		var admin db.Admin
+		fs.StringVar(&admin.Name, "name", "", "имя")
+		fs.StringVar(&admin.LastName, "last-name", "", "фамилия")
+		fs.StringVar(&admin.Phone, "phone", "", "телефон")
+		fs.StringVar(&admin.Login, "login", "", "логин")
+
+		return func(inv *invocation) error {
+			admin.Name = strings.TrimSpace(admin.Name)
+			admin.LastName = strings.TrimSpace(admin.LastName)
+			admin.Login = strings.TrimSpace(admin.Login)
+			if admin.Name == "" || admin.LastName == "" || admin.Login == "" {
+				return fmt.Errorf("%w: флаги -name, -last-name и -login обязательны", ErrUsage)
+			}
+			_, err := inv.store.Admins.GetByLogin(admin.Login)
+			if err == nil {
+				return fmt.Errorf("администратор с логином %q уже есть", admin.Login)
+			}
+			if !errors.Is(err, db.ErrNotFound) {
+				return err
+			}
+
+			password, err := inv.readPassword("Пароль администратора: ")
+			if err != nil {
+				return err
+			}
+			if admin.Password, err = auth.HashPassword(password); err != nil {
+				return err
+			}
+			if err := inv.store.Admins.Create(&admin); err != nil {
+				return err
+			}
+			admin.Password = ""
+			return inv.print(export.AdminTable([]db.Admin{admin}))
+		}
+	},
+}
+
+var adminsResetPassword = command{
+	group: "admins", name: "reset-password", args: "LOGIN",
+	summary: "задать администратору новый пароль из стандартного ввода",
+	setup: func(*flag.FlagSet) func(*invocation) error {
+		return func(inv *invocation) error {
+			if len(inv.args) != 1 {
+				return fmt.Errorf("%w: укажите логин администратора", ErrUsage)
+			}
+			admin, err := inv.store.Admins.GetByLogin(inv.args[0])
+			if err != nil {
+				return fmt.Errorf("администратор %q: %w", inv.args[0], err)
+			}
+
+			password, err := inv.readPassword("Новый пароль: ")
+			if err != nil {
+				return err
+			}
+			hash, err := auth.HashPassword(password)
+			if err != nil {
+				return err
+			}
+			if err := inv.store.Admins.UpdatePassword(admin.ID, hash); err != nil {
+				return err
+			}
+			admin.Password = ""
+			return inv.print(export.AdminTable([]db.Admin{*admin}))
+		}
+	},
+}
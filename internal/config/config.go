@@ -0,0 +1,241 @@
+// Package config собирает настройки приложения из файла TOML, переменных окружения
+// и флагов командной строки. Каждый следующий источник переопределяет предыдущий:
+// значения по умолчанию < файл < окружение < флаги.
+package config
+
+import (
+	"car-sales-system/internal/db"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Config — настройки приложения
+type Config struct {
+	Database Database `toml:"database"`
+	Cars     Cars     `toml:"cars"`
+	UI       UI       `toml:"ui"`
+	Log      Log      `toml:"log"`
+	Server   Server   `toml:"server"`
+}
+
+// Database — расположение базы данных
+type Database struct {
+	// Path — путь к файлу SQLite или адрес PostgreSQL вида postgres://пользователь:пароль@сервер/база.
+	// Относительный путь в файле настроек отсчитывается от каталога этого файла,
+	// в окружении и флагах — от текущего каталога. Если путь в файле не указан,
+	// база лежит рядом с файлом настроек.
+	Path string `toml:"path"`
+}
+
+// Cars — правила проверки данных автомобиля
+type Cars struct {
+	MinYear int `toml:"min_year"`
+	MaxYear int `toml:"max_year"`
+}
+
+// UI — настройки интерфейса
+type UI struct {
+	// Currency — обозначение валюты в ценах
+	Currency string `toml:"currency"`
+}
+
+// Log — настройки журнала
+type Log struct {
+	// Level — наименьший уровень выводимых сообщений: debug, info, warn или error
+	Level string `toml:"level"`
+}
+
+// Server — работа через сервер carsales-server
+type Server struct {
+	// Listen — адрес, на котором carsales-server принимает запросы
+	Listen string `toml:"listen"`
+	// URL — адрес сервера вида http://сервер:8080. Если он задан, программа работает
+	// с базой через сервер, а настройки базы и года выпуска действуют только на сервере.
+	URL string `toml:"url"`
+}
+
+// Переменные окружения с настройками
+const (
+	EnvFile     = "CARSALES_CONFIG"
+	EnvDBPath   = "CARSALES_DB_PATH"
+	EnvMinYear  = "CARSALES_MIN_CAR_YEAR"
+	EnvMaxYear  = "CARSALES_MAX_CAR_YEAR"
+	EnvCurrency = "CARSALES_CURRENCY"
+	EnvLogLevel = "CARSALES_LOG_LEVEL"
+	EnvListen   = "CARSALES_LISTEN"
+	EnvServer   = "CARSALES_SERVER"
+)
+
+// FileName — имя файла настроек, который ищется рядом с программой и в каталоге настроек пользователя
+const FileName = "carsales.toml"
+
+// DatabaseFileName — имя файла базы SQLite по умолчанию
+const DatabaseFileName = "carssale.db"
+
+// userDir — каталог программы в каталоге настроек пользователя
+const userDir = "car-sales"
+
+// Default возвращает настройки по умолчанию
+func Default() Config {
+	return Config{
+		Database: Database{Path: DefaultDatabasePath()},
+		Cars:     Cars{MinYear: db.MinCarYear, MaxYear: db.MaxCarYear},
+		UI:       UI{Currency: "Р"},
+		Log:      Log{Level: "info"},
+		Server:   Server{Listen: ":8080"},
+	}
+}
+
+// ReadFile читает настройки из файла TOML поверх cfg. Незнакомые ключи считаются ошибкой,
+// чтобы опечатка в названии настройки не оставалась незамеченной. Если путь к базе
+// в файле не указан, база лежит рядом с файлом.
+func ReadFile(path string, cfg *Config) error {
+	cfg.Database.Path = ""
+	meta, err := toml.DecodeFile(path, cfg)
+	if err != nil {
+		return fmt.Errorf("ошибка чтения файла настроек %s: %w", path, err)
+	}
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, len(undecoded))
+		for i, key := range undecoded {
+			keys[i] = key.String()
+		}
+		return fmt.Errorf("неизвестные настройки в файле %s: %s", path, strings.Join(keys, ", "))
+	}
+	switch {
+	case cfg.Database.Path == "":
+		cfg.Database.Path = filepath.Join(filepath.Dir(path), DatabaseFileName)
+	case db.DialectFor(cfg.Database.Path) == db.SQLite && !filepath.IsAbs(cfg.Database.Path):
+		cfg.Database.Path = filepath.Join(filepath.Dir(path), cfg.Database.Path)
+	}
+	return nil
+}
+
+// ApplyEnv переопределяет настройки заданными переменными окружения; lookup — обычно os.LookupEnv
+func ApplyEnv(cfg *Config, lookup func(string) (string, bool)) error {
+	for _, s := range cfg.settings() {
+		if value, ok := lookup(s.env); ok {
+			if err := s.set(value); err != nil {
+				return fmt.Errorf("переменная %s: %w", s.env, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Validate проверяет согласованность настроек
+func (c Config) Validate() error {
+	switch {
+	case strings.TrimSpace(c.Database.Path) == "":
+		return errors.New("не указан путь к базе данных")
+	case c.Cars.MinYear <= 0 || c.Cars.MinYear > c.Cars.MaxYear:
+		return fmt.Errorf("неверный диапазон года выпуска: от %d до %d", c.Cars.MinYear, c.Cars.MaxYear)
+	case strings.TrimSpace(c.UI.Currency) == "":
+		return errors.New("не указано обозначение валюты")
+	case strings.TrimSpace(c.Server.Listen) == "":
+		return errors.New("не указан адрес, на котором сервер принимает запросы")
+	}
+	if _, err := c.LogLevel(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ErrServerConfigured возвращается командами, которые открывают базу напрямую, если задан адрес сервера
+var ErrServerConfigured = errors.New("команда работает с базой напрямую и недоступна при работе через сервер")
+
+// LocalDatabase возвращает путь к базе для команд, которые открывают её напрямую: миграций,
+// импорта, выгрузки и администрирования. При заданном адресе сервера такая команда открыла бы
+// не общую базу, а локальный файл, поэтому возвращается ErrServerConfigured.
+func (c Config) LocalDatabase() (string, error) {
+	if c.Server.URL != "" {
+		return "", fmt.Errorf("%w (%s): выполните её на сервере или сбросьте адрес флагом -server=", ErrServerConfigured, c.Server.URL)
+	}
+	return c.Database.Path, nil
+}
+
+// LogLevel возвращает уровень журнала для log/slog
+func (c Config) LogLevel() (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Log.Level)); err != nil {
+		return 0, fmt.Errorf("неизвестный уровень журнала %q, используйте debug, info, warn или error", c.Log.Level)
+	}
+	return level, nil
+}
+
+// setting — настройка, которую можно задать строкой из окружения или флага
+type setting struct {
+	env, flag, usage string
+	set              func(string) error
+}
+
+// settings перечисляет настройки, задаваемые окружением и флагами, с записью прямо в c
+func (c *Config) settings() []setting {
+	text := func(dest *string) func(string) error {
+		return func(v string) error {
+			*dest = strings.TrimSpace(v)
+			return nil
+		}
+	}
+	number := func(dest *int) func(string) error {
+		return func(v string) error {
+			n, err := strconv.Atoi(strings.TrimSpace(v))
+			if err != nil {
+				return fmt.Errorf("ожидается целое число: %q", v)
+			}
+			*dest = n
+			return nil
+		}
+	}
+	return []setting{
+		{EnvDBPath, "db", "путь к файлу базы SQLite или адрес postgres://…", text(&c.Database.Path)},
+		{EnvMinYear, "min-year", "наименьший допустимый год выпуска автомобиля", number(&c.Cars.MinYear)},
+		{EnvMaxYear, "max-year", "наибольший допустимый год выпуска автомобиля", number(&c.Cars.MaxYear)},
+		{EnvCurrency, "currency", "обозначение валюты в ценах", text(&c.UI.Currency)},
+		{EnvLogLevel, "log-level", "уровень журнала: debug, info, warn или error", text(&c.Log.Level)},
+		{EnvListen, "listen", "адрес, на котором carsales-server принимает запросы", text(&c.Server.Listen)},
+		{EnvServer, "server", "адрес сервера carsales-server вида http://сервер:8080; без него база открывается напрямую", text(&c.Server.URL)},
+	}
+}
+
+// DefaultDatabasePath возвращает расположение базы, если файла настроек нет: файл в каталоге
+// программы в настройках пользователя, там же, где ищется carsales.toml. Оно не зависит
+// от текущего каталога, поэтому программа находит базу, откуда бы её ни запустили.
+// Если каталог пользователя неизвестен, база лежит рядом с программой.
+func DefaultDatabasePath() string {
+	if dir, err := os.UserConfigDir(); err == nil {
+		return filepath.Join(dir, userDir, DatabaseFileName)
+	}
+	if exe, err := os.Executable(); err == nil {
+		return filepath.Join(filepath.Dir(exe), DatabaseFileName)
+	}
+	return DatabaseFileName
+}
+
+// FindFile возвращает путь к файлу настроек: из переменной CARSALES_CONFIG, иначе carsales.toml
+// рядом с программой или в каталоге настроек пользователя. Пустая строка — файла нет.
+func FindFile() string {
+	if path, ok := os.LookupEnv(EnvFile); ok {
+		return path
+	}
+	var candidates []string
+	if exe, err := os.Executable(); err == nil {
+		candidates = append(candidates, filepath.Join(filepath.Dir(exe), FileName))
+	}
+	if dir, err := os.UserConfigDir(); err == nil {
+		candidates = append(candidates, filepath.Join(dir, userDir, FileName))
+	}
+	for _, path := range candidates {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
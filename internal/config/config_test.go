@@ -0,0 +1,178 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), FileName)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	path := writeFile(t, `
+[database]
+path = "data/cars.db"
+
+[cars]
+min_year = 1950
+max_year = 2030
+
+[ui]
+currency = "USD"
+
+[server]
+listen = "127.0.0.1:9000"
+`)
+	t.Setenv(EnvFile, path)
+	t.Setenv(EnvMaxYear, "2040")
+	t.Setenv(EnvCurrency, "EUR")
+	t.Setenv(EnvServer, "http://cars.local:8080")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags := AddFlags(fs)
+	if err := fs.Parse([]string{"-currency", "₸", "-log-level", "debug"}); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := flags.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Config{
+		Database: Database{Path: filepath.Join(filepath.Dir(path), "data", "cars.db")},
+		Cars:     Cars{MinYear: 1950, MaxYear: 2040},
+		UI:       UI{Currency: "₸"},
+		Log:      Log{Level: "debug"},
+		Server:   Server{Listen: "127.0.0.1:9000", URL: "http://cars.local:8080"},
+	}
+	if cfg != want {
+		t.Errorf("настройки %+v, ожидались %+v", cfg, want)
+	}
+}
+
+func TestPostgresAddressIsNotResolved(t *testing.T) {
+	dsn := "postgres://carsales@db.local/carsales?sslmode=disable"
+	cfg := Default()
+	if err := ReadFile(writeFile(t, "[database]\npath = \""+dsn+"\"\n"), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Database.Path != dsn {
+		t.Errorf("адрес базы %q, ожидался %q", cfg.Database.Path, dsn)
+	}
+}
+
+func TestLoadDefaults(t *testing.T) {
+	path := writeFile(t, "")
+	t.Setenv(EnvFile, path)
+	flags := AddFlags(flag.NewFlagSet("test", flag.ContinueOnError))
+	cfg, err := flags.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Без пути в файле база лежит рядом с файлом настроек
+	want := Default()
+	want.Database.Path = filepath.Join(filepath.Dir(path), DatabaseFileName)
+	if cfg != want {
+		t.Errorf("настройки %+v, ожидались настройки по умолчанию %+v", cfg, want)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name, file string
+		env        map[string]string
+		args       []string
+		want       string
+	}{
+		{name: "опечатка в файле", file: "[ui]\ncurrensy = \"USD\"\n", want: "ui.currensy"},
+		{name: "год в окружении", env: map[string]string{EnvMinYear: "давно"}, want: EnvMinYear},
+		{name: "год во флаге", args: []string{"-max-year", "x"}, want: "-max-year"},
+		{name: "диапазон лет", args: []string{"-min-year", "2030", "-max-year", "2000"}, want: "диапазон года"},
+		{name: "удалённая настройка языка", file: "[ui]\nlanguage = \"en\"\n", want: "ui.language"},
+		{name: "уровень журнала", args: []string{"-log-level", "verbose"}, want: "уровень журнала"},
+		{name: "пустая валюта", args: []string{"-currency", " "}, want: "валюты"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvFile, writeFile(t, tt.file))
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			flags := AddFlags(fs)
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			_, err := flags.Load()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ошибка %v, ожидалось упоминание %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalDatabase(t *testing.T) {
+	t.Setenv(EnvFile, writeFile(t, "[server]\nurl = \"http://host:8080\"\n"))
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags := AddFlags(fs)
+	if err := fs.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := flags.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cfg.LocalDatabase(); !errors.Is(err, ErrServerConfigured) {
+		t.Errorf("сервер из файла: ожидалась ErrServerConfigured, получено %v", err)
+	}
+
+	// Пустой -server отменяет сервер из файла, и команда открывает базу напрямую
+	if err := fs.Parse([]string{"-server="}); err != nil {
+		t.Fatal(err)
+	}
+	if cfg, err = flags.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if path, err := cfg.LocalDatabase(); err != nil || path != cfg.Database.Path {
+		t.Errorf("без сервера: %q, %v", path, err)
+	}
+}
+
+func TestDefaultDatabaseDoesNotDependOnWorkingDirectory(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv("AppData", filepath.Join(home, "AppData"))
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	path := DefaultDatabasePath()
+	if !filepath.IsAbs(path) || !strings.HasPrefix(path, home) || filepath.Base(path) != DatabaseFileName {
+		t.Fatalf("база по умолчанию: %s", path)
+	}
+
+	// База из текущего каталога, оставшаяся от прежних версий, не подхватывается
+	if err := os.WriteFile(DatabaseFileName, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if cfg := Default(); cfg.Database.Path != path {
+		t.Errorf("база по умолчанию при базе в текущем каталоге: %s", cfg.Database.Path)
+	}
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+// Flags — флаги командной строки с настройками, зарегистрированные в наборе флагов
+type Flags struct {
+	fs   *flag.FlagSet
+	file string
+}
+
+// AddFlags регистрирует флаг -config и флаги отдельных настроек в fs
+func AddFlags(fs *flag.FlagSet) *Flags {
+	f := &Flags{fs: fs}
+	fs.StringVar(&f.file, "config", "", "файл настроек TOML; по умолчанию "+EnvFile+" или "+FileName+
+		" рядом с программой или в каталоге настроек пользователя")
+	for _, s := range (&Config{}).settings() {
+		fs.String(s.flag, "", s.usage+" (переменная "+s.env+")")
+	}
+	return f
+}
+
+// Load собирает настройки из всех источников после разбора флагов и проверяет их
+func (f *Flags) Load() (Config, error) {
+	cfg := Default()
+	path := f.file
+	if path == "" {
+		path = FindFile()
+	}
+	if path != "" {
+		if err := ReadFile(path, &cfg); err != nil {
+			return Config{}, err
+		}
+	}
+	if err := ApplyEnv(&cfg, os.LookupEnv); err != nil {
+		return Config{}, err
+	}
+
+	byFlag := make(map[string]setting)
+	for _, s := range cfg.settings() {
+		byFlag[s.flag] = s
+	}
+	var err error
+	// Visit обходит только явно заданные флаги, незаданные не затирают файл и окружение
+	f.fs.Visit(func(fl *flag.Flag) {
+		if s, ok := byFlag[fl.Name]; ok && err == nil {
+			if setErr := s.set(fl.Value.String()); setErr != nil {
+				err = fmt.Errorf("флаг -%s: %w", fl.Name, setErr)
+			}
+		}
+	})
+	if err != nil {
+		return Config{}, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
@@ -3,65 +3,105 @@ package db
 import (
 	"database/sql"
 	"fmt"
-	"log"
-
-	_ "github.com/mattn/go-sqlite3" // Импорт SQLite
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
 )
 
-// InitializeDatabase открывает соединение и создаёт таблицы, если их нет
-func InitializeDatabase() (*sql.DB, error) {
-	// Подключение к базе данных
-	db, err := sql.Open("sqlite3", "./carssale.db")
+// DB — соединение с базой данных вместе с её диалектом. Exec, Query и QueryRow
+// принимают запросы на SQL SQLite с параметрами "?" и переводят их диалектом.
+// Типы столбцов SQLite заменяются только в описании схемы, которое выполняет execSchema.
+type DB struct {
+	*sql.DB
+	dialect Dialect
+}
+
+// Tx — транзакция, переводящая запросы так же, как DB
+type Tx struct {
+	*sql.Tx
+	dialect Dialect
+}
+
+// OpenDatabase открывает соединение с базой данных без применения миграций.
+// Адрес postgres://… открывает PostgreSQL, любой другой — файл SQLite;
+// недостающий каталог для нового файла SQLite создаётся.
+func OpenDatabase(dsn string) (*DB, error) {
+	dialect := DialectFor(dsn)
+	if dialect == SQLite && dsn != ":memory:" && !strings.HasPrefix(dsn, "file:") {
+		if err := os.MkdirAll(filepath.Dir(dsn), 0o755); err != nil {
+			return nil, fmt.Errorf("ошибка создания каталога базы данных: %w", err)
+		}
+	}
+	db, err := sql.Open(dialect.driverName(), dsn)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка подключения к базе данных: %w", err)
 	}
+	return &DB{DB: db, dialect: dialect}, nil
+}
 
-	// Создание таблиц
-	createTablesSQL := `
- CREATE TABLE IF NOT EXISTS Client (
-  ID_Client INTEGER PRIMARY KEY AUTOINCREMENT,
-  Name VARCHAR(50),
-  LastName VARCHAR(50),
-  Phone VARCHAR(15),
-  Login VARCHAR(50) UNIQUE,
-  Password VARCHAR(255)
- );
-
- CREATE TABLE IF NOT EXISTS Cars (
-  ID_Car INTEGER PRIMARY KEY AUTOINCREMENT,
-  Brand VARCHAR(50),
-  Model VARCHAR(50),
-  YearOfRelease INTEGER,
-  Color VARCHAR(30),
-  Price DECIMAL(10, 2)
- );
-
- CREATE TABLE IF NOT EXISTS Administrator (
-  ID_Admin INTEGER PRIMARY KEY AUTOINCREMENT,
-  Name VARCHAR(50),
-  LastName VARCHAR(50),
-  Login VARCHAR(50) UNIQUE,
-  Password VARCHAR(255),
-  Phone VARCHAR(15)
- );
-
- CREATE TABLE IF NOT EXISTS Checks (
-  ID_Check INTEGER PRIMARY KEY AUTOINCREMENT,
-  ID_Client INTEGER NOT NULL,
-  ID_Car INTEGER NOT NULL,
-  ID_Admin INTEGER NOT NULL,
-  Price DECIMAL(10, 2),
-  FOREIGN KEY (ID_Client) REFERENCES Client(ID_Client),
-  FOREIGN KEY (ID_Car) REFERENCES Cars(ID_Car),
-  FOREIGN KEY (ID_Admin) REFERENCES Administrator(ID_Admin)
- );
- `
-
-	_, err = db.Exec(createTablesSQL)
+// InitializeDatabase открывает базу по адресу dsn и применяет все недостающие миграции
+func InitializeDatabase(dsn string) (*DB, error) {
+	// Подключение к базе данных
+	db, err := OpenDatabase(dsn)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка создания таблиц: %w", err)
+		return nil, err
 	}
 
-	log.Println("База данных успешно инициализирована.")
+	// Приведение схемы к актуальной версии
+	if err := Migrate(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ошибка миграции базы данных: %w", err)
+	}
+
+	slog.Info("База данных успешно инициализирована.", "dialect", db.dialect.Name())
 	return db, nil
 }
+
+// Dialect возвращает диалект базы
+func (db *DB) Dialect() Dialect {
+	return db.dialect
+}
+
+func (db *DB) Exec(query string, args ...any) (sql.Result, error) {
+	return db.DB.Exec(db.dialect.translate(query), args...)
+}
+
+func (db *DB) Query(query string, args ...any) (*sql.Rows, error) {
+	return db.DB.Query(db.dialect.translate(query), args...)
+}
+
+func (db *DB) QueryRow(query string, args ...any) *sql.Row {
+	return db.DB.QueryRow(db.dialect.translate(query), args...)
+}
+
+// execSchema выполняет описание схемы из миграции, заменяя типы столбцов SQLite типами СУБД
+func (db *DB) execSchema(ddl string) (sql.Result, error) {
+	return db.Exec(db.dialect.schema(ddl))
+}
+
+// Begin начинает транзакцию
+func (db *DB) Begin() (*Tx, error) {
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: tx, dialect: db.dialect}, nil
+}
+
+func (tx *Tx) Exec(query string, args ...any) (sql.Result, error) {
+	return tx.Tx.Exec(tx.dialect.translate(query), args...)
+}
+
+func (tx *Tx) Query(query string, args ...any) (*sql.Rows, error) {
+	return tx.Tx.Query(tx.dialect.translate(query), args...)
+}
+
+func (tx *Tx) QueryRow(query string, args ...any) *sql.Row {
+	return tx.Tx.QueryRow(tx.dialect.translate(query), args...)
+}
+
+// execSchema выполняет описание схемы из миграции, как DB.execSchema
+func (tx *Tx) execSchema(ddl string) (sql.Result, error) {
+	return tx.Exec(tx.dialect.schema(ddl))
+}
@@ -0,0 +1,153 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	_ "github.com/lib/pq"           // Импорт PostgreSQL
+	_ "github.com/mattn/go-sqlite3" // Импорт SQLite
+)
+
+// Dialect — особенности SQL одной СУБД. Запросы и миграции пишутся на SQL SQLite
+// с параметрами "?", а диалект при выполнении переводит их для своей СУБД.
+type Dialect interface {
+	// Name — название СУБД для сообщений
+	Name() string
+	driverName() string
+	// translate заменяет параметры "?" обозначениями СУБД
+	translate(query string) string
+	// schema заменяет в описании схемы типы столбцов и конструкции SQLite, которых нет в СУБД.
+	// Обычные запросы через неё не проходят, чтобы замена не задела строки и имена в них.
+	schema(ddl string) string
+	// like — оператор сравнения с шаблоном без учёта регистра
+	like() string
+	// columns возвращает столбцы таблицы
+	columns(q queryer, table string) (columnSet, error)
+}
+
+// Поддерживаемые диалекты
+var (
+	SQLite   Dialect = sqliteDialect{}
+	Postgres Dialect = postgresDialect{}
+)
+
+// DialectFor выбирает диалект по адресу базы: postgres://… и postgresql://… — PostgreSQL,
+// любой другой адрес считается путём к файлу SQLite
+func DialectFor(dsn string) Dialect {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		return Postgres
+	}
+	return SQLite
+}
+
+// columnInfo — описание столбца таблицы
+type columnInfo struct {
+	Name    string
+	Type    string
+	NotNull bool
+}
+
+// columnSet — столбцы таблицы по имени в нижнем регистре: PostgreSQL хранит имена
+// без кавычек в нижнем регистре, а SQLite сравнивает их без учёта регистра
+type columnSet map[string]columnInfo
+
+func (s columnSet) get(name string) (columnInfo, bool) {
+	c, ok := s[strings.ToLower(name)]
+	return c, ok
+}
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) Name() string                  { return "SQLite" }
+func (sqliteDialect) driverName() string            { return "sqlite3" }
+func (sqliteDialect) translate(query string) string { return query }
+func (sqliteDialect) schema(ddl string) string      { return ddl }
+
+// like: LIKE в SQLite не различает регистр только у латиницы
+func (sqliteDialect) like() string { return "LIKE" }
+
+func (sqliteDialect) columns(q queryer, table string) (columnSet, error) {
+	rows, err := q.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения структуры таблицы %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	columns := make(columnSet)
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &pk); err != nil {
+			return nil, fmt.Errorf("ошибка чтения структуры таблицы %s: %w", table, err)
+		}
+		columns[strings.ToLower(name)] = columnInfo{Name: name, Type: colType, NotNull: notNull == 1}
+	}
+	return columns, rows.Err()
+}
+
+type postgresDialect struct{}
+
+// postgresTypes заменяет в описании схемы конструкции SQLite, которых нет в PostgreSQL.
+// Время хранится с часовым поясом: приложение записывает его в UTC.
+var postgresTypes = strings.NewReplacer(
+	"INTEGER PRIMARY KEY AUTOINCREMENT", "SERIAL PRIMARY KEY",
+	"DATETIME", "TIMESTAMPTZ",
+	"BLOB", "BYTEA",
+	"CREATE VIEW IF NOT EXISTS", "CREATE OR REPLACE VIEW",
+)
+
+func (postgresDialect) Name() string             { return "PostgreSQL" }
+func (postgresDialect) driverName() string       { return "postgres" }
+func (postgresDialect) like() string             { return "ILIKE" }
+func (postgresDialect) schema(ddl string) string { return postgresTypes.Replace(ddl) }
+
+// translate нумерует параметры ($1, $2, …), пропуская "?" внутри строк и имён в кавычках
+func (postgresDialect) translate(query string) string {
+	if !strings.Contains(query, "?") {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	var quote rune
+	for _, r := range query {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '?':
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func (postgresDialect) columns(q queryer, table string) (columnSet, error) {
+	rows, err := q.Query(`
+		SELECT column_name, data_type, is_nullable = 'NO'
+		FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = ?
+	`, strings.ToLower(table))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения структуры таблицы %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	columns := make(columnSet)
+	for rows.Next() {
+		var c columnInfo
+		if err := rows.Scan(&c.Name, &c.Type, &c.NotNull); err != nil {
+			return nil, fmt.Errorf("ошибка чтения структуры таблицы %s: %w", table, err)
+		}
+		columns[strings.ToLower(c.Name)] = c
+	}
+	return columns, rows.Err()
+}
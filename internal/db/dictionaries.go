@@ -0,0 +1,970 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DictionaryKind — справочник значений, из которых составляются данные автомобиля
+type DictionaryKind string
+
+const (
+	DictBrands DictionaryKind = "brands"
+	DictModels DictionaryKind = "models"
+	DictColors DictionaryKind = "colors"
+)
+
+// DictionaryKinds — справочники в порядке отображения
+var DictionaryKinds = []DictionaryKind{DictBrands, DictModels, DictColors}
+
+var dictionaryTitles = map[DictionaryKind]string{
+	DictBrands: "Марки",
+	DictModels: "Модели",
+	DictColors: "Цвета",
+}
+
+// Title возвращает название справочника для интерфейса
+func (k DictionaryKind) Title() string {
+	if title, ok := dictionaryTitles[k]; ok {
+		return title
+	}
+	return string(k)
+}
+
+// DictionaryEntry — значение справочника
+type DictionaryEntry struct {
+	ID   int
+	Kind DictionaryKind
+	Name string
+	// BrandID и BrandName заполнены только у моделей: модель принадлежит марке
+	BrandID   int
+	BrandName string
+	// Cars — число автомобилей с этим значением; заполняется при чтении списка
+	Cars int
+}
+
+// Ошибки изменения справочников
+var (
+	ErrDictionaryDuplicate = errors.New("такое значение уже есть в справочнике, используйте объединение")
+	ErrDictionaryInUse     = errors.New("значение используется автомобилями, его можно только объединить с другим")
+	ErrDictionaryMerge     = errors.New("объединять можно только два разных значения, модели — только одной марки")
+)
+
+// brandAliases — распространённые написания марок кириллицей и сокращения.
+// Ключи и значения записаны в виде DictionaryKey.
+var brandAliases = map[string]string{
+	"ауди":          "audi",
+	"бмв":           "bmw",
+	"вольво":        "volvo",
+	"лада":          "lada",
+	"ваз":           "lada",
+	"киа":           "kia",
+	"лексус":        "lexus",
+	"мазда":         "mazda",
+	"мерседес":      "mercedes benz",
+	"мерседес бенц": "mercedes benz",
+	"mercedes":      "mercedes benz",
+	"митсубиси":     "mitsubishi",
+	"мицубиси":      "mitsubishi",
+	"ниссан":        "nissan",
+	"опель":         "opel",
+	"пежо":          "peugeot",
+	"порше":         "porsche",
+	"рено":          "renault",
+	"ситроен":       "citroen",
+	"субару":        "subaru",
+	"сузуки":        "suzuki",
+	"тесла":         "tesla",
+	"тойота":        "toyota",
+	"фольксваген":   "volkswagen",
+	"vw":            "volkswagen",
+	"форд":          "ford",
+	"хонда":         "honda",
+	"хендай":        "hyundai",
+	"хендэ":         "hyundai",
+	"хундай":        "hyundai",
+	"шевроле":       "chevrolet",
+	"chevy":         "chevrolet",
+	"шкода":         "skoda",
+	"landrover":     "land rover",
+	"ленд ровер":    "land rover",
+}
+
+// colorAliases — английские названия цветов, которые приводятся к русским
+var colorAliases = map[string]string{
+	"black":  "черный",
+	"white":  "белый",
+	"red":    "красный",
+	"blue":   "синий",
+	"green":  "зеленый",
+	"yellow": "желтый",
+	"gray":   "серый",
+	"grey":   "серый",
+	"silver": "серебристый",
+	"brown":  "коричневый",
+	"orange": "оранжевый",
+	"beige":  "бежевый",
+}
+
+// plainKey приводит написание к виду для сравнения: нижний регистр, «ё» как «е»,
+// дефисы как пробелы, без лишних пробелов
+func plainKey(name string) string {
+	name = strings.ToLower(strings.ReplaceAll(name, "-", " "))
+	name = strings.ReplaceAll(name, "ё", "е")
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// DictionaryKey возвращает ключ, по которому разные написания считаются одним значением справочника:
+// «Toyota», «toyota» и «Тойота» дают один ключ
+func DictionaryKey(kind DictionaryKind, name string) string {
+	key := plainKey(name)
+	aliases := map[DictionaryKind]map[string]string{DictBrands: brandAliases, DictColors: colorAliases}[kind]
+	if alias, ok := aliases[key]; ok {
+		return alias
+	}
+	return key
+}
+
+// BrandMatchesVIN сообщает, совпадает ли марка, введённая пользователем, с маркой из расшифровки VIN.
+// Марки сравниваются по DictionaryKey, поэтому «Мерседес» совпадает с «Mercedes-Benz», а «Mini» с «Mini Cooper» — нет.
+// Если одна из марок неизвестна, расхождения нет.
+func BrandMatchesVIN(decoded, typed string) bool {
+	d, t := DictionaryKey(DictBrands, decoded), DictionaryKey(DictBrands, typed)
+	return d == "" || t == "" || d == t
+}
+
+// validateDictionaryName проверяет название значения по тем же правилам, что и ValidateCar
+func validateDictionaryName(kind DictionaryKind, name string) error {
+	switch {
+	case strings.TrimSpace(name) == "":
+		return fmt.Errorf("%w: название не может быть пустым", ErrInvalidCar)
+	case kind == DictBrands && containsDigit(name):
+		return fmt.Errorf("%w: марка не должна содержать цифры", ErrInvalidCar)
+	case kind == DictColors && containsDigit(name):
+		return fmt.Errorf("%w: цвет не должен содержать цифры", ErrInvalidCar)
+	}
+	return nil
+}
+
+// sortEntries упорядочивает значения по алфавиту, модели — сначала по марке
+func sortEntries(entries []DictionaryEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if ka, kb := plainKey(a.BrandName), plainKey(b.BrandName); ka != kb {
+			return ka < kb
+		}
+		return plainKey(a.Name) < plainKey(b.Name)
+	})
+}
+
+// dictionaryTable — таблица справочника и ссылающийся на неё столбец Cars
+type dictionaryTable struct {
+	table, id string
+}
+
+var dictionaryTables = map[DictionaryKind]dictionaryTable{
+	DictBrands: {"Brands", "ID_Brand"},
+	DictModels: {"Models", "ID_Model"},
+	DictColors: {"Colors", "ID_Color"},
+}
+
+// table возвращает таблицу справочника; неизвестный справочник — ошибка
+func (k DictionaryKind) table() (dictionaryTable, error) {
+	t, ok := dictionaryTables[k]
+	if !ok {
+		return dictionaryTable{}, fmt.Errorf("неизвестный справочник %q", k)
+	}
+	return t, nil
+}
+
+// carRefs — ссылки автомобиля на справочники
+type carRefs struct {
+	brand, model, color int
+}
+
+type sqlDictionaryRepository struct {
+	db *DB
+}
+
+func (r *sqlDictionaryRepository) List(kind DictionaryKind, brandID int) ([]DictionaryEntry, error) {
+	t, err := kind.table()
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf(
+		"SELECT %[2]s, Name, %[3]s, (SELECT COUNT(*) FROM Cars c WHERE c.%[2]s = %[1]s.%[2]s) FROM %[1]s",
+		t.table, t.id, scopeColumn(kind),
+	)
+	var args []any
+	if kind == DictModels && brandID != 0 {
+		query += " WHERE ID_Brand = ?"
+		args = append(args, brandID)
+	}
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения справочника: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []DictionaryEntry
+	for rows.Next() {
+		e := DictionaryEntry{Kind: kind}
+		if err := rows.Scan(&e.ID, &e.Name, &e.BrandID, &e.Cars); err != nil {
+			return nil, fmt.Errorf("ошибка чтения справочника: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if kind == DictModels {
+		brands, err := r.names(DictBrands)
+		if err != nil {
+			return nil, err
+		}
+		for i := range entries {
+			entries[i].BrandName = brands[entries[i].BrandID]
+		}
+	}
+	sortEntries(entries)
+	return entries, nil
+}
+
+// names возвращает названия значений справочника по ID
+func (r *sqlDictionaryRepository) names(kind DictionaryKind) (map[int]string, error) {
+	t, err := kind.table()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.db.Query(fmt.Sprintf("SELECT %s, Name FROM %s", t.id, t.table))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения справочника: %w", err)
+	}
+	defer rows.Close()
+
+	names := make(map[int]string)
+	for rows.Next() {
+		var id int
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, fmt.Errorf("ошибка чтения справочника: %w", err)
+		}
+		names[id] = name
+	}
+	return names, rows.Err()
+}
+
+func (r *sqlDictionaryRepository) Add(entry *DictionaryEntry) error {
+	t, err := entry.Kind.table()
+	if err != nil {
+		return err
+	}
+	if err := validateDictionaryName(entry.Kind, entry.Name); err != nil {
+		return err
+	}
+	entry.Name = strings.TrimSpace(entry.Name)
+	return inTx(r.db, func(tx *Tx) error {
+		if entry.Kind == DictModels {
+			if _, err := entryName(tx, DictBrands, entry.BrandID); err != nil {
+				return err
+			}
+		} else {
+			entry.BrandID = 0
+		}
+		key := DictionaryKey(entry.Kind, entry.Name)
+		if id, err := findEntry(tx, entry.Kind, entry.BrandID, key); err != nil {
+			return err
+		} else if id != 0 {
+			return ErrDictionaryDuplicate
+		}
+		id, err := insertEntry(tx, t, entry.BrandID, entry.Name, key)
+		if err != nil {
+			return err
+		}
+		entry.ID = id
+		return nil
+	})
+}
+
+func (r *sqlDictionaryRepository) Rename(kind DictionaryKind, id int, name string) error {
+	t, err := kind.table()
+	if err != nil {
+		return err
+	}
+	if err := validateDictionaryName(kind, name); err != nil {
+		return err
+	}
+	name = strings.TrimSpace(name)
+	return inTx(r.db, func(tx *Tx) error {
+		var oldName string
+		var brandID int
+		err := tx.QueryRow(
+			fmt.Sprintf("SELECT Name, %s FROM %s WHERE %s = ?", scopeColumn(kind), t.table, t.id), id,
+		).Scan(&oldName, &brandID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("ошибка получения значения справочника: %w", err)
+		}
+		key := DictionaryKey(kind, name)
+		if other, err := findEntry(tx, kind, brandID, key); err != nil {
+			return err
+		} else if other != 0 && other != id {
+			return ErrDictionaryDuplicate
+		}
+		if _, err := tx.Exec(fmt.Sprintf("UPDATE %s SET Name = ?, NameKey = ? WHERE %s = ?", t.table, t.id), name, key, id); err != nil {
+			return fmt.Errorf("ошибка переименования значения справочника: %w", err)
+		}
+		// Прежнее написание продолжает распознаваться при вводе
+		if oldKey := DictionaryKey(kind, oldName); oldKey != key {
+			return addAlias(tx, kind, brandID, oldKey, id)
+		}
+		return nil
+	})
+}
+
+func (r *sqlDictionaryRepository) Merge(kind DictionaryKind, fromID, intoID int) error {
+	if _, err := kind.table(); err != nil {
+		return err
+	}
+	if fromID == intoID {
+		return ErrDictionaryMerge
+	}
+	return inTx(r.db, func(tx *Tx) error {
+		return mergeEntries(tx, kind, fromID, intoID, false)
+	})
+}
+
+// mergeEntries переносит автомобили и псевдонимы со значения fromID на intoID и удаляет fromID.
+// При объединении марок модели с одинаковым названием объединяются, остальные переходят к марке intoID;
+// только в этом случае (acrossBrands) допускается объединение моделей разных марок.
+func mergeEntries(tx *Tx, kind DictionaryKind, fromID, intoID int, acrossBrands bool) error {
+	t, _ := kind.table()
+	var from, into DictionaryEntry
+	for _, e := range []struct {
+		id    int
+		entry *DictionaryEntry
+	}{{fromID, &from}, {intoID, &into}} {
+		err := tx.QueryRow(
+			fmt.Sprintf("SELECT Name, %s FROM %s WHERE %s = ?", scopeColumn(kind), t.table, t.id), e.id,
+		).Scan(&e.entry.Name, &e.entry.BrandID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("ошибка получения значения справочника: %w", err)
+		}
+	}
+	if kind == DictModels && from.BrandID != into.BrandID && !acrossBrands {
+		return ErrDictionaryMerge
+	}
+
+	if kind == DictBrands {
+		models, err := tx.Query("SELECT ID_Model, NameKey FROM Models WHERE ID_Brand = ?", fromID)
+		if err != nil {
+			return fmt.Errorf("ошибка объединения марок: %w", err)
+		}
+		type model struct {
+			id  int
+			key string
+		}
+		var moved []model
+		for models.Next() {
+			var m model
+			if err := models.Scan(&m.id, &m.key); err != nil {
+				models.Close()
+				return fmt.Errorf("ошибка объединения марок: %w", err)
+			}
+			moved = append(moved, m)
+		}
+		models.Close()
+		if err := models.Err(); err != nil {
+			return err
+		}
+		for _, m := range moved {
+			same, err := findEntry(tx, DictModels, intoID, m.key)
+			if err != nil {
+				return err
+			}
+			if same != 0 {
+				if err := mergeEntries(tx, DictModels, m.id, same, true); err != nil {
+					return err
+				}
+				continue
+			}
+			if _, err := tx.Exec("UPDATE Models SET ID_Brand = ? WHERE ID_Model = ?", intoID, m.id); err != nil {
+				return fmt.Errorf("ошибка объединения марок: %w", err)
+			}
+		}
+		// Псевдонимы моделей переходят к новой марке, если там нет такого же
+		err = execAll(tx, "ошибка объединения марок",
+			statement{`UPDATE DictionaryAliases SET ID_Brand = ? WHERE Kind = ? AND ID_Brand = ?
+			 AND NameKey NOT IN (SELECT NameKey FROM DictionaryAliases WHERE Kind = ? AND ID_Brand = ?)`,
+				[]any{intoID, DictModels, fromID, DictModels, intoID}},
+			statement{"DELETE FROM DictionaryAliases WHERE Kind = ? AND ID_Brand = ?", []any{DictModels, fromID}},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := execAll(tx, "ошибка объединения значений справочника",
+		statement{fmt.Sprintf("UPDATE Cars SET %[1]s = ? WHERE %[1]s = ?", t.id), []any{intoID, fromID}},
+		statement{"UPDATE DictionaryAliases SET ID_Value = ? WHERE Kind = ? AND ID_Value = ?", []any{intoID, kind, fromID}},
+		statement{fmt.Sprintf("DELETE FROM %s WHERE %s = ?", t.table, t.id), []any{fromID}},
+	)
+	if err != nil {
+		return err
+	}
+	// Написание удалённого значения продолжает распознаваться при вводе
+	return addAlias(tx, kind, into.BrandID, DictionaryKey(kind, from.Name), intoID)
+}
+
+func (r *sqlDictionaryRepository) Delete(kind DictionaryKind, id int) error {
+	t, err := kind.table()
+	if err != nil {
+		return err
+	}
+	return inTx(r.db, func(tx *Tx) error {
+		var cars int
+		if err := tx.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM Cars WHERE %s = ?", t.id), id).Scan(&cars); err != nil {
+			return fmt.Errorf("ошибка удаления значения справочника: %w", err)
+		}
+		if cars > 0 {
+			return ErrDictionaryInUse
+		}
+		statements := []statement{{"DELETE FROM DictionaryAliases WHERE Kind = ? AND ID_Value = ?", []any{kind, id}}}
+		if kind == DictBrands {
+			// Модели марки без автомобилей удаляются вместе с ней
+			statements = append(statements,
+				statement{"DELETE FROM DictionaryAliases WHERE Kind = ? AND ID_Brand = ?", []any{DictModels, id}},
+				statement{"DELETE FROM Models WHERE ID_Brand = ?", []any{id}},
+			)
+		}
+		if err := execAll(tx, "ошибка удаления значения справочника", statements...); err != nil {
+			return err
+		}
+		res, err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s = ?", t.table, t.id), id)
+		if err != nil {
+			return fmt.Errorf("ошибка удаления значения справочника: %w", err)
+		}
+		return expectAffected(res)
+	})
+}
+
+// statement — запрос с параметрами для execAll
+type statement struct {
+	query string
+	args  []any
+}
+
+// execAll выполняет запросы по порядку; ошибка оборачивается сообщением message
+func execAll(q queryer, message string, statements ...statement) error {
+	for _, s := range statements {
+		if _, err := q.Exec(s.query, s.args...); err != nil {
+			return fmt.Errorf("%s: %w", message, err)
+		}
+	}
+	return nil
+}
+
+// scopeColumn — столбец марки у моделей; у марок и цветов области нет
+func scopeColumn(kind DictionaryKind) string {
+	if kind == DictModels {
+		return "ID_Brand"
+	}
+	return "0"
+}
+
+// entryName возвращает название значения справочника или ErrNotFound
+func entryName(q queryer, kind DictionaryKind, id int) (string, error) {
+	t, err := kind.table()
+	if err != nil {
+		return "", err
+	}
+	var name string
+	err = q.QueryRow(fmt.Sprintf("SELECT Name FROM %s WHERE %s = ?", t.table, t.id), id).Scan(&name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("ошибка получения значения справочника: %w", err)
+	}
+	return name, nil
+}
+
+// findEntry ищет значение по ключу среди значений справочника, а затем среди псевдонимов.
+// brandID ограничивает поиск моделей одной маркой. Возвращает 0, если значение не найдено.
+func findEntry(q queryer, kind DictionaryKind, brandID int, key string) (int, error) {
+	t, err := kind.table()
+	if err != nil {
+		return 0, err
+	}
+	var id int
+	err = q.QueryRow(
+		fmt.Sprintf("SELECT %s FROM %s WHERE NameKey = ? AND %s = ?", t.id, t.table, scopeColumn(kind)), key, brandID,
+	).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = q.QueryRow(
+			"SELECT ID_Value FROM DictionaryAliases WHERE Kind = ? AND ID_Brand = ? AND NameKey = ?", kind, brandID, key,
+		).Scan(&id)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("ошибка поиска в справочнике: %w", err)
+	}
+	return id, nil
+}
+
+func insertEntry(q queryer, t dictionaryTable, brandID int, name, key string) (int, error) {
+	query := fmt.Sprintf("INSERT INTO %s (Name, NameKey) VALUES (?, ?)", t.table)
+	args := []any{name, key}
+	if t.table == "Models" {
+		query = "INSERT INTO Models (ID_Brand, Name, NameKey) VALUES (?, ?, ?)"
+		args = []any{brandID, name, key}
+	}
+	id, err := insertID(q, t.id, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка добавления значения справочника: %w", err)
+	}
+	return id, nil
+}
+
+// addAlias запоминает написание key как другое название значения id
+func addAlias(q queryer, kind DictionaryKind, brandID int, key string, id int) error {
+	_, err := q.Exec(
+		`INSERT INTO DictionaryAliases (Kind, ID_Brand, NameKey, ID_Value) VALUES (?, ?, ?, ?)
+		 ON CONFLICT (Kind, ID_Brand, NameKey) DO UPDATE SET ID_Value = excluded.ID_Value`,
+		kind, brandID, key, id,
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения написания значения справочника: %w", err)
+	}
+	return nil
+}
+
+// resolveEntry находит значение справочника по написанию или добавляет новое.
+// Возвращает ID и название значения так, как оно записано в справочнике.
+func resolveEntry(q queryer, kind DictionaryKind, brandID int, name string) (int, string, error) {
+	name = strings.TrimSpace(name)
+	key := DictionaryKey(kind, name)
+	id, err := findEntry(q, kind, brandID, key)
+	if err != nil {
+		return 0, "", err
+	}
+	if id != 0 {
+		canonical, err := entryName(q, kind, id)
+		return id, canonical, err
+	}
+	t, _ := kind.table()
+	id, err = insertEntry(q, t, brandID, name, key)
+	return id, name, err
+}
+
+// resolveCar находит или добавляет марку, модель и цвет автомобиля и заменяет их
+// в car написанием из справочников
+func resolveCar(q queryer, car *Car) (carRefs, error) {
+	var refs carRefs
+	var err error
+	if refs.brand, car.Brand, err = resolveEntry(q, DictBrands, 0, car.Brand); err != nil {
+		return carRefs{}, err
+	}
+	if refs.model, car.Model, err = resolveEntry(q, DictModels, refs.brand, car.Model); err != nil {
+		return carRefs{}, err
+	}
+	if refs.color, car.Color, err = resolveEntry(q, DictColors, 0, car.Color); err != nil {
+		return carRefs{}, err
+	}
+	return refs, nil
+}
+
+// unspecifiedName — значение справочника для пустых марок, моделей и цветов старых баз
+const unspecifiedName = "Не указано"
+
+// mergeFreeTextValues переносит марки, модели и цвета из текстовых столбцов Cars в справочники.
+// Написания с одинаковым DictionaryKey объединяются; названием значения становится написание,
+// не требующее замены по псевдонимам (латиница у марок, русское у цветов), а из них — самое частое.
+// Отличающиеся регистром и пробелами написания после этого распознаются по DictionaryKey.
+// Пустые и состоящие из пробелов значения становятся значением «Не указано».
+func mergeFreeTextValues(tx *Tx) error {
+	type carText struct {
+		id                  int
+		brand, model, color sql.NullString
+	}
+	rows, err := tx.Query("SELECT ID_Car, Brand, Model, Color FROM Cars")
+	if err != nil {
+		return err
+	}
+	var cars []carText
+	for rows.Next() {
+		var c carText
+		if err := rows.Scan(&c.id, &c.brand, &c.model, &c.color); err != nil {
+			rows.Close()
+			return err
+		}
+		cars = append(cars, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// spellings[группа][написание] — число автомобилей с таким написанием
+	spellings := make(map[string]map[string]int)
+	count := func(kind DictionaryKind, scope string, v sql.NullString) string {
+		name := strings.TrimSpace(v.String)
+		if name == "" {
+			// Старая форма принимала пустую марку или марку из пробелов
+			name = unspecifiedName
+		}
+		group := string(kind) + "\x00" + scope + "\x00" + DictionaryKey(kind, name)
+		if spellings[group] == nil {
+			spellings[group] = make(map[string]int)
+		}
+		spellings[group][name]++
+		return group
+	}
+	type carGroups struct{ brand, model, color string }
+	groups := make([]carGroups, len(cars))
+	for i, c := range cars {
+		groups[i].brand = count(DictBrands, "", c.brand)
+		groups[i].model = count(DictModels, groups[i].brand, c.model)
+		groups[i].color = count(DictColors, "", c.color)
+	}
+
+	chooseName := func(kind DictionaryKind, variants map[string]int) string {
+		best := ""
+		better := func(a, b string) bool {
+			// Написание без замены по псевдонимам предпочтительнее
+			pa, pb := plainKey(a) == DictionaryKey(kind, a), plainKey(b) == DictionaryKey(kind, b)
+			if pa != pb {
+				return pa
+			}
+			if variants[a] != variants[b] {
+				return variants[a] > variants[b]
+			}
+			return a < b
+		}
+		for name := range variants {
+			if best == "" || better(name, best) {
+				best = name
+			}
+		}
+		return best
+	}
+
+	ids := make(map[string]int)
+	resolve := func(kind DictionaryKind, group string, brandID int) (int, error) {
+		if group == "" {
+			return 0, nil
+		}
+		if id, ok := ids[group]; ok {
+			return id, nil
+		}
+		id, _, err := resolveEntry(tx, kind, brandID, chooseName(kind, spellings[group]))
+		if err != nil {
+			return 0, err
+		}
+		ids[group] = id
+		return id, nil
+	}
+
+	for i, c := range cars {
+		brandID, err := resolve(DictBrands, groups[i].brand, 0)
+		if err != nil {
+			return err
+		}
+		modelID, err := resolve(DictModels, groups[i].model, brandID)
+		if err != nil {
+			return err
+		}
+		colorID, err := resolve(DictColors, groups[i].color, 0)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(
+			"UPDATE Cars SET ID_Brand = ?, ID_Model = ?, ID_Color = ? WHERE ID_Car = ?",
+			nullID(brandID), nullID(modelID), nullID(colorID), c.id,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nullID сохраняет нулевой ID как NULL
+func nullID(id int) any {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
+
+type memoryDictionaryRepository struct {
+	d *memoryData
+}
+
+// memoryAlias — другое написание значения справочника
+type memoryAlias struct {
+	kind    DictionaryKind
+	brandID int
+	key     string
+}
+
+func (r *memoryDictionaryRepository) List(kind DictionaryKind, brandID int) ([]DictionaryEntry, error) {
+	if _, err := kind.table(); err != nil {
+		return nil, err
+	}
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	var entries []DictionaryEntry
+	for _, e := range r.d.dictionaries[kind] {
+		if kind == DictModels && brandID != 0 && e.BrandID != brandID {
+			continue
+		}
+		if kind == DictModels {
+			e.BrandName = r.d.dictionaries[DictBrands][e.BrandID].Name
+		}
+		e.Cars = len(r.d.carsWith(e))
+		entries = append(entries, e)
+	}
+	sortEntries(entries)
+	return entries, nil
+}
+
+func (r *memoryDictionaryRepository) Add(entry *DictionaryEntry) error {
+	if _, err := entry.Kind.table(); err != nil {
+		return err
+	}
+	if err := validateDictionaryName(entry.Kind, entry.Name); err != nil {
+		return err
+	}
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	entry.Name = strings.TrimSpace(entry.Name)
+	if entry.Kind == DictModels {
+		if _, ok := r.d.dictionaries[DictBrands][entry.BrandID]; !ok {
+			return ErrNotFound
+		}
+	} else {
+		entry.BrandID = 0
+	}
+	if r.d.findEntry(entry.Kind, entry.BrandID, DictionaryKey(entry.Kind, entry.Name)) != 0 {
+		return ErrDictionaryDuplicate
+	}
+	entry.ID = r.d.insertEntry(entry.Kind, entry.BrandID, entry.Name)
+	stored := *entry
+	stored.BrandName, stored.Cars = "", 0
+	r.d.dictionaries[entry.Kind][entry.ID] = stored
+	return nil
+}
+
+func (r *memoryDictionaryRepository) Rename(kind DictionaryKind, id int, name string) error {
+	if _, err := kind.table(); err != nil {
+		return err
+	}
+	if err := validateDictionaryName(kind, name); err != nil {
+		return err
+	}
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	e, ok := r.d.dictionaries[kind][id]
+	if !ok {
+		return ErrNotFound
+	}
+	name = strings.TrimSpace(name)
+	key := DictionaryKey(kind, name)
+	if other := r.d.findEntry(kind, e.BrandID, key); other != 0 && other != id {
+		return ErrDictionaryDuplicate
+	}
+	for _, carID := range r.d.carsWith(e) {
+		car := r.d.cars[carID]
+		setCarValue(&car, kind, name)
+		r.d.cars[carID] = car
+	}
+	if oldKey := DictionaryKey(kind, e.Name); oldKey != key {
+		r.d.aliases[memoryAlias{kind, e.BrandID, oldKey}] = id
+	}
+	e.Name = name
+	r.d.dictionaries[kind][id] = e
+	return nil
+}
+
+func (r *memoryDictionaryRepository) Merge(kind DictionaryKind, fromID, intoID int) error {
+	if _, err := kind.table(); err != nil {
+		return err
+	}
+	if fromID == intoID {
+		return ErrDictionaryMerge
+	}
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	return r.d.mergeEntries(kind, fromID, intoID, false)
+}
+
+// mergeEntries повторяет mergeEntries для базы данных; вызывается под блокировкой
+func (d *memoryData) mergeEntries(kind DictionaryKind, fromID, intoID int, acrossBrands bool) error {
+	from, ok := d.dictionaries[kind][fromID]
+	if !ok {
+		return ErrNotFound
+	}
+	into, ok := d.dictionaries[kind][intoID]
+	if !ok {
+		return ErrNotFound
+	}
+	if kind == DictModels && from.BrandID != into.BrandID && !acrossBrands {
+		return ErrDictionaryMerge
+	}
+
+	// Автомобили запоминаются до переноса моделей: после него их уже не найти по марке
+	cars := d.carsWith(from)
+	if kind == DictBrands {
+		for _, m := range d.dictionaries[DictModels] {
+			if m.BrandID != fromID {
+				continue
+			}
+			if same := d.findEntry(DictModels, intoID, DictionaryKey(DictModels, m.Name)); same != 0 {
+				if err := d.mergeEntries(DictModels, m.ID, same, true); err != nil {
+					return err
+				}
+				continue
+			}
+			m.BrandID = intoID
+			d.dictionaries[DictModels][m.ID] = m
+		}
+		for alias, id := range d.aliases {
+			if alias.kind == DictModels && alias.brandID == fromID {
+				delete(d.aliases, alias)
+				moved := memoryAlias{DictModels, intoID, alias.key}
+				if _, taken := d.aliases[moved]; !taken {
+					d.aliases[moved] = id
+				}
+			}
+		}
+	}
+
+	for _, carID := range cars {
+		car := d.cars[carID]
+		setCarValue(&car, kind, into.Name)
+		d.cars[carID] = car
+	}
+	for alias, id := range d.aliases {
+		if alias.kind == kind && id == fromID {
+			d.aliases[alias] = intoID
+		}
+	}
+	delete(d.dictionaries[kind], fromID)
+	d.aliases[memoryAlias{kind, into.BrandID, DictionaryKey(kind, from.Name)}] = intoID
+	return nil
+}
+
+func (r *memoryDictionaryRepository) Delete(kind DictionaryKind, id int) error {
+	if _, err := kind.table(); err != nil {
+		return err
+	}
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	e, ok := r.d.dictionaries[kind][id]
+	if !ok {
+		return ErrNotFound
+	}
+	if len(r.d.carsWith(e)) > 0 {
+		return ErrDictionaryInUse
+	}
+	for alias, value := range r.d.aliases {
+		if (alias.kind == kind && value == id) || (kind == DictBrands && alias.kind == DictModels && alias.brandID == id) {
+			delete(r.d.aliases, alias)
+		}
+	}
+	if kind == DictBrands {
+		for modelID, m := range r.d.dictionaries[DictModels] {
+			if m.BrandID == id {
+				delete(r.d.dictionaries[DictModels], modelID)
+			}
+		}
+	}
+	delete(r.d.dictionaries[kind], id)
+	return nil
+}
+
+// findEntry ищет значение по ключу и псевдонимам; вызывается под блокировкой
+func (d *memoryData) findEntry(kind DictionaryKind, brandID int, key string) int {
+	for _, e := range d.dictionaries[kind] {
+		if e.BrandID == brandID && DictionaryKey(kind, e.Name) == key {
+			return e.ID
+		}
+	}
+	return d.aliases[memoryAlias{kind, brandID, key}]
+}
+
+func (d *memoryData) insertEntry(kind DictionaryKind, brandID int, name string) int {
+	t, _ := kind.table()
+	id := d.newID(t.table)
+	d.dictionaries[kind][id] = DictionaryEntry{ID: id, Kind: kind, Name: name, BrandID: brandID}
+	return id
+}
+
+// resolveEntry находит значение по написанию или добавляет новое; вызывается под блокировкой
+func (d *memoryData) resolveEntry(kind DictionaryKind, brandID int, name string) (int, string) {
+	name = strings.TrimSpace(name)
+	if id := d.findEntry(kind, brandID, DictionaryKey(kind, name)); id != 0 {
+		return id, d.dictionaries[kind][id].Name
+	}
+	return d.insertEntry(kind, brandID, name), name
+}
+
+// resolveCar заменяет марку, модель и цвет написанием из справочников; вызывается под блокировкой
+func (d *memoryData) resolveCar(car *Car) {
+	var brandID int
+	brandID, car.Brand = d.resolveEntry(DictBrands, 0, car.Brand)
+	_, car.Model = d.resolveEntry(DictModels, brandID, car.Model)
+	_, car.Color = d.resolveEntry(DictColors, 0, car.Color)
+}
+
+// carsWith возвращает ID автомобилей со значением справочника e; вызывается под блокировкой.
+// В памяти автомобили хранят названия, поэтому модель сравнивается вместе с маркой.
+func (d *memoryData) carsWith(e DictionaryEntry) []int {
+	var ids []int
+	for id, car := range d.cars {
+		switch e.Kind {
+		case DictBrands:
+			if car.Brand != e.Name {
+				continue
+			}
+		case DictModels:
+			if car.Model != e.Name || car.Brand != d.dictionaries[DictBrands][e.BrandID].Name {
+				continue
+			}
+		case DictColors:
+			if car.Color != e.Name {
+				continue
+			}
+		}
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func setCarValue(car *Car, kind DictionaryKind, name string) {
+	switch kind {
+	case DictBrands:
+		car.Brand = name
+	case DictModels:
+		car.Model = name
+	case DictColors:
+		car.Color = name
+	}
+}
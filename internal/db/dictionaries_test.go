@@ -0,0 +1,317 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestDictionaryKey(t *testing.T) {
+	tests := []struct {
+		kind DictionaryKind
+		a, b string
+		same bool
+	}{
+		{DictBrands, "Toyota", " toyota ", true},
+		{DictBrands, "Toyota", "Тойота", true},
+		{DictBrands, "Mercedes-Benz", "mercedes benz", true},
+		{DictBrands, "Мерседес", "Mercedes-Benz", true},
+		{DictColors, "Чёрный", "черный", true},
+		{DictColors, "Black", "Черный", true},
+		{DictModels, "C-Class", "c class", true},
+		{DictBrands, "BMW", "Audi", false},
+	}
+	for _, tt := range tests {
+		if got := DictionaryKey(tt.kind, tt.a) == DictionaryKey(tt.kind, tt.b); got != tt.same {
+			t.Errorf("%s: %q и %q — одинаковый ключ: %v", tt.kind, tt.a, tt.b, got)
+		}
+	}
+}
+
+func TestCarsShareDictionaryValues(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			cars := []Car{
+				{Brand: "Toyota", Model: "Camry", Year: 2020, Color: "Black", Price: 24000},
+				{Brand: "toyota", Model: "camry", Year: 2021, Color: "black", Price: 25000},
+				{Brand: "Тойота", Model: "Corolla", Year: 2019, Color: "Чёрный", Price: 15000},
+				{Brand: "BMW", Model: "X5", Year: 2020, Color: "White", Price: 50000},
+			}
+			for i := range cars {
+				if err := store.Cars.Create(&cars[i]); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, car := range cars[:3] {
+				got, err := store.Cars.Get(car.ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got.Brand != "Toyota" || got.Color != "Black" {
+					t.Errorf("автомобиль %d: %s, %s — ожидалось написание из справочника", car.ID, got.Brand, got.Color)
+				}
+			}
+
+			brands, err := store.Dictionaries.List(DictBrands, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(brands) != 2 || brands[0].Name != "BMW" || brands[1].Name != "Toyota" || brands[1].Cars != 3 {
+				t.Fatalf("марки: %+v", brands)
+			}
+			models, err := store.Dictionaries.List(DictModels, brands[1].ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(models) != 2 || models[0].Name != "Camry" || models[0].Cars != 2 || models[0].BrandName != "Toyota" {
+				t.Fatalf("модели Toyota: %+v", models)
+			}
+
+			found, err := store.Cars.Search(CarFilter{Brand: "тойота", Color: "черный"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(found) != 3 {
+				t.Errorf("фильтр по другому написанию нашёл %d автомобилей", len(found))
+			}
+
+			sales, err := store.Checks.ListSales(true)
+			if err != nil || len(sales) != 0 {
+				t.Fatalf("продажи: %v, %v", sales, err)
+			}
+		})
+	}
+}
+
+func TestDictionaryRenameMergeDelete(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			cars := []Car{
+				{Brand: "Toyota", Model: "Camry", Year: 2020, Color: "Black", Price: 24000},
+				{Brand: "Toyta", Model: "Camry", Year: 2021, Color: "Black", Price: 25000},
+				{Brand: "Toyta", Model: "Prius", Year: 2019, Color: "Black", Price: 15000},
+			}
+			for i := range cars {
+				if err := store.Cars.Create(&cars[i]); err != nil {
+					t.Fatal(err)
+				}
+			}
+			brandID := func(name string) int {
+				brands, err := store.Dictionaries.List(DictBrands, 0)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, b := range brands {
+					if b.Name == name {
+						return b.ID
+					}
+				}
+				return 0
+			}
+			toyota, typo := brandID("Toyota"), brandID("Toyta")
+
+			if err := store.Dictionaries.Rename(DictBrands, typo, "toyota"); !errors.Is(err, ErrDictionaryDuplicate) {
+				t.Errorf("переименование в существующее значение: %v", err)
+			}
+			if err := store.Dictionaries.Delete(DictBrands, typo); !errors.Is(err, ErrDictionaryInUse) {
+				t.Errorf("удаление используемого значения: %v", err)
+			}
+			if err := store.Dictionaries.Merge(DictBrands, typo, typo); !errors.Is(err, ErrDictionaryMerge) {
+				t.Errorf("объединение с самим собой: %v", err)
+			}
+
+			if err := store.Dictionaries.Merge(DictBrands, typo, toyota); err != nil {
+				t.Fatal(err)
+			}
+			for _, car := range cars {
+				got, err := store.Cars.Get(car.ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got.Brand != "Toyota" {
+					t.Errorf("после объединения автомобиль %d: марка %s", car.ID, got.Brand)
+				}
+			}
+			models, err := store.Dictionaries.List(DictModels, toyota)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(models) != 2 || models[0].Name != "Camry" || models[0].Cars != 2 || models[1].Name != "Prius" {
+				t.Errorf("модели после объединения марок: %+v", models)
+			}
+
+			// Написание объединённой марки распознаётся при вводе
+			car := Car{Brand: "toyta", Model: "Prius", Year: 2022, Color: "Black", Price: 30000}
+			if err := store.Cars.Create(&car); err != nil {
+				t.Fatal(err)
+			}
+			if car.Brand != "Toyota" || brandID("Toyta") != 0 || brandID("toyta") != 0 {
+				t.Errorf("новый автомобиль с прежним написанием: %s", car.Brand)
+			}
+
+			if err := store.Dictionaries.Rename(DictBrands, toyota, "TOYOTA"); err != nil {
+				t.Fatal(err)
+			}
+			got, err := store.Cars.Get(cars[0].ID)
+			if err != nil || got.Brand != "TOYOTA" {
+				t.Errorf("после переименования: %+v, %v", got, err)
+			}
+
+			color := DictionaryEntry{Kind: DictColors, Name: "Зелёный"}
+			if err := store.Dictionaries.Add(&color); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.Dictionaries.Add(&DictionaryEntry{Kind: DictColors, Name: "зеленый"}); !errors.Is(err, ErrDictionaryDuplicate) {
+				t.Errorf("добавление другого написания: %v", err)
+			}
+			if err := store.Dictionaries.Add(&DictionaryEntry{Kind: DictModels, Name: "Supra"}); !errors.Is(err, ErrNotFound) {
+				t.Errorf("модель без марки: %v", err)
+			}
+			if err := store.Dictionaries.Delete(DictColors, color.ID); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.Dictionaries.Delete(DictColors, color.ID); !errors.Is(err, ErrNotFound) {
+				t.Errorf("повторное удаление: %v", err)
+			}
+		})
+	}
+}
+
+func TestDictionaryMigrationMergesFreeText(t *testing.T) {
+	for name, database := range testDatabases(t) {
+		t.Run(name, func(t *testing.T) {
+			if err := Migrate(database); err != nil {
+				t.Fatal(err)
+			}
+			// Возврат к схеме с текстовыми столбцами, как в базах до появления справочников
+			if _, err := RollbackLast(database); err != nil {
+				t.Fatal(err)
+			}
+			_, err := database.Exec(`
+				INSERT INTO Cars (Brand, Model, YearOfRelease, Color, Price) VALUES
+					('toyota', 'Camry', 2020, 'Black', 20000),
+					('Toyota', 'camry ', 2021, 'Черный', 21000),
+					('Тойота', 'Corolla', 2019, 'black', 15000);
+			`)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := Migrate(database); err != nil {
+				t.Fatal(err)
+			}
+
+			store := NewSQLStore(database)
+			for kind, want := range map[DictionaryKind][]string{
+				DictBrands: {"Toyota"},
+				DictModels: {"Camry", "Corolla"},
+				DictColors: {"Черный"},
+			} {
+				entries, err := store.Dictionaries.List(kind, 0)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var names []string
+				for _, e := range entries {
+					names = append(names, e.Name)
+				}
+				if len(names) != len(want) {
+					t.Errorf("%s: %v, ожидалось %v", kind, names, want)
+					continue
+				}
+				for i := range want {
+					if names[i] != want[i] {
+						t.Errorf("%s: %v, ожидалось %v", kind, names, want)
+					}
+				}
+			}
+
+			cars, err := store.Cars.List()
+			if err != nil || len(cars) != 3 {
+				t.Fatalf("автомобили: %v, %v", cars, err)
+			}
+
+			// Откат возвращает названия в текстовые столбцы
+			if _, err := RollbackLast(database); err != nil {
+				t.Fatal(err)
+			}
+			var brand, color sql.NullString
+			if err := database.QueryRow("SELECT Brand, Color FROM Cars ORDER BY ID_Car LIMIT 1").Scan(&brand, &color); err != nil {
+				t.Fatal(err)
+			}
+			if brand.String != "Toyota" || color.String != "Черный" {
+				t.Errorf("после отката: %v, %v", brand, color)
+			}
+		})
+	}
+}
+
+func TestDictionaryMigrationBlankValues(t *testing.T) {
+	for name, database := range testDatabases(t) {
+		t.Run(name, func(t *testing.T) {
+			if err := inTx(database, migrations[0].Up); err != nil {
+				t.Fatal(err)
+			}
+			_, err := database.Exec(`
+				INSERT INTO Cars (Brand, Model, YearOfRelease, Color, Price) VALUES
+					(' ', 'Camry', 2020, 'Black', 20000),
+					(NULL, NULL, 2021, NULL, 21000),
+					('Toyota', '', 2019, '  ', 15000);
+			`)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := Migrate(database); err != nil {
+				t.Fatal(err)
+			}
+
+			store := NewSQLStore(database)
+			cars, err := store.Cars.List()
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := []struct{ brand, model, color string }{
+				{unspecifiedName, "Camry", "Black"},
+				{unspecifiedName, unspecifiedName, unspecifiedName},
+				{"Toyota", unspecifiedName, unspecifiedName},
+			}
+			if len(cars) != len(want) {
+				t.Fatalf("автомобили: %+v", cars)
+			}
+			for i, w := range want {
+				if cars[i].Brand != w.brand || cars[i].Model != w.model || cars[i].Color != w.color {
+					t.Errorf("автомобиль %d: %s %s %s, ожидалось %s %s %s", i, cars[i].Brand, cars[i].Model, cars[i].Color, w.brand, w.model, w.color)
+				}
+			}
+
+			// Автомобиль без ссылки на справочник, как в базах, обновлённых до появления «Не указано»
+			if _, err := database.Exec("UPDATE Cars SET ID_Brand = NULL, ID_Model = NULL, ID_Color = NULL WHERE ID_Car = ?", cars[0].ID); err != nil {
+				t.Fatal(err)
+			}
+			if cars, err := store.Cars.List(); err != nil || len(cars) != 3 || cars[0].Brand != "" {
+				t.Errorf("автомобиль без марки: %+v, %v", cars, err)
+			}
+		})
+	}
+}
+
+func TestBrandMatchesVIN(t *testing.T) {
+	tests := []struct {
+		decoded, typed string
+		want           bool
+	}{
+		{"Mercedes-Benz", "mercedes", true},
+		{"Mercedes-Benz", "Mercedes Benz", true},
+		{"Mercedes-Benz", "Мерседес", true},
+		{"Land Rover", "LandRover", true},
+		{"Volkswagen", "VW", true},
+		{"BMW", "Audi", false},
+		{"Mini", "Mini Cooper", false},
+		{"Kia", "K", false},
+		{"BMW", "", true},
+	}
+	for _, tt := range tests {
+		if got := BrandMatchesVIN(tt.decoded, tt.typed); got != tt.want {
+			t.Errorf("BrandMatchesVIN(%q, %q) = %v", tt.decoded, tt.typed, got)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package db
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CarSort — порядок автомобилей в каталоге
+type CarSort string
+
+const (
+	SortNewest    CarSort = "newest"     // сначала недавно добавленные
+	SortPriceAsc  CarSort = "price_asc"  // сначала дешёвые
+	SortPriceDesc CarSort = "price_desc" // сначала дорогие
+	SortYearDesc  CarSort = "year_desc"  // сначала новые по году выпуска
+	SortYearAsc   CarSort = "year_asc"   // сначала старые по году выпуска
+)
+
+// CarSorts — порядки сортировки в порядке отображения
+var CarSorts = []CarSort{SortNewest, SortPriceAsc, SortPriceDesc, SortYearDesc, SortYearAsc}
+
+var carSortTitles = map[CarSort]string{
+	SortNewest:    "Сначала новые поступления",
+	SortPriceAsc:  "Сначала дешёвые",
+	SortPriceDesc: "Сначала дорогие",
+	SortYearDesc:  "Сначала новые по году",
+	SortYearAsc:   "Сначала старые по году",
+}
+
+// Title возвращает название порядка сортировки для интерфейса
+func (s CarSort) Title() string {
+	if title, ok := carSortTitles[s]; ok {
+		return title
+	}
+	return string(s)
+}
+
+// orderBy возвращает выражение ORDER BY для сортировки; при равенстве порядок определяет ID
+func (s CarSort) orderBy() string {
+	switch s {
+	case SortPriceAsc:
+		return "Price ASC, ID_Car DESC"
+	case SortPriceDesc:
+		return "Price DESC, ID_Car DESC"
+	case SortYearDesc:
+		return "YearOfRelease DESC, ID_Car DESC"
+	case SortYearAsc:
+		return "YearOfRelease ASC, ID_Car DESC"
+	default:
+		return "ID_Car DESC"
+	}
+}
+
+// CarFilter — условия поиска автомобилей. Нулевые поля не ограничивают выборку.
+type CarFilter struct {
+	Statuses  []CarStatus
+	Brand     string
+	Model     string
+	Color     string
+	YearFrom  int
+	YearTo    int
+	PriceFrom float64
+	PriceTo   float64
+	// Search — слова, каждое из которых должно встречаться в марке, модели, цвете или годе выпуска
+	Search string
+	Sort   CarSort
+	// Limit и Offset выбирают одну страницу результата: не больше Limit автомобилей
+	// после первых Offset. Нулевой Limit не ограничивает выборку.
+	Limit  int
+	Offset int
+}
+
+// ErrInvalidFilter возвращается для границы диапазона, которая не является годом или ценой
+var ErrInvalidFilter = errors.New("неверная граница диапазона")
+
+// ParseYearBound разбирает границу диапазона года выпуска, введённую покупателем.
+// Пустая строка не ограничивает выборку и даёт 0.
+func ParseYearBound(text string) (int, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0, nil
+	}
+	year, err := strconv.Atoi(text)
+	if err != nil || year <= 0 {
+		return 0, ErrInvalidFilter
+	}
+	return year, nil
+}
+
+// ParsePriceBound разбирает границу диапазона цены, введённую покупателем.
+// Пустая строка не ограничивает выборку и даёт 0.
+func ParsePriceBound(text string) (float64, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0, nil
+	}
+	price, err := strconv.ParseFloat(text, 64)
+	if err != nil || price < 0 || math.IsNaN(price) || math.IsInf(price, 0) {
+		return 0, ErrInvalidFilter
+	}
+	return price, nil
+}
+
+// searchTerms разбивает строку поиска на слова
+func (f CarFilter) searchTerms() []string {
+	return strings.Fields(f.Search)
+}
+
+// matches проверяет автомобиль на соответствие фильтру так же, как SQL-запрос Search; страница не учитывается.
+// Слова поиска сравниваются без учёта регистра любых букв, как ILIKE в PostgreSQL. LIKE в SQLite
+// не различает регистр только у латиницы, поэтому там «лада» не находит «Лада».
+func (f CarFilter) matches(car Car) bool {
+	switch {
+	case len(f.Statuses) > 0 && !containsStatus(f.Statuses, car.Status),
+		f.Brand != "" && DictionaryKey(DictBrands, car.Brand) != DictionaryKey(DictBrands, f.Brand),
+		f.Model != "" && DictionaryKey(DictModels, car.Model) != DictionaryKey(DictModels, f.Model),
+		f.Color != "" && DictionaryKey(DictColors, car.Color) != DictionaryKey(DictColors, f.Color),
+		f.YearFrom != 0 && car.Year < f.YearFrom,
+		f.YearTo != 0 && car.Year > f.YearTo,
+		f.PriceFrom != 0 && car.Price < f.PriceFrom,
+		f.PriceTo != 0 && car.Price > f.PriceTo:
+		return false
+	}
+	for _, term := range f.searchTerms() {
+		term = strings.ToLower(term)
+		found := false
+		for _, field := range []string{car.Brand, car.Model, car.Color, strconv.Itoa(car.Year)} {
+			if strings.Contains(strings.ToLower(field), term) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// page возвращает страницу Limit/Offset из уже отобранных и упорядоченных автомобилей
+func (f CarFilter) page(cars []Car) []Car {
+	offset := max(f.Offset, 0)
+	if offset >= len(cars) {
+		return nil
+	}
+	cars = cars[offset:]
+	if f.Limit > 0 && f.Limit < len(cars) {
+		cars = cars[:f.Limit]
+	}
+	return cars
+}
+
+// escapeLike экранирует спецсимволы шаблона LIKE символом '\'
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// CheckFilter — условия выборки чеков. Нулевые поля не ограничивают выборку.
+type CheckFilter struct {
+	Statuses []CheckStatus
+	// From и To ограничивают время оформления промежутком [From, To).
+	// Чеки, оформленные до учёта времени продаж, в выборку по времени не попадают.
+	From time.Time
+	To   time.Time
+}
+
+// matches проверяет чек на соответствие фильтру так же, как SQL-запрос List
+func (f CheckFilter) matches(check Check) bool {
+	if len(f.Statuses) > 0 && !containsCheckStatus(f.Statuses, check.Status) {
+		return false
+	}
+	if (!f.From.IsZero() || !f.To.IsZero()) && check.CreatedAt.IsZero() {
+		return false
+	}
+	return (f.From.IsZero() || !check.CreatedAt.Before(f.From)) &&
+		(f.To.IsZero() || check.CreatedAt.Before(f.To))
+}
+
+// where возвращает условие SQL для таблицы Checks и его аргументы
+func (f CheckFilter) where() (string, []any) {
+	conds := []string{"1 = 1"}
+	var args []any
+	if len(f.Statuses) > 0 {
+		conds = append(conds, "Status IN ("+placeholders(len(f.Statuses))+")")
+		for _, s := range f.Statuses {
+			args = append(args, s)
+		}
+	}
+	if !f.From.IsZero() {
+		conds = append(conds, "CreatedAt >= ?")
+		args = append(args, f.From.UTC())
+	}
+	if !f.To.IsZero() {
+		conds = append(conds, "CreatedAt < ?")
+		args = append(args, f.To.UTC())
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+func containsCheckStatus(statuses []CheckStatus, status CheckStatus) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,182 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSearchCars(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			cars := []Car{
+				{Brand: "Toyota", Model: "Camry", Year: 2018, Color: "Black", Price: 18000},
+				{Brand: "Toyota", Model: "Corolla", Year: 2021, Color: "White", Price: 15000},
+				{Brand: "BMW", Model: "X5", Year: 2020, Color: "Black", Price: 50000},
+				{Brand: "Audi", Model: "A4_Sport", Year: 2019, Color: "Red", Price: 30000},
+			}
+			for i := range cars {
+				if err := store.Cars.Create(&cars[i]); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := store.Cars.SetStatus(cars[2].ID, StatusWithdrawn); err != nil {
+				t.Fatal(err)
+			}
+
+			tests := []struct {
+				name   string
+				filter CarFilter
+				want   []int
+			}{
+				{"все, новые поступления первыми", CarFilter{}, []int{3, 2, 1, 0}},
+				{"только в наличии", CarFilter{Statuses: []CarStatus{StatusInStock}}, []int{3, 1, 0}},
+				{"марка без учёта регистра", CarFilter{Brand: "toyota", Sort: SortPriceAsc}, []int{1, 0}},
+				{"цвет и цена", CarFilter{Color: "Black", PriceTo: 20000}, []int{0}},
+				{"диапазон лет", CarFilter{YearFrom: 2019, YearTo: 2020, Sort: SortYearDesc}, []int{2, 3}},
+				{"цена по убыванию", CarFilter{PriceFrom: 16000, Sort: SortPriceDesc}, []int{2, 3, 0}},
+				{"поиск по нескольким словам", CarFilter{Search: "toy 2021"}, []int{1}},
+				{"поиск по модели", CarFilter{Search: "x5"}, []int{2}},
+				{"спецсимволы LIKE", CarFilter{Search: "a4_"}, []int{3}},
+				{"подчёркивание не шаблон", CarFilter{Search: "_"}, []int{3}},
+				{"ничего не найдено", CarFilter{Search: "Lada"}, nil},
+				{"страница", CarFilter{Sort: SortPriceAsc, Limit: 2, Offset: 1}, []int{0, 3}},
+				{"смещение без ограничения", CarFilter{Sort: SortPriceAsc, Offset: 3}, []int{2}},
+				{"страница за концом", CarFilter{Limit: 2, Offset: 4}, nil},
+			}
+			for _, tt := range tests {
+				got, err := store.Cars.Search(tt.filter)
+				if err != nil {
+					t.Fatalf("%s: %v", tt.name, err)
+				}
+				if len(got) != len(tt.want) {
+					t.Errorf("%s: найдено %d, ожидалось %d: %+v", tt.name, len(got), len(tt.want), got)
+					continue
+				}
+				for i, idx := range tt.want {
+					if got[i].ID != cars[idx].ID {
+						t.Errorf("%s: позиция %d — %s %s", tt.name, i, got[i].Brand, got[i].Model)
+					}
+				}
+			}
+
+			counts := []struct {
+				filter CarFilter
+				want   int
+			}{
+				{CarFilter{}, 4},
+				{CarFilter{Statuses: []CarStatus{StatusInStock}, Limit: 1, Offset: 1}, 3},
+				{CarFilter{Brand: "toyota", Search: "2021"}, 1},
+				{CarFilter{Search: "Lada"}, 0},
+			}
+			for _, c := range counts {
+				if got, err := store.Cars.Count(c.filter); err != nil || got != c.want {
+					t.Errorf("число автомобилей по фильтру %+v: %d, %v, ожидалось %d", c.filter, got, err, c.want)
+				}
+			}
+		})
+	}
+}
+
+// TestSearchCyrillicCase закрепляет различие хранилищ: регистр кириллицы в поиске
+// не учитывается в памяти и в PostgreSQL, но учитывается в SQLite
+func TestSearchCyrillicCase(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			car := Car{Brand: "Лада", Model: "Веста", Year: 2020, Color: "Белый", Price: 12000}
+			if err := store.Cars.Create(&car); err != nil {
+				t.Fatal(err)
+			}
+			foldsCyrillic := name != "sqlite"
+			for _, tt := range []struct {
+				search string
+				want   bool
+			}{
+				{"Лада", true},
+				{"Вест", true},
+				{"лада", foldsCyrillic},
+				{"ВЕСТА", foldsCyrillic},
+				{"белый", foldsCyrillic},
+			} {
+				got, err := store.Cars.Search(CarFilter{Search: tt.search})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if found := len(got) == 1; found != tt.want {
+					t.Errorf("поиск %q: найдено %v, ожидалось %v", tt.search, found, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestListChecks(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			client := addTestClient(t, store, "buyer")
+			car := addTestCar(t, store)
+			day := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+			checks := []Check{
+				{ClientID: client.ID, CarID: car.ID, Price: 100, Status: CheckApproved, CreatedAt: day},
+				{ClientID: client.ID, CarID: car.ID, Price: 200, Status: CheckRejected, CreatedAt: day.AddDate(0, 0, 1)},
+				{ClientID: client.ID, CarID: car.ID, Price: 300, Status: CheckApproved, CreatedAt: day.AddDate(0, 1, 0)},
+			}
+			for i := range checks {
+				if err := store.Checks.Create(&checks[i]); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			tests := []struct {
+				name   string
+				filter CheckFilter
+				want   []int
+			}{
+				{"все", CheckFilter{}, []int{0, 1, 2}},
+				{"подтверждённые", CheckFilter{Statuses: []CheckStatus{CheckApproved}}, []int{0, 2}},
+				{"март", CheckFilter{From: day.AddDate(0, 0, -1), To: day.AddDate(0, 1, 0)}, []int{0, 1}},
+				{"подтверждённые с марта", CheckFilter{Statuses: []CheckStatus{CheckApproved}, From: day.AddDate(0, 0, 1)}, []int{2}},
+			}
+			for _, tt := range tests {
+				got, err := store.Checks.List(tt.filter)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(got) != len(tt.want) {
+					t.Errorf("%s: %d чеков, ожидалось %d", tt.name, len(got), len(tt.want))
+					continue
+				}
+				for i, idx := range tt.want {
+					if got[i].ID != checks[idx].ID || got[i].Price != checks[idx].Price || !got[i].CreatedAt.Equal(checks[idx].CreatedAt) {
+						t.Errorf("%s: чек %d: %+v, ожидался %+v", tt.name, i, got[i], checks[idx])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestParseBounds(t *testing.T) {
+	years := []struct {
+		text string
+		want int
+		ok   bool
+	}{{"", 0, true}, {" 2019 ", 2019, true}, {"0", 0, false}, {"двадцать", 0, false}}
+	for _, tt := range years {
+		got, err := ParseYearBound(tt.text)
+		if got != tt.want || (err == nil) != tt.ok {
+			t.Errorf("ParseYearBound(%q) = %d, %v", tt.text, got, err)
+		}
+	}
+
+	prices := []struct {
+		text string
+		want float64
+		ok   bool
+	}{{"", 0, true}, {"15000.50", 15000.50, true}, {"-1", 0, false}, {"NaN", 0, false}, {"Inf", 0, false}}
+	for _, tt := range prices {
+		got, err := ParsePriceBound(tt.text)
+		if got != tt.want || (err == nil) != tt.ok {
+			t.Errorf("ParsePriceBound(%q) = %v, %v", tt.text, got, err)
+		}
+	}
+}
@@ -0,0 +1,592 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// memoryData — общее состояние хранилища в памяти.
+// Все репозитории работают с одним экземпляром под общей блокировкой.
+type memoryData struct {
+	mu      sync.Mutex
+	cars    map[int]Car
+	clients map[int]Client
+	admins  map[int]Admin
+	checks  map[int]Check
+	photos  map[int]CarPhoto
+	nextID  map[string]int
+
+	statusHistory []CarStatusChange
+	changeHistory []CarFieldChange
+	priceHistory  []CarPriceChange
+
+	// Автомобили хранят названия из справочников, поэтому переименование и объединение меняют и их
+	dictionaries map[DictionaryKind]map[int]DictionaryEntry
+	aliases      map[memoryAlias]int
+}
+
+// NewMemoryStore создаёт пустое хранилище в памяти (для тестов и демонстрации без базы данных)
+func NewMemoryStore() *Store {
+	d := &memoryData{
+		cars:    make(map[int]Car),
+		clients: make(map[int]Client),
+		admins:  make(map[int]Admin),
+		checks:  make(map[int]Check),
+		photos:  make(map[int]CarPhoto),
+		nextID:  make(map[string]int),
+		dictionaries: map[DictionaryKind]map[int]DictionaryEntry{
+			DictBrands: make(map[int]DictionaryEntry),
+			DictModels: make(map[int]DictionaryEntry),
+			DictColors: make(map[int]DictionaryEntry),
+		},
+		aliases: make(map[memoryAlias]int),
+	}
+	return &Store{
+		Cars:         &memoryCarRepository{d: d},
+		Clients:      &memoryClientRepository{d: d},
+		Admins:       &memoryAdminRepository{d: d},
+		Checks:       &memoryCheckRepository{d: d},
+		Photos:       &memoryPhotoRepository{d: d},
+		Purchases:    &memoryPurchaseService{d: d},
+		Dictionaries: &memoryDictionaryRepository{d: d},
+	}
+}
+
+// newID выдаёт следующий идентификатор для таблицы, как AUTOINCREMENT в SQLite
+func (d *memoryData) newID(table string) int {
+	d.nextID[table]++
+	return d.nextID[table]
+}
+
+type memoryCarRepository struct {
+	d *memoryData
+}
+
+func (r *memoryCarRepository) Create(car *Car) error {
+	if err := ValidateCar(*car); err != nil {
+		return err
+	}
+
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	if r.d.vinTaken(car.VIN, 0) {
+		return ErrDuplicateVIN
+	}
+	r.d.createCar(car)
+	return nil
+}
+
+func (r *memoryCarRepository) CreateBatch(cars []Car) error {
+	vins := make(map[string]bool)
+	for i, car := range cars {
+		if err := ValidateCar(car); err != nil {
+			return fmt.Errorf("автомобиль %d: %w", i+1, err)
+		}
+	}
+
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	// Все проверки выполняются до первого изменения, чтобы при ошибке не добавился ни один автомобиль
+	for i, car := range cars {
+		if car.VIN != "" && (vins[car.VIN] || r.d.vinTaken(car.VIN, 0)) {
+			return fmt.Errorf("автомобиль %d: %w", i+1, ErrDuplicateVIN)
+		}
+		vins[car.VIN] = true
+	}
+	for i := range cars {
+		r.d.createCar(&cars[i])
+	}
+	return nil
+}
+
+// createCar добавляет проверенный автомобиль; вызывается под блокировкой
+func (d *memoryData) createCar(car *Car) {
+	d.resolveCar(car)
+	car.ID = d.newID("Cars")
+	car.Status = StatusInStock
+	d.cars[car.ID] = *car
+	d.recordPrice(car.ID, car.Price, 0)
+	d.recordStatusChange(car.ID, "", car.Status)
+}
+
+func (r *memoryCarRepository) Get(id int) (*Car, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	car, ok := r.d.cars[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &car, nil
+}
+
+func (r *memoryCarRepository) List(statuses ...CarStatus) ([]Car, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	var cars []Car
+	for _, car := range r.d.cars {
+		if len(statuses) > 0 && !containsStatus(statuses, car.Status) {
+			continue
+		}
+		cars = append(cars, car)
+	}
+	sort.Slice(cars, func(i, j int) bool { return cars[i].ID < cars[j].ID })
+	return cars, nil
+}
+
+func (r *memoryCarRepository) Search(filter CarFilter) ([]Car, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	var cars []Car
+	for _, car := range r.d.cars {
+		if filter.matches(car) {
+			cars = append(cars, car)
+		}
+	}
+	sort.Slice(cars, func(i, j int) bool {
+		a, b := cars[i], cars[j]
+		switch {
+		case filter.Sort == SortPriceAsc && a.Price != b.Price:
+			return a.Price < b.Price
+		case filter.Sort == SortPriceDesc && a.Price != b.Price:
+			return a.Price > b.Price
+		case filter.Sort == SortYearDesc && a.Year != b.Year:
+			return a.Year > b.Year
+		case filter.Sort == SortYearAsc && a.Year != b.Year:
+			return a.Year < b.Year
+		}
+		return a.ID > b.ID
+	})
+	return filter.page(cars), nil
+}
+
+func (r *memoryCarRepository) Count(filter CarFilter) (int, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	count := 0
+	for _, car := range r.d.cars {
+		if filter.matches(car) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *memoryCarRepository) SetStatus(id int, to CarStatus) error {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	return r.d.setStatus(id, to, checkManualTransition)
+}
+
+func (r *memoryCarRepository) StatusHistory(id int) ([]CarStatusChange, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	var history []CarStatusChange
+	for _, change := range r.d.statusHistory {
+		if change.CarID == id {
+			history = append(history, change)
+		}
+	}
+	return history, nil
+}
+
+func (r *memoryCarRepository) Update(car Car, adminID int) ([]CarFieldChange, error) {
+	if err := ValidateCar(car); err != nil {
+		return nil, err
+	}
+
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	old, ok := r.d.cars[car.ID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if r.d.vinTaken(car.VIN, car.ID) {
+		return nil, ErrDuplicateVIN
+	}
+	r.d.resolveCar(&car)
+	changes := diffCars(old, car, adminID, time.Now().UTC())
+	car.Status = old.Status
+	r.d.cars[car.ID] = car
+	r.d.changeHistory = append(r.d.changeHistory, changes...)
+	if car.Price != old.Price {
+		r.d.recordPrice(car.ID, car.Price, adminID)
+	}
+	return changes, nil
+}
+
+func (r *memoryCarRepository) ChangeHistory(id int) ([]CarFieldChange, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	var history []CarFieldChange
+	for _, change := range r.d.changeHistory {
+		if change.CarID == id {
+			history = append(history, change)
+		}
+	}
+	return history, nil
+}
+
+func (r *memoryCarRepository) PriceHistory(id int) ([]CarPriceChange, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	var history []CarPriceChange
+	for _, p := range r.d.priceHistory {
+		if p.CarID == id {
+			history = append(history, p)
+		}
+	}
+	return history, nil
+}
+
+func (d *memoryData) recordPrice(carID int, price float64, adminID int) {
+	d.priceHistory = append(d.priceHistory, CarPriceChange{CarID: carID, Price: price, AdminID: adminID, ChangedAt: time.Now().UTC()})
+}
+
+// vinTaken сообщает, занят ли VIN другим автомобилем, кроме exceptID; вызывается под блокировкой
+func (d *memoryData) vinTaken(vin string, exceptID int) bool {
+	if vin == "" {
+		return false
+	}
+	for _, car := range d.cars {
+		if car.VIN == vin && car.ID != exceptID {
+			return true
+		}
+	}
+	return false
+}
+
+// setStatus проверяет переход функцией check и меняет состояние автомобиля; вызывается под блокировкой
+func (d *memoryData) setStatus(id int, to CarStatus, check func(from, to CarStatus) error) error {
+	car, ok := d.cars[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if err := check(car.Status, to); err != nil {
+		return err
+	}
+
+	d.recordStatusChange(id, car.Status, to)
+	car.Status = to
+	d.cars[id] = car
+	return nil
+}
+
+func (d *memoryData) recordStatusChange(carID int, from, to CarStatus) {
+	d.statusHistory = append(d.statusHistory, CarStatusChange{CarID: carID, From: from, To: to, ChangedAt: time.Now().UTC()})
+}
+
+func containsStatus(statuses []CarStatus, status CarStatus) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+type memoryClientRepository struct {
+	d *memoryData
+}
+
+func (r *memoryClientRepository) Create(client *Client) error {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	for _, c := range r.d.clients {
+		if c.Login == client.Login {
+			return fmt.Errorf("ошибка регистрации клиента: логин %q уже занят", client.Login)
+		}
+	}
+	client.ID = r.d.newID("Client")
+	r.d.clients[client.ID] = *client
+	return nil
+}
+
+func (r *memoryClientRepository) GetByLogin(login string) (*Client, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	for _, c := range r.d.clients {
+		if c.Login == login {
+			return &c, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryClientRepository) UpdatePassword(id int, password string) error {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	c, ok := r.d.clients[id]
+	if !ok {
+		return ErrNotFound
+	}
+	c.Password = password
+	r.d.clients[id] = c
+	return nil
+}
+
+func (r *memoryClientRepository) List() ([]Client, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	var clients []Client
+	for _, c := range r.d.clients {
+		c.Password = ""
+		clients = append(clients, c)
+	}
+	sort.Slice(clients, func(i, j int) bool { return clients[i].ID < clients[j].ID })
+	return clients, nil
+}
+
+func (r *memoryClientRepository) Delete(id int) error {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	if _, ok := r.d.clients[id]; !ok {
+		return ErrNotFound
+	}
+	for checkID, check := range r.d.checks {
+		if check.ClientID != id {
+			continue
+		}
+		// Бронь неподтверждённого заказа снимается вместе с заказом, как в releasePendingCars
+		if car, ok := r.d.cars[check.CarID]; ok && check.Status == CheckPending && car.Status == StatusReserved {
+			if err := r.d.setStatus(check.CarID, StatusInStock, checkTransition); err != nil {
+				return err
+			}
+		}
+		delete(r.d.checks, checkID)
+	}
+	delete(r.d.clients, id)
+	return nil
+}
+
+type memoryAdminRepository struct {
+	d *memoryData
+}
+
+func (r *memoryAdminRepository) Create(admin *Admin) error {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	for _, a := range r.d.admins {
+		if a.Login == admin.Login {
+			return fmt.Errorf("ошибка добавления администратора: логин %q уже занят", admin.Login)
+		}
+	}
+	admin.ID = r.d.newID("Administrator")
+	r.d.admins[admin.ID] = *admin
+	return nil
+}
+
+func (r *memoryAdminRepository) GetByLogin(login string) (*Admin, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	for _, a := range r.d.admins {
+		if a.Login == login {
+			return &a, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryAdminRepository) UpdatePassword(id int, password string) error {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	a, ok := r.d.admins[id]
+	if !ok {
+		return ErrNotFound
+	}
+	a.Password = password
+	r.d.admins[id] = a
+	return nil
+}
+
+func (r *memoryAdminRepository) List() ([]Admin, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	var admins []Admin
+	for _, a := range r.d.admins {
+		a.Password = ""
+		admins = append(admins, a)
+	}
+	sort.Slice(admins, func(i, j int) bool { return admins[i].ID < admins[j].ID })
+	return admins, nil
+}
+
+type memoryCheckRepository struct {
+	d *memoryData
+}
+
+func (r *memoryCheckRepository) Create(check *Check) error {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	if check.Status == "" {
+		check.Status = CheckPending
+	}
+	if check.CreatedAt.IsZero() {
+		check.CreatedAt = time.Now().UTC()
+	}
+	check.ID = r.d.newID("Checks")
+	r.d.checks[check.ID] = *check
+	return nil
+}
+
+func (r *memoryCheckRepository) Get(id int) (*Check, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	check, ok := r.d.checks[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &check, nil
+}
+
+func (r *memoryCheckRepository) List(filter CheckFilter) ([]Check, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	var checks []Check
+	for _, check := range r.d.checks {
+		if filter.matches(check) {
+			checks = append(checks, check)
+		}
+	}
+	sort.Slice(checks, func(i, j int) bool { return checks[i].ID < checks[j].ID })
+	return checks, nil
+}
+
+func (r *memoryCheckRepository) ListByClient(clientID int) ([]Purchase, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	var purchases []Purchase
+	for _, check := range r.d.checks {
+		if check.ClientID != clientID {
+			continue
+		}
+		p := Purchase{
+			CheckID:   check.ID,
+			Price:     check.Price,
+			Status:    check.Status,
+			CreatedAt: check.CreatedAt,
+			DecidedAt: check.DecidedAt,
+		}
+		if car, ok := r.d.cars[check.CarID]; ok {
+			p.Brand, p.Model, p.Year = car.Brand, car.Model, car.Year
+		} else {
+			p.CarDeleted = true
+		}
+		purchases = append(purchases, p)
+	}
+	sort.Slice(purchases, func(i, j int) bool { return purchases[i].CheckID < purchases[j].CheckID })
+	return purchases, nil
+}
+
+func (r *memoryCheckRepository) ListPending() ([]PendingOrder, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	var orders []PendingOrder
+	for _, check := range r.d.checks {
+		client, hasClient := r.d.clients[check.ClientID]
+		car, hasCar := r.d.cars[check.CarID]
+		if check.Status != CheckPending || !hasClient || !hasCar {
+			continue
+		}
+		orders = append(orders, PendingOrder{
+			CheckID:        check.ID,
+			ClientID:       client.ID,
+			ClientName:     client.Name,
+			ClientLastName: client.LastName,
+			CarID:          car.ID,
+			Brand:          car.Brand,
+			Model:          car.Model,
+			Year:           car.Year,
+			Price:          check.Price,
+		})
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].CheckID < orders[j].CheckID })
+	return orders, nil
+}
+
+func (r *memoryCheckRepository) SalesSummary(from, to time.Time) (SalesSummary, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	var summary SalesSummary
+	for _, check := range r.d.checks {
+		if check.Status != CheckApproved || check.DecidedAt.IsZero() {
+			continue
+		}
+		if check.DecidedAt.Before(from) || !check.DecidedAt.Before(to) {
+			continue
+		}
+		summary.Revenue += check.Price
+		summary.Units++
+	}
+	return summary, nil
+}
+
+func (r *memoryCheckRepository) ListSales(includeArchived bool) ([]Sale, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	var sales []Sale
+	for _, check := range r.d.checks {
+		if check.Status != CheckApproved {
+			continue
+		}
+		car, hasCar := r.d.cars[check.CarID]
+		if !includeArchived && (!hasCar || car.Status == StatusArchived) {
+			continue
+		}
+		sale := Sale{
+			CheckID:     check.ID,
+			CarID:       check.CarID,
+			Brand:       car.Brand,
+			Model:       car.Model,
+			Year:        car.Year,
+			Color:       car.Color,
+			CarStatus:   car.Status,
+			CarDeleted:  !hasCar,
+			AdminID:     check.AdminID,
+			Price:       check.Price,
+			ListedPrice: check.Price,
+			CreatedAt:   check.CreatedAt,
+			DecidedAt:   check.DecidedAt,
+		}
+		for _, p := range r.d.priceHistory {
+			if p.CarID == check.CarID && !check.CreatedAt.IsZero() && !p.ChangedAt.After(check.CreatedAt) {
+				sale.ListedPrice = p.Price
+				break
+			}
+		}
+		if admin, ok := r.d.admins[check.AdminID]; ok {
+			sale.AdminName, sale.AdminLastName = admin.Name, admin.LastName
+		}
+		sales = append(sales, sale)
+	}
+	sort.Slice(sales, func(i, j int) bool { return sales[i].CheckID < sales[j].CheckID })
+	return sales, nil
+}
@@ -0,0 +1,629 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// migration описывает один пронумерованный шаг изменения схемы.
+// Up и Down выполняются внутри транзакции и должны быть идемпотентными,
+// чтобы старые файлы carssale.db, созданные до появления миграций, обновлялись на месте.
+type migration struct {
+	Version int
+	Name    string
+	Up      func(tx *Tx) error
+	Down    func(tx *Tx) error
+}
+
+// ErrIrreversibleMigration возвращается при попытке откатить базовую схему:
+// её откат удалил бы таблицы вместе со всеми клиентами, автомобилями и продажами.
+var ErrIrreversibleMigration = errors.New("базовую схему откатить нельзя")
+
+// MigrationStatus показывает состояние одной миграции в базе данных
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// migrations — полный список миграций в порядке применения.
+// Новые миграции добавляются только в конец списка со следующим номером.
+var migrations = []migration{
+	{
+		Version: 1,
+		Name:    "базовые таблицы",
+		Up: func(tx *Tx) error {
+			_, err := tx.execSchema(`
+ CREATE TABLE IF NOT EXISTS Client (
+  ID_Client INTEGER PRIMARY KEY AUTOINCREMENT,
+  Name VARCHAR(50),
+  LastName VARCHAR(50),
+  Phone VARCHAR(15),
+  Login VARCHAR(50) UNIQUE,
+  Password VARCHAR(255)
+ );
+
+ CREATE TABLE IF NOT EXISTS Cars (
+  ID_Car INTEGER PRIMARY KEY AUTOINCREMENT,
+  Brand VARCHAR(50),
+  Model VARCHAR(50),
+  YearOfRelease INTEGER,
+  Color VARCHAR(30),
+  Price DECIMAL(10, 2)
+ );
+
+ CREATE TABLE IF NOT EXISTS Administrator (
+  ID_Admin INTEGER PRIMARY KEY AUTOINCREMENT,
+  Name VARCHAR(50),
+  LastName VARCHAR(50),
+  Login VARCHAR(50) UNIQUE,
+  Password VARCHAR(255),
+  Phone VARCHAR(15)
+ );
+
+ CREATE TABLE IF NOT EXISTS Checks (
+  ID_Check INTEGER PRIMARY KEY AUTOINCREMENT,
+  ID_Client INTEGER NOT NULL,
+  ID_Car INTEGER NOT NULL,
+  ID_Admin INTEGER NOT NULL,
+  Price DECIMAL(10, 2),
+  FOREIGN KEY (ID_Client) REFERENCES Client(ID_Client),
+  FOREIGN KEY (ID_Car) REFERENCES Cars(ID_Car),
+  FOREIGN KEY (ID_Admin) REFERENCES Administrator(ID_Admin)
+ );
+ `)
+			return err
+		},
+		// Базовой схемой записываются и принятые старые файлы carssale.db, поэтому она не откатывается
+		Down: func(tx *Tx) error {
+			return ErrIrreversibleMigration
+		},
+	},
+	{
+		Version: 2,
+		Name:    "архив автомобилей и чеки без администратора",
+		Up: func(tx *Tx) error {
+			if err := addColumnIfMissing(tx, "Cars", "IsArchived", "BOOLEAN DEFAULT FALSE"); err != nil {
+				return err
+			}
+
+			// Клиент оформляет покупку без администратора, поэтому ID_Admin должен допускать NULL.
+			// SQLite не умеет снимать NOT NULL через ALTER TABLE, поэтому таблица пересоздаётся.
+			columns, err := tableColumns(tx, "Checks")
+			if err != nil {
+				return err
+			}
+			if admin, _ := columns.get("ID_Admin"); !admin.NotNull {
+				return nil
+			}
+			if tx.dialect == Postgres {
+				_, err = tx.Exec("ALTER TABLE Checks ALTER COLUMN ID_Admin DROP NOT NULL")
+				return err
+			}
+			_, err = tx.execSchema(`
+ CREATE TABLE Checks_new (
+  ID_Check INTEGER PRIMARY KEY AUTOINCREMENT,
+  ID_Client INTEGER NOT NULL,
+  ID_Car INTEGER NOT NULL,
+  ID_Admin INTEGER,
+  Price DECIMAL(10, 2),
+  FOREIGN KEY (ID_Client) REFERENCES Client(ID_Client),
+  FOREIGN KEY (ID_Car) REFERENCES Cars(ID_Car),
+  FOREIGN KEY (ID_Admin) REFERENCES Administrator(ID_Admin)
+ );
+ INSERT INTO Checks_new (ID_Check, ID_Client, ID_Car, ID_Admin, Price)
+  SELECT ID_Check, ID_Client, ID_Car, ID_Admin, Price FROM Checks;
+ DROP TABLE Checks;
+ ALTER TABLE Checks_new RENAME TO Checks;
+ `)
+			return err
+		},
+		// Ограничение NOT NULL для ID_Admin не возвращается: в таблице уже могут быть чеки без администратора.
+		Down: func(tx *Tx) error {
+			return dropColumnIfExists(tx, "Cars", "IsArchived")
+		},
+	},
+	{
+		Version: 3,
+		Name:    "признак проданного автомобиля",
+		Up: func(tx *Tx) error {
+			if err := addColumnIfMissing(tx, "Cars", "IsSold", "BOOLEAN DEFAULT FALSE"); err != nil {
+				return err
+			}
+			// Автомобили, на которые уже есть чеки, считаются проданными
+			_, err := tx.Exec("UPDATE Cars SET IsSold = TRUE WHERE ID_Car IN (SELECT ID_Car FROM Checks)")
+			return err
+		},
+		Down: func(tx *Tx) error {
+			return dropColumnIfExists(tx, "Cars", "IsSold")
+		},
+	},
+	{
+		Version: 4,
+		Name:    "статусы автомобилей и история их смены",
+		Up: func(tx *Tx) error {
+			if err := addColumnIfMissing(tx, "Cars", "Status", "VARCHAR(20) NOT NULL DEFAULT 'in_stock'"); err != nil {
+				return err
+			}
+			_, err := tx.execSchema(`
+ CREATE TABLE IF NOT EXISTS CarStatusHistory (
+  ID_Change INTEGER PRIMARY KEY AUTOINCREMENT,
+  ID_Car INTEGER NOT NULL,
+  FromStatus VARCHAR(20),
+  ToStatus VARCHAR(20) NOT NULL,
+  ChangedAt DATETIME NOT NULL,
+  FOREIGN KEY (ID_Car) REFERENCES Cars(ID_Car)
+ );
+ UPDATE Cars SET Status = CASE
+  WHEN IsArchived THEN 'archived'
+  WHEN IsSold THEN 'sold'
+  ELSE 'in_stock'
+ END;
+ `)
+			if err != nil {
+				return err
+			}
+			// Текущее состояние каждого автомобиля становится первой записью его истории
+			_, err = tx.Exec(
+				"INSERT INTO CarStatusHistory (ID_Car, FromStatus, ToStatus, ChangedAt) SELECT ID_Car, NULL, Status, ? FROM Cars",
+				time.Now().UTC(),
+			)
+			if err != nil {
+				return err
+			}
+			if err := dropColumnIfExists(tx, "Cars", "IsArchived"); err != nil {
+				return err
+			}
+			return dropColumnIfExists(tx, "Cars", "IsSold")
+		},
+		Down: func(tx *Tx) error {
+			if err := addColumnIfMissing(tx, "Cars", "IsArchived", "BOOLEAN DEFAULT FALSE"); err != nil {
+				return err
+			}
+			if err := addColumnIfMissing(tx, "Cars", "IsSold", "BOOLEAN DEFAULT FALSE"); err != nil {
+				return err
+			}
+			_, err := tx.Exec(`
+ UPDATE Cars SET
+  IsArchived = (Status = 'archived'),
+  IsSold = (Status IN ('sold', 'reserved'));
+ DROP TABLE IF EXISTS CarStatusHistory;
+ `)
+			if err != nil {
+				return err
+			}
+			return dropColumnIfExists(tx, "Cars", "Status")
+		},
+	},
+	{
+		Version: 5,
+		Name:    "подтверждение заказов администратором",
+		Up: func(tx *Tx) error {
+			// Чеки, оформленные до появления подтверждения, считаются завершёнными продажами
+			if err := addColumnIfMissing(tx, "Checks", "Status", "VARCHAR(20) NOT NULL DEFAULT 'approved'"); err != nil {
+				return err
+			}
+			return addColumnIfMissing(tx, "Checks", "DecidedAt", "DATETIME")
+		},
+		Down: func(tx *Tx) error {
+			if err := dropColumnIfExists(tx, "Checks", "DecidedAt"); err != nil {
+				return err
+			}
+			return dropColumnIfExists(tx, "Checks", "Status")
+		},
+	},
+	{
+		Version: 6,
+		Name:    "время оформления чеков",
+		// У чеков, оформленных раньше, время неизвестно и остаётся NULL
+		Up: func(tx *Tx) error {
+			if err := addColumnIfMissing(tx, "Checks", "CreatedAt", "DATETIME"); err != nil {
+				return err
+			}
+			_, err := tx.Exec("CREATE INDEX IF NOT EXISTS idx_checks_created_at ON Checks (CreatedAt)")
+			return err
+		},
+		Down: func(tx *Tx) error {
+			if _, err := tx.Exec("DROP INDEX IF EXISTS idx_checks_created_at"); err != nil {
+				return err
+			}
+			return dropColumnIfExists(tx, "Checks", "CreatedAt")
+		},
+	},
+	{
+		Version: 7,
+		Name:    "история изменения данных автомобилей",
+		Up: func(tx *Tx) error {
+			_, err := tx.execSchema(`
+ CREATE TABLE IF NOT EXISTS CarChangeHistory (
+  ID_Change INTEGER PRIMARY KEY AUTOINCREMENT,
+  ID_Car INTEGER NOT NULL,
+  Field VARCHAR(20) NOT NULL,
+  OldValue VARCHAR(255),
+  NewValue VARCHAR(255),
+  ID_Admin INTEGER,
+  ChangedAt DATETIME NOT NULL,
+  FOREIGN KEY (ID_Car) REFERENCES Cars(ID_Car),
+  FOREIGN KEY (ID_Admin) REFERENCES Administrator(ID_Admin)
+ );
+ CREATE INDEX IF NOT EXISTS idx_car_change_history_car ON CarChangeHistory (ID_Car);
+ `)
+			return err
+		},
+		Down: func(tx *Tx) error {
+			_, err := tx.Exec("DROP TABLE IF EXISTS CarChangeHistory")
+			return err
+		},
+	},
+	{
+		Version: 8,
+		Name:    "история цен автомобилей",
+		Up: func(tx *Tx) error {
+			_, err := tx.execSchema(`
+ CREATE TABLE IF NOT EXISTS CarPriceHistory (
+  ID_Price INTEGER PRIMARY KEY AUTOINCREMENT,
+  ID_Car INTEGER NOT NULL,
+  Price DECIMAL(10, 2) NOT NULL,
+  ID_Admin INTEGER,
+  ChangedAt DATETIME NOT NULL,
+  FOREIGN KEY (ID_Car) REFERENCES Cars(ID_Car),
+  FOREIGN KEY (ID_Admin) REFERENCES Administrator(ID_Admin)
+ );
+ CREATE INDEX IF NOT EXISTS idx_car_price_history_car ON CarPriceHistory (ID_Car);
+ `)
+			if err != nil {
+				return err
+			}
+			// Прежние цены неизвестны: история каждого автомобиля начинается с текущей цены
+			_, err = tx.Exec(
+				"INSERT INTO CarPriceHistory (ID_Car, Price, ID_Admin, ChangedAt) SELECT ID_Car, Price, NULL, ? FROM Cars",
+				time.Now().UTC(),
+			)
+			return err
+		},
+		Down: func(tx *Tx) error {
+			_, err := tx.Exec("DROP TABLE IF EXISTS CarPriceHistory")
+			return err
+		},
+	},
+	{
+		Version: 9,
+		Name:    "фотографии автомобилей",
+		Up: func(tx *Tx) error {
+			_, err := tx.execSchema(`
+ CREATE TABLE IF NOT EXISTS CarPhotos (
+  ID_Photo INTEGER PRIMARY KEY AUTOINCREMENT,
+  ID_Car INTEGER NOT NULL,
+  Position INTEGER NOT NULL,
+  ContentType VARCHAR(50) NOT NULL,
+  Data BLOB NOT NULL,
+  Thumbnail BLOB NOT NULL,
+  CreatedAt DATETIME NOT NULL,
+  FOREIGN KEY (ID_Car) REFERENCES Cars(ID_Car)
+ );
+ CREATE INDEX IF NOT EXISTS idx_car_photos_car ON CarPhotos (ID_Car, Position);
+ `)
+			return err
+		},
+		Down: func(tx *Tx) error {
+			_, err := tx.Exec("DROP TABLE IF EXISTS CarPhotos")
+			return err
+		},
+	},
+	{
+		Version: 10,
+		Name:    "VIN и характеристики автомобилей",
+		Up: func(tx *Tx) error {
+			columns := []struct{ name, def string }{
+				{"VIN", "VARCHAR(17)"},
+				{"Mileage", "INTEGER"},
+				{"EngineVolume", "DECIMAL(3, 1)"},
+				{"FuelType", "VARCHAR(20)"},
+				{"Transmission", "VARCHAR(20)"},
+				{"BodyType", "VARCHAR(20)"},
+				{"DriveType", "VARCHAR(20)"},
+				{"Owners", "INTEGER"},
+			}
+			for _, c := range columns {
+				if err := addColumnIfMissing(tx, "Cars", c.name, c.def); err != nil {
+					return err
+				}
+			}
+			// У старых автомобилей VIN нет (NULL), уникальность проверяется только для заполненных
+			_, err := tx.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_cars_vin ON Cars (VIN) WHERE VIN IS NOT NULL")
+			return err
+		},
+		Down: func(tx *Tx) error {
+			if _, err := tx.Exec("DROP INDEX IF EXISTS idx_cars_vin"); err != nil {
+				return err
+			}
+			for _, name := range []string{"VIN", "Mileage", "EngineVolume", "FuelType", "Transmission", "BodyType", "DriveType", "Owners"} {
+				if err := dropColumnIfExists(tx, "Cars", name); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version: 11,
+		Name:    "справочники марок, моделей и цветов",
+		Up: func(tx *Tx) error {
+			_, err := tx.execSchema(`
+ CREATE TABLE IF NOT EXISTS Brands (
+  ID_Brand INTEGER PRIMARY KEY AUTOINCREMENT,
+  Name VARCHAR(50) NOT NULL,
+  NameKey VARCHAR(50) NOT NULL UNIQUE
+ );
+
+ CREATE TABLE IF NOT EXISTS Models (
+  ID_Model INTEGER PRIMARY KEY AUTOINCREMENT,
+  ID_Brand INTEGER NOT NULL,
+  Name VARCHAR(50) NOT NULL,
+  NameKey VARCHAR(50) NOT NULL,
+  UNIQUE (ID_Brand, NameKey),
+  FOREIGN KEY (ID_Brand) REFERENCES Brands(ID_Brand)
+ );
+
+ CREATE TABLE IF NOT EXISTS Colors (
+  ID_Color INTEGER PRIMARY KEY AUTOINCREMENT,
+  Name VARCHAR(30) NOT NULL,
+  NameKey VARCHAR(30) NOT NULL UNIQUE
+ );
+
+ -- Другие написания значений; ID_Brand задаёт марку для моделей и равен 0 у марок и цветов
+ CREATE TABLE IF NOT EXISTS DictionaryAliases (
+  Kind VARCHAR(10) NOT NULL,
+  ID_Brand INTEGER NOT NULL DEFAULT 0,
+  NameKey VARCHAR(50) NOT NULL,
+  ID_Value INTEGER NOT NULL,
+  PRIMARY KEY (Kind, ID_Brand, NameKey)
+ );
+ `)
+			if err != nil {
+				return err
+			}
+			for _, c := range []struct{ name, def string }{
+				{"ID_Brand", "INTEGER REFERENCES Brands(ID_Brand)"},
+				{"ID_Model", "INTEGER REFERENCES Models(ID_Model)"},
+				{"ID_Color", "INTEGER REFERENCES Colors(ID_Color)"},
+			} {
+				if err := addColumnIfMissing(tx, "Cars", c.name, c.def); err != nil {
+					return err
+				}
+			}
+
+			// Текстовые значения переносятся в справочники, после чего их столбцы удаляются
+			columns, err := tableColumns(tx, "Cars")
+			if err != nil {
+				return err
+			}
+			if _, ok := columns.get("Brand"); ok {
+				if err := mergeFreeTextValues(tx); err != nil {
+					return err
+				}
+			}
+			for _, name := range []string{"Brand", "Model", "Color"} {
+				if err := dropColumnIfExists(tx, "Cars", name); err != nil {
+					return err
+				}
+			}
+			// Представление возвращает автомобили с названиями вместо ссылок на справочники.
+			// Столбцы перечислены явно: при изменении Cars представление нужно пересоздать.
+			_, err = tx.execSchema(`
+ CREATE INDEX IF NOT EXISTS idx_cars_brand ON Cars (ID_Brand);
+ CREATE VIEW IF NOT EXISTS CarDetails AS
+  SELECT c.ID_Car, b.Name AS Brand, m.Name AS Model, c.YearOfRelease, col.Name AS Color, c.Price, c.Status,
+   c.VIN, c.Mileage, c.EngineVolume, c.FuelType, c.Transmission, c.BodyType, c.DriveType, c.Owners,
+   c.ID_Brand, c.ID_Model, c.ID_Color
+  FROM Cars c
+  LEFT JOIN Brands b ON c.ID_Brand = b.ID_Brand
+  LEFT JOIN Models m ON c.ID_Model = m.ID_Model
+  LEFT JOIN Colors col ON c.ID_Color = col.ID_Color;
+ `)
+			return err
+		},
+		Down: func(tx *Tx) error {
+			if _, err := tx.Exec("DROP VIEW IF EXISTS CarDetails; DROP INDEX IF EXISTS idx_cars_brand"); err != nil {
+				return err
+			}
+			for _, c := range []struct{ name, def string }{
+				{"Brand", "VARCHAR(50)"}, {"Model", "VARCHAR(50)"}, {"Color", "VARCHAR(30)"},
+			} {
+				if err := addColumnIfMissing(tx, "Cars", c.name, c.def); err != nil {
+					return err
+				}
+			}
+			_, err := tx.Exec(`
+ UPDATE Cars SET
+  Brand = (SELECT Name FROM Brands WHERE Brands.ID_Brand = Cars.ID_Brand),
+  Model = (SELECT Name FROM Models WHERE Models.ID_Model = Cars.ID_Model),
+  Color = (SELECT Name FROM Colors WHERE Colors.ID_Color = Cars.ID_Color);
+ `)
+			if err != nil {
+				return err
+			}
+			for _, name := range []string{"ID_Brand", "ID_Model", "ID_Color"} {
+				if err := dropColumnIfExists(tx, "Cars", name); err != nil {
+					return err
+				}
+			}
+			_, err = tx.Exec(`
+ DROP TABLE IF EXISTS DictionaryAliases;
+ DROP TABLE IF EXISTS Models;
+ DROP TABLE IF EXISTS Brands;
+ DROP TABLE IF EXISTS Colors;
+ `)
+			return err
+		},
+	},
+}
+
+// ensureMigrationsTable создаёт служебную таблицу учёта применённых миграций
+func ensureMigrationsTable(db *DB) error {
+	_, err := db.execSchema(`
+ CREATE TABLE IF NOT EXISTS schema_migrations (
+  version INTEGER PRIMARY KEY,
+  name VARCHAR(100) NOT NULL,
+  applied_at DATETIME NOT NULL
+ );
+ `)
+	if err != nil {
+		return fmt.Errorf("ошибка создания таблицы schema_migrations: %w", err)
+	}
+	return nil
+}
+
+// appliedMigrations возвращает время применения каждой записанной миграции
+func appliedMigrations(db *DB) (map[int]time.Time, error) {
+	if err := ensureMigrationsTable(db); err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query("SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("ошибка чтения schema_migrations: %w", err)
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+// Migrate применяет по порядку все ещё не применённые миграции
+func Migrate(db *DB) error {
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+
+		err := inTx(db, func(tx *Tx) error {
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+			_, err := tx.Exec(
+				"INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)",
+				m.Version, m.Name, time.Now().UTC(),
+			)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("ошибка применения миграции %d (%s): %w", m.Version, m.Name, err)
+		}
+		slog.Info(fmt.Sprintf("Применена миграция %d: %s", m.Version, m.Name))
+	}
+
+	return nil
+}
+
+// RollbackLast откатывает последнюю применённую миграцию и возвращает её номер.
+// Если ни одна миграция не применена, возвращается 0; базовая схема не откатывается
+// и возвращает ErrIrreversibleMigration.
+func RollbackLast(db *DB) (int, error) {
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := len(migrations) - 1; i >= 0; i-- {
+		m := migrations[i]
+		if _, ok := applied[m.Version]; !ok {
+			continue
+		}
+
+		err := inTx(db, func(tx *Tx) error {
+			if err := m.Down(tx); err != nil {
+				return err
+			}
+			_, err := tx.Exec("DELETE FROM schema_migrations WHERE version = ?", m.Version)
+			return err
+		})
+		if err != nil {
+			return 0, fmt.Errorf("ошибка отката миграции %d (%s): %w", m.Version, m.Name, err)
+		}
+		slog.Info(fmt.Sprintf("Откачена миграция %d: %s", m.Version, m.Name))
+		return m.Version, nil
+	}
+
+	return 0, nil
+}
+
+// MigrationsStatus возвращает состояние всех известных миграций
+func MigrationsStatus(db *DB) ([]MigrationStatus, error) {
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		appliedAt, ok := applied[m.Version]
+		statuses = append(statuses, MigrationStatus{
+			Version:   m.Version,
+			Name:      m.Name,
+			Applied:   ok,
+			AppliedAt: appliedAt,
+		})
+	}
+	return statuses, nil
+}
+
+// inTx выполняет fn в транзакции и откатывает её при ошибке
+func inTx(db *DB, fn func(tx *Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// tableColumns возвращает столбцы таблицы
+func tableColumns(tx *Tx, table string) (columnSet, error) {
+	return tx.dialect.columns(tx, table)
+}
+
+// addColumnIfMissing добавляет столбец, только если его ещё нет в таблице
+func addColumnIfMissing(tx *Tx, table, column, definition string) error {
+	columns, err := tableColumns(tx, table)
+	if err != nil {
+		return err
+	}
+	if _, ok := columns.get(column); ok {
+		return nil
+	}
+	_, err = tx.execSchema(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	return err
+}
+
+// dropColumnIfExists удаляет столбец, если он есть в таблице
+func dropColumnIfExists(tx *Tx, table, column string) error {
+	columns, err := tableColumns(tx, table)
+	if err != nil {
+		return err
+	}
+	if _, ok := columns.get(column); !ok {
+		return nil
+	}
+	_, err = tx.Exec(fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", table, column))
+	return err
+}
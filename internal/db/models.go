@@ -0,0 +1,134 @@
+package db
+
+import "time"
+
+// Car — автомобиль из таблицы Cars.
+// Характеристики, добавленные позже основных полей, у старых записей могут быть не заполнены.
+type Car struct {
+	ID     int
+	Brand  string
+	Model  string
+	Year   int
+	Color  string
+	Price  float64
+	Status CarStatus
+
+	VIN          string
+	Mileage      int     // пробег, км
+	EngineVolume float64 // объём двигателя, л
+	Fuel         FuelType
+	Transmission Transmission
+	Body         BodyType
+	Drive        DriveType
+	Owners       int // число предыдущих владельцев
+}
+
+// Client — клиент из таблицы Client
+type Client struct {
+	ID       int
+	Name     string
+	LastName string
+	Phone    string
+	Login    string
+	Password string
+}
+
+// Admin — администратор из таблицы Administrator
+type Admin struct {
+	ID       int
+	Name     string
+	LastName string
+	Login    string
+	Password string
+	Phone    string
+}
+
+// Check — чек о покупке автомобиля. AdminID равен 0, пока заказ не рассмотрен администратором,
+// DecidedAt — нулевое время до решения. CreatedAt нулевое у чеков, оформленных до учёта времени продаж.
+type Check struct {
+	ID        int
+	ClientID  int
+	CarID     int
+	AdminID   int
+	Price     float64
+	Status    CheckStatus
+	CreatedAt time.Time
+	DecidedAt time.Time
+}
+
+// Purchase — строка истории покупок клиента.
+// CarDeleted означает, что автомобиль из чека отсутствует в таблице Cars.
+type Purchase struct {
+	CheckID    int
+	Brand      string
+	Model      string
+	Year       int
+	Price      float64
+	Status     CheckStatus
+	CreatedAt  time.Time
+	DecidedAt  time.Time
+	CarDeleted bool
+}
+
+// PendingOrder — заказ в очереди на подтверждение администратором
+type PendingOrder struct {
+	CheckID        int
+	ClientID       int
+	ClientName     string
+	ClientLastName string
+	CarID          int
+	Brand          string
+	Model          string
+	Year           int
+	Price          float64
+}
+
+// SalesSummary — итоги подтверждённых продаж за период
+type SalesSummary struct {
+	Revenue float64
+	Units   int
+}
+
+// AveragePrice возвращает среднюю цену продажи или 0, если продаж не было
+func (s SalesSummary) AveragePrice() float64 {
+	if s.Units == 0 {
+		return 0
+	}
+	return s.Revenue / float64(s.Units)
+}
+
+// Sale — подтверждённая продажа вместе с данными автомобиля и администратора,
+// подтвердившего заказ. Для чеков из старой базы AdminID равен нулю.
+type Sale struct {
+	CheckID       int
+	CarID         int
+	Brand         string
+	Model         string
+	Year          int
+	Color         string
+	CarStatus     CarStatus
+	CarDeleted    bool
+	AdminID       int
+	AdminName     string
+	AdminLastName string
+	Price         float64
+	// ListedPrice — первая цена, выставленная за автомобиль до продажи. Если она неизвестна
+	// (чек старше истории цен или автомобиль удалён из базы), равна Price.
+	ListedPrice float64
+	CreatedAt   time.Time
+	// DecidedAt — время подтверждения продажи: по нему продажа относится к периоду.
+	// Нулевое у продаж, подтверждённых до учёта времени решения.
+	DecidedAt time.Time
+}
+
+// CarPhoto — фотография автомобиля. Data содержит исходный файл,
+// Thumbnail — уменьшенную копию в JPEG для списков и галереи.
+type CarPhoto struct {
+	ID          int
+	CarID       int
+	Position    int
+	ContentType string
+	Data        []byte
+	Thumbnail   []byte
+	CreatedAt   time.Time
+}
@@ -0,0 +1,193 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+type sqlPhotoRepository struct {
+	db *DB
+}
+
+func (r *sqlPhotoRepository) Add(photo *CarPhoto) error {
+	photo.CreatedAt = time.Now().UTC()
+	return inTx(r.db, func(tx *Tx) error {
+		var exists bool
+		if err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM Cars WHERE ID_Car = ?)", photo.CarID).Scan(&exists); err != nil {
+			return fmt.Errorf("ошибка проверки автомобиля: %w", err)
+		}
+		if !exists {
+			return ErrCarNotFound
+		}
+		err := tx.QueryRow("SELECT COALESCE(MAX(Position), 0) + 1 FROM CarPhotos WHERE ID_Car = ?", photo.CarID).Scan(&photo.Position)
+		if err != nil {
+			return fmt.Errorf("ошибка добавления фотографии: %w", err)
+		}
+
+		photo.ID, err = insertID(tx, "ID_Photo",
+			"INSERT INTO CarPhotos (ID_Car, Position, ContentType, Data, Thumbnail, CreatedAt) VALUES (?, ?, ?, ?, ?, ?)",
+			photo.CarID, photo.Position, photo.ContentType, photo.Data, photo.Thumbnail, photo.CreatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("ошибка добавления фотографии: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *sqlPhotoRepository) Get(id int) (*CarPhoto, error) {
+	var p CarPhoto
+	err := r.db.QueryRow(
+		"SELECT ID_Photo, ID_Car, Position, ContentType, Data, Thumbnail, CreatedAt FROM CarPhotos WHERE ID_Photo = ?", id,
+	).Scan(&p.ID, &p.CarID, &p.Position, &p.ContentType, &p.Data, &p.Thumbnail, &p.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения фотографии: %w", err)
+	}
+	return &p, nil
+}
+
+func (r *sqlPhotoRepository) List(carID int) ([]CarPhoto, error) {
+	return r.query(
+		"SELECT ID_Photo, ID_Car, Position, ContentType, Thumbnail, CreatedAt FROM CarPhotos WHERE ID_Car = ? ORDER BY Position",
+		carID,
+	)
+}
+
+func (r *sqlPhotoRepository) Delete(id int) error {
+	res, err := r.db.Exec("DELETE FROM CarPhotos WHERE ID_Photo = ?", id)
+	if err != nil {
+		return fmt.Errorf("ошибка удаления фотографии: %w", err)
+	}
+	return expectAffected(res)
+}
+
+func (r *sqlPhotoRepository) Covers(carIDs []int) (map[int]CarPhoto, error) {
+	covers := make(map[int]CarPhoto)
+	if len(carIDs) == 0 {
+		return covers, nil
+	}
+
+	args := make([]any, len(carIDs))
+	for i, id := range carIDs {
+		args[i] = id
+	}
+	photos, err := r.query(`
+		SELECT p.ID_Photo, p.ID_Car, p.Position, p.ContentType, p.Thumbnail, p.CreatedAt
+		FROM CarPhotos p
+		WHERE p.ID_Car IN (`+placeholders(len(carIDs))+`)
+		  AND p.Position = (SELECT MIN(Position) FROM CarPhotos WHERE ID_Car = p.ID_Car)
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range photos {
+		covers[p.CarID] = p
+	}
+	return covers, nil
+}
+
+// query читает фотографии без исходных файлов
+func (r *sqlPhotoRepository) query(query string, args ...any) ([]CarPhoto, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения фотографий: %w", err)
+	}
+	defer rows.Close()
+
+	var photos []CarPhoto
+	for rows.Next() {
+		var p CarPhoto
+		if err := rows.Scan(&p.ID, &p.CarID, &p.Position, &p.ContentType, &p.Thumbnail, &p.CreatedAt); err != nil {
+			return nil, fmt.Errorf("ошибка чтения фотографии: %w", err)
+		}
+		photos = append(photos, p)
+	}
+	return photos, rows.Err()
+}
+
+type memoryPhotoRepository struct {
+	d *memoryData
+}
+
+func (r *memoryPhotoRepository) Add(photo *CarPhoto) error {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	if _, ok := r.d.cars[photo.CarID]; !ok {
+		return ErrCarNotFound
+	}
+	photo.Position = 1
+	for _, p := range r.d.photos {
+		if p.CarID == photo.CarID && p.Position >= photo.Position {
+			photo.Position = p.Position + 1
+		}
+	}
+	photo.ID = r.d.newID("CarPhotos")
+	photo.CreatedAt = time.Now().UTC()
+	r.d.photos[photo.ID] = *photo
+	return nil
+}
+
+func (r *memoryPhotoRepository) Get(id int) (*CarPhoto, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	p, ok := r.d.photos[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &p, nil
+}
+
+func (r *memoryPhotoRepository) List(carID int) ([]CarPhoto, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	var photos []CarPhoto
+	for _, p := range r.d.photos {
+		if p.CarID == carID {
+			p.Data = nil
+			photos = append(photos, p)
+		}
+	}
+	sort.Slice(photos, func(i, j int) bool { return photos[i].Position < photos[j].Position })
+	return photos, nil
+}
+
+func (r *memoryPhotoRepository) Delete(id int) error {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	if _, ok := r.d.photos[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.d.photos, id)
+	return nil
+}
+
+func (r *memoryPhotoRepository) Covers(carIDs []int) (map[int]CarPhoto, error) {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+
+	wanted := make(map[int]bool, len(carIDs))
+	for _, id := range carIDs {
+		wanted[id] = true
+	}
+	covers := make(map[int]CarPhoto)
+	for _, p := range r.d.photos {
+		if !wanted[p.CarID] {
+			continue
+		}
+		if cover, ok := covers[p.CarID]; !ok || p.Position < cover.Position {
+			p.Data = nil
+			covers[p.CarID] = p
+		}
+	}
+	return covers, nil
+}
@@ -0,0 +1,62 @@
+package db
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestPhotos(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			car := addTestCar(t, store)
+			other := addTestCar(t, store)
+
+			var added []CarPhoto
+			for i, data := range []string{"first", "second", "third"} {
+				p := CarPhoto{CarID: car.ID, ContentType: "image/png", Data: []byte(data), Thumbnail: []byte("thumb-" + data)}
+				if err := store.Photos.Add(&p); err != nil {
+					t.Fatal(err)
+				}
+				if p.ID == 0 || p.Position != i+1 {
+					t.Errorf("фотография %d: %+v", i, p)
+				}
+				added = append(added, p)
+			}
+			if err := store.Photos.Add(&CarPhoto{CarID: car.ID + 100, Data: []byte("x"), Thumbnail: []byte("x")}); !errors.Is(err, ErrCarNotFound) {
+				t.Errorf("несуществующий автомобиль: ожидалась ErrCarNotFound, получено %v", err)
+			}
+
+			full, err := store.Photos.Get(added[1].ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(full.Data, []byte("second")) {
+				t.Errorf("исходный файл: %q", full.Data)
+			}
+
+			if err := store.Photos.Delete(added[0].ID); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.Photos.Delete(added[0].ID); !errors.Is(err, ErrNotFound) {
+				t.Errorf("повторное удаление: ожидалась ErrNotFound, получено %v", err)
+			}
+
+			photos, err := store.Photos.List(car.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(photos) != 2 || photos[0].ID != added[1].ID || photos[0].Data != nil || string(photos[0].Thumbnail) != "thumb-second" {
+				t.Errorf("галерея: %+v", photos)
+			}
+
+			covers, err := store.Photos.Covers([]int{car.ID, other.ID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(covers) != 1 || covers[car.ID].ID != added[1].ID {
+				t.Errorf("обложки: %+v", covers)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package db
+
+// PriceBreakdown — состав цены автомобиля для покупателя
+type PriceBreakdown struct {
+	// ListedPrice — первая выставленная цена автомобиля
+	ListedPrice float64
+	// Discount — снижение цены с момента выставления; отрицательное, если цена выросла
+	Discount float64
+	// Price — итоговая сумма к оплате, она же цена в чеке
+	Price float64
+}
+
+// BreakdownPrice раскладывает текущую цену автомобиля по истории его цен.
+// Без истории первой ценой считается текущая.
+func BreakdownPrice(car Car, history []CarPriceChange) PriceBreakdown {
+	listed := car.Price
+	if len(history) > 0 {
+		listed = history[0].Price
+	}
+	return PriceBreakdown{
+		ListedPrice: listed,
+		Discount:    listed - car.Price,
+		Price:       car.Price,
+	}
+}
@@ -0,0 +1,16 @@
+package db
+
+import "testing"
+
+func TestBreakdownPrice(t *testing.T) {
+	car := Car{Price: 24000}
+
+	b := BreakdownPrice(car, []CarPriceChange{{Price: 26000}, {Price: 25000}, {Price: 24000}})
+	if b.ListedPrice != 26000 || b.Discount != 2000 || b.Price != 24000 {
+		t.Errorf("разбивка цены: %+v", b)
+	}
+
+	if b := BreakdownPrice(car, nil); b.ListedPrice != car.Price || b.Discount != 0 {
+		t.Errorf("без истории цен: %+v", b)
+	}
+}
@@ -0,0 +1,216 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Ошибки оформления покупки
+var (
+	ErrCarNotFound     = errors.New("автомобиль не найден")
+	ErrCarAlreadySold  = errors.New("автомобиль уже продан")
+	ErrCarReserved     = errors.New("автомобиль забронирован другим покупателем")
+	ErrCarUnavailable  = errors.New("автомобиль снят с продажи")
+	ErrCheckNotPending = errors.New("заказ уже рассмотрен")
+	ErrPriceChanged    = errors.New("цена автомобиля изменилась, проверьте новую цену и оформите заказ заново")
+)
+
+// PurchaseService оформляет покупку автомобиля и её подтверждение администратором
+type PurchaseService interface {
+	// Purchase атомарно проверяет, что автомобиль в наличии, создаёт заказ в статусе
+	// «ожидает подтверждения» и бронирует автомобиль. Если автомобиль уже куплен
+	// или забронирован, возвращается ErrCarAlreadySold или ErrCarReserved.
+	// price — цена, которую подтвердил покупатель; если к моменту оформления
+	// она изменилась, заказ не создаётся и возвращается ErrPriceChanged.
+	Purchase(clientID, carID int, price float64) (*Check, error)
+	// Approve подтверждает заказ: в чек записываются администратор и время решения, автомобиль продаётся
+	Approve(checkID, adminID int) (*Check, error)
+	// Reject отклоняет заказ и возвращает автомобиль в продажу
+	Reject(checkID, adminID int) (*Check, error)
+}
+
+type sqlPurchaseService struct {
+	db *DB
+}
+
+func (s *sqlPurchaseService) Purchase(clientID, carID int, price float64) (*Check, error) {
+	check := &Check{ClientID: clientID, CarID: carID, Status: CheckPending, CreatedAt: time.Now().UTC()}
+
+	err := inTx(s.db, func(tx *Tx) error {
+		// Условное обновление первым запросом транзакции захватывает блокировку записи
+		// (в SQLite — всей базы, в PostgreSQL — строки автомобиля), поэтому второй покупатель
+		// дождётся коммита и уже не увидит автомобиль свободным.
+		res, err := tx.Exec(
+			"UPDATE Cars SET Status = ? WHERE ID_Car = ? AND Status = ?",
+			StatusReserved, carID, StatusInStock,
+		)
+		if err != nil {
+			return fmt.Errorf("ошибка резервирования автомобиля: %w", err)
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return unavailableReason(tx, carID)
+		}
+		if err := recordStatusChange(tx, carID, StatusInStock, StatusReserved); err != nil {
+			return err
+		}
+
+		if err := tx.QueryRow("SELECT Price FROM Cars WHERE ID_Car = ?", carID).Scan(&check.Price); err != nil {
+			return fmt.Errorf("ошибка при получении цены: %w", err)
+		}
+		if !samePrice(check.Price, price) {
+			return ErrPriceChanged
+		}
+
+		check.ID, err = insertID(tx, "ID_Check",
+			"INSERT INTO Checks (ID_Client, ID_Car, ID_Admin, Price, Status, CreatedAt) VALUES (?, ?, NULL, ?, ?, ?)",
+			clientID, carID, check.Price, check.Status, check.CreatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("ошибка при добавлении чека: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return check, nil
+}
+
+func (s *sqlPurchaseService) Approve(checkID, adminID int) (*Check, error) {
+	return s.decide(checkID, adminID, CheckApproved, StatusSold)
+}
+
+func (s *sqlPurchaseService) Reject(checkID, adminID int) (*Check, error) {
+	return s.decide(checkID, adminID, CheckRejected, StatusInStock)
+}
+
+// decide записывает решение администратора по заказу и переводит забронированный автомобиль в carStatus
+func (s *sqlPurchaseService) decide(checkID, adminID int, decision CheckStatus, carStatus CarStatus) (*Check, error) {
+	var check *Check
+	err := inTx(s.db, func(tx *Tx) error {
+		res, err := tx.Exec(
+			"UPDATE Checks SET Status = ?, ID_Admin = ?, DecidedAt = ? WHERE ID_Check = ? AND Status = ?",
+			decision, adminID, time.Now().UTC(), checkID, CheckPending,
+		)
+		if err != nil {
+			return fmt.Errorf("ошибка сохранения решения по заказу: %w", err)
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			if _, err := getCheck(tx, checkID); err != nil {
+				return err
+			}
+			return ErrCheckNotPending
+		}
+
+		check, err = getCheck(tx, checkID)
+		if err != nil {
+			return err
+		}
+		return setStatusTx(tx, check.CarID, carStatus, checkTransition)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return check, nil
+}
+
+// samePrice сравнивает цены с точностью до копейки
+func samePrice(a, b float64) bool {
+	return math.Abs(a-b) < 0.005
+}
+
+// unavailableReason объясняет, почему автомобиль нельзя купить
+func unavailableReason(tx *Tx, carID int) error {
+	var status CarStatus
+	err := tx.QueryRow("SELECT Status FROM Cars WHERE ID_Car = ?", carID).Scan(&status)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return ErrCarNotFound
+	case err != nil:
+		return fmt.Errorf("ошибка проверки автомобиля: %w", err)
+	}
+	return statusError(status)
+}
+
+// statusError переводит состояние недоступного автомобиля в ошибку покупки
+func statusError(status CarStatus) error {
+	switch status {
+	case StatusSold:
+		return ErrCarAlreadySold
+	case StatusReserved:
+		return ErrCarReserved
+	default:
+		return ErrCarUnavailable
+	}
+}
+
+type memoryPurchaseService struct {
+	d *memoryData
+}
+
+func (s *memoryPurchaseService) Purchase(clientID, carID int, price float64) (*Check, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+
+	car, ok := s.d.cars[carID]
+	if !ok {
+		return nil, ErrCarNotFound
+	}
+	if car.Status != StatusInStock {
+		return nil, statusError(car.Status)
+	}
+	if !samePrice(car.Price, price) {
+		return nil, ErrPriceChanged
+	}
+	if err := s.d.setStatus(carID, StatusReserved, checkTransition); err != nil {
+		return nil, err
+	}
+
+	check := Check{
+		ID:        s.d.newID("Checks"),
+		ClientID:  clientID,
+		CarID:     carID,
+		Price:     car.Price,
+		Status:    CheckPending,
+		CreatedAt: time.Now().UTC(),
+	}
+	s.d.checks[check.ID] = check
+	return &check, nil
+}
+
+func (s *memoryPurchaseService) Approve(checkID, adminID int) (*Check, error) {
+	return s.decide(checkID, adminID, CheckApproved, StatusSold)
+}
+
+func (s *memoryPurchaseService) Reject(checkID, adminID int) (*Check, error) {
+	return s.decide(checkID, adminID, CheckRejected, StatusInStock)
+}
+
+func (s *memoryPurchaseService) decide(checkID, adminID int, decision CheckStatus, carStatus CarStatus) (*Check, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+
+	check, ok := s.d.checks[checkID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if check.Status != CheckPending {
+		return nil, ErrCheckNotPending
+	}
+	if err := s.d.setStatus(check.CarID, carStatus, checkTransition); err != nil {
+		return nil, err
+	}
+
+	check.Status = decision
+	check.AdminID = adminID
+	check.DecidedAt = time.Now().UTC()
+	s.d.checks[checkID] = check
+	return &check, nil
+}
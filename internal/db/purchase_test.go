@@ -0,0 +1,429 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testStores возвращает хранилища поверх каждой из testDatabases и хранилище в памяти
+func testStores(t *testing.T) map[string]*Store {
+	t.Helper()
+
+	stores := map[string]*Store{"memory": NewMemoryStore()}
+	for name, database := range testDatabases(t) {
+		if err := Migrate(database); err != nil {
+			t.Fatal(err)
+		}
+		stores[name] = NewSQLStore(database)
+	}
+	return stores
+}
+
+func addTestCar(t *testing.T, store *Store) Car {
+	t.Helper()
+
+	car := Car{Brand: "Toyota", Model: "Camry", Year: 2020, Color: "Black", Price: 24000}
+	if err := store.Cars.Create(&car); err != nil {
+		t.Fatal(err)
+	}
+	return car
+}
+
+func addTestAdmin(t *testing.T, store *Store) Admin {
+	t.Helper()
+
+	admin := Admin{Name: "Михаил", LastName: "Филин", Login: "admin", Password: "secret"}
+	if err := store.Admins.Create(&admin); err != nil {
+		t.Fatal(err)
+	}
+	return admin
+}
+
+func assertCarStatus(t *testing.T, store *Store, carID int, want CarStatus) {
+	t.Helper()
+
+	car, err := store.Cars.Get(carID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if car.Status != want {
+		t.Errorf("статус автомобиля: %s, ожидался %s", car.Status, want)
+	}
+}
+
+func addTestClient(t *testing.T, store *Store, login string) Client {
+	t.Helper()
+
+	client := Client{Name: "Иван", LastName: "Иванов", Phone: "123", Login: login, Password: "secret"}
+	if err := store.Clients.Create(&client); err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestPurchaseReservesCarUntilApproval(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			car := addTestCar(t, store)
+			client := addTestClient(t, store, "ivanov")
+			admin := addTestAdmin(t, store)
+
+			check, err := store.Purchases.Purchase(client.ID, car.ID, car.Price)
+			if err != nil {
+				t.Fatalf("Purchase: %v", err)
+			}
+			if check.ID == 0 || check.Price != car.Price || check.ClientID != client.ID || check.Status != CheckPending {
+				t.Errorf("неверный чек: %+v", check)
+			}
+			assertCarStatus(t, store, car.ID, StatusReserved)
+
+			forSale, err := store.Cars.List(StatusInStock)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(forSale) != 0 {
+				t.Errorf("забронированный автомобиль остался в каталоге: %+v", forSale)
+			}
+			if _, err := store.Purchases.Purchase(client.ID, car.ID, car.Price); !errors.Is(err, ErrCarReserved) {
+				t.Errorf("покупка забронированного: ожидалась ErrCarReserved, получено %v", err)
+			}
+
+			pending, err := store.Checks.ListPending()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(pending) != 1 || pending[0].CheckID != check.ID || pending[0].ClientName != client.Name {
+				t.Fatalf("очередь заказов: %+v", pending)
+			}
+
+			approved, err := store.Purchases.Approve(check.ID, admin.ID)
+			if err != nil {
+				t.Fatalf("Approve: %v", err)
+			}
+			if approved.Status != CheckApproved || approved.AdminID != admin.ID || approved.DecidedAt.IsZero() {
+				t.Errorf("неверный подтверждённый чек: %+v", approved)
+			}
+			assertCarStatus(t, store, car.ID, StatusSold)
+
+			if _, err := store.Purchases.Approve(check.ID, admin.ID); !errors.Is(err, ErrCheckNotPending) {
+				t.Errorf("повторное решение: ожидалась ErrCheckNotPending, получено %v", err)
+			}
+			if _, err := store.Purchases.Purchase(client.ID, car.ID, car.Price); !errors.Is(err, ErrCarAlreadySold) {
+				t.Errorf("повторная покупка: ожидалась ErrCarAlreadySold, получено %v", err)
+			}
+
+			history, err := store.Checks.ListByClient(client.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(history) != 1 || history[0].Status != CheckApproved {
+				t.Errorf("история покупок: %+v", history)
+			}
+		})
+	}
+}
+
+func TestRejectReturnsCarToStock(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			car := addTestCar(t, store)
+			client := addTestClient(t, store, "ivanov")
+			admin := addTestAdmin(t, store)
+
+			check, err := store.Purchases.Purchase(client.ID, car.ID, car.Price)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rejected, err := store.Purchases.Reject(check.ID, admin.ID)
+			if err != nil {
+				t.Fatalf("Reject: %v", err)
+			}
+			if rejected.Status != CheckRejected || rejected.AdminID != admin.ID {
+				t.Errorf("неверный отклонённый чек: %+v", rejected)
+			}
+			assertCarStatus(t, store, car.ID, StatusInStock)
+
+			if _, err := store.Purchases.Reject(check.ID+100, admin.ID); !errors.Is(err, ErrNotFound) {
+				t.Errorf("несуществующий заказ: ожидалась ErrNotFound, получено %v", err)
+			}
+			sales, err := store.Checks.ListSales(true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(sales) != 0 {
+				t.Errorf("отклонённый заказ попал в анализ продаж: %+v", sales)
+			}
+		})
+	}
+}
+
+func TestReservationChangesOnlyByDecision(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			car := addTestCar(t, store)
+			client := addTestClient(t, store, "ivanov")
+			other := addTestClient(t, store, "petrov")
+			admin := addTestAdmin(t, store)
+
+			check, err := store.Purchases.Purchase(client.ID, car.ID, car.Price)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, to := range []CarStatus{StatusInStock, StatusSold} {
+				if err := store.Cars.SetStatus(car.ID, to); !errors.Is(err, ErrInvalidTransition) {
+					t.Errorf("ручное снятие брони → %s: ожидалась ErrInvalidTransition, получено %v", to, err)
+				}
+			}
+			assertCarStatus(t, store, car.ID, StatusReserved)
+			if _, err := store.Purchases.Purchase(other.ID, car.ID, car.Price); !errors.Is(err, ErrCarReserved) {
+				t.Errorf("покупка после попытки снять бронь: ожидалась ErrCarReserved, получено %v", err)
+			}
+			if _, err := store.Purchases.Approve(check.ID, admin.ID); err != nil {
+				t.Fatalf("Approve после попытки снять бронь: %v", err)
+			}
+
+			free := addTestCar(t, store)
+			if err := store.Cars.SetStatus(free.ID, StatusReserved); !errors.Is(err, ErrInvalidTransition) {
+				t.Errorf("ручная бронь: ожидалась ErrInvalidTransition, получено %v", err)
+			}
+			if next := StatusReserved.ManualNext(); len(next) != 0 {
+				t.Errorf("ручные переходы из брони: %v", next)
+			}
+		})
+	}
+}
+
+func TestDeleteClientReleasesReservation(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			reserved, sold := addTestCar(t, store), addTestCar(t, store)
+			client := addTestClient(t, store, "ivanov")
+			admin := addTestAdmin(t, store)
+
+			if _, err := store.Purchases.Purchase(client.ID, reserved.ID, reserved.Price); err != nil {
+				t.Fatal(err)
+			}
+			check, err := store.Purchases.Purchase(client.ID, sold.ID, sold.Price)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := store.Purchases.Approve(check.ID, admin.ID); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := store.Clients.Delete(client.ID); err != nil {
+				t.Fatal(err)
+			}
+			assertCarStatus(t, store, reserved.ID, StatusInStock)
+			assertCarStatus(t, store, sold.ID, StatusSold)
+
+			history, err := store.Cars.StatusHistory(reserved.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if last := history[len(history)-1]; last.From != StatusReserved || last.To != StatusInStock {
+				t.Errorf("последняя запись истории: %+v", last)
+			}
+			other := addTestClient(t, store, "petrov")
+			if _, err := store.Purchases.Purchase(other.ID, reserved.ID, reserved.Price); err != nil {
+				t.Errorf("покупка освобождённого автомобиля: %v", err)
+			}
+		})
+	}
+}
+
+func TestPurchaseRejectsChangedPrice(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			car := addTestCar(t, store)
+			client := addTestClient(t, store, "ivanov")
+			admin := addTestAdmin(t, store)
+
+			confirmed := car.Price
+			car.Price = 22000
+			if _, err := store.Cars.Update(car, admin.ID); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := store.Purchases.Purchase(client.ID, car.ID, confirmed); !errors.Is(err, ErrPriceChanged) {
+				t.Fatalf("покупка по устаревшей цене: ожидалась ErrPriceChanged, получено %v", err)
+			}
+			assertCarStatus(t, store, car.ID, StatusInStock)
+			if purchases, err := store.Checks.ListByClient(client.ID); err != nil || len(purchases) != 0 {
+				t.Errorf("покупка по устаревшей цене оставила чек: %+v, %v", purchases, err)
+			}
+
+			check, err := store.Purchases.Purchase(client.ID, car.ID, car.Price)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if check.Price != car.Price {
+				t.Errorf("цена в чеке: %v, ожидалась %v", check.Price, car.Price)
+			}
+		})
+	}
+}
+
+func TestPurchaseUnavailableCar(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			car := addTestCar(t, store)
+			client := addTestClient(t, store, "ivanov")
+
+			if err := store.Cars.SetStatus(car.ID, StatusArchived); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := store.Purchases.Purchase(client.ID, car.ID, car.Price); !errors.Is(err, ErrCarUnavailable) {
+				t.Errorf("архивный автомобиль: ожидалась ErrCarUnavailable, получено %v", err)
+			}
+			if _, err := store.Purchases.Purchase(client.ID, car.ID+100, car.Price); !errors.Is(err, ErrCarNotFound) {
+				t.Errorf("несуществующий автомобиль: ожидалась ErrCarNotFound, получено %v", err)
+			}
+
+			purchases, err := store.Checks.ListByClient(client.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(purchases) != 0 {
+				t.Errorf("неудачная покупка оставила чек: %+v", purchases)
+			}
+		})
+	}
+}
+
+func TestPurchaseConcurrentBuyers(t *testing.T) {
+	const buyers = 20
+
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			car := addTestCar(t, store)
+			clients := make([]Client, buyers)
+			for i := range clients {
+				clients[i] = addTestClient(t, store, fmt.Sprintf("client%d", i))
+			}
+
+			var wg sync.WaitGroup
+			errs := make([]error, buyers)
+			start := make(chan struct{})
+			for i := range clients {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					<-start
+					_, errs[i] = store.Purchases.Purchase(clients[i].ID, car.ID, car.Price)
+				}(i)
+			}
+			close(start)
+			wg.Wait()
+
+			succeeded := 0
+			for i, err := range errs {
+				switch {
+				case err == nil:
+					succeeded++
+				case !errors.Is(err, ErrCarReserved):
+					t.Errorf("покупатель %d: неожиданная ошибка %v", i, err)
+				}
+			}
+			if succeeded != 1 {
+				t.Fatalf("успешных покупок: %d, ожидалась ровно одна", succeeded)
+			}
+
+			checks := 0
+			for _, client := range clients {
+				purchases, err := store.Checks.ListByClient(client.ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				checks += len(purchases)
+			}
+			if checks != 1 {
+				t.Errorf("создано чеков: %d, ожидался один", checks)
+			}
+		})
+	}
+}
+
+func TestListSalesArchivedToggle(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			car := addTestCar(t, store)
+			client := addTestClient(t, store, "ivanov")
+			admin := addTestAdmin(t, store)
+
+			discounted := car
+			discounted.Price = 22000
+			if _, err := store.Cars.Update(discounted, admin.ID); err != nil {
+				t.Fatal(err)
+			}
+
+			check, err := store.Purchases.Purchase(client.ID, car.ID, discounted.Price)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := store.Purchases.Approve(check.ID, admin.ID); err != nil {
+				t.Fatal(err)
+			}
+
+			sales, err := store.Checks.ListSales(false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(sales) != 1 || sales[0].Brand != car.Brand || sales[0].AdminName != admin.Name || sales[0].CarStatus != StatusSold ||
+				sales[0].Price != 22000 || sales[0].ListedPrice != car.Price {
+				t.Fatalf("продажи: %+v", sales)
+			}
+
+			if err := store.Cars.SetStatus(car.ID, StatusArchived); err != nil {
+				t.Fatal(err)
+			}
+			if sales, err = store.Checks.ListSales(false); err != nil || len(sales) != 0 {
+				t.Errorf("без архивных: %+v, %v", sales, err)
+			}
+			if sales, err = store.Checks.ListSales(true); err != nil || len(sales) != 1 || sales[0].Price != discounted.Price {
+				t.Errorf("с архивными: %+v, %v", sales, err)
+			}
+		})
+	}
+}
+
+func TestSalesBucketedByDecision(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			car := addTestCar(t, store)
+			client := addTestClient(t, store, "ivanov")
+			admin := addTestAdmin(t, store)
+
+			// Заказ оформлен в сентябре, а подтверждён в октябре
+			ordered := time.Date(2026, time.September, 29, 10, 0, 0, 0, time.UTC)
+			decided := time.Date(2026, time.October, 2, 10, 0, 0, 0, time.UTC)
+			check := Check{ClientID: client.ID, CarID: car.ID, AdminID: admin.ID, Price: car.Price,
+				Status: CheckApproved, CreatedAt: ordered, DecidedAt: decided}
+			if err := store.Checks.Create(&check); err != nil {
+				t.Fatal(err)
+			}
+
+			september, err := store.Checks.SalesSummary(time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC))
+			if err != nil {
+				t.Fatal(err)
+			}
+			october, err := store.Checks.SalesSummary(time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if september.Units != 0 || october.Units != 1 || october.Revenue != car.Price {
+				t.Errorf("итоги: сентябрь %+v, октябрь %+v, ожидалась одна продажа в октябре", september, october)
+			}
+
+			sales, err := store.Checks.ListSales(true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(sales) != 1 || !sales[0].DecidedAt.Equal(decided) || !sales[0].CreatedAt.Equal(ordered) {
+				t.Errorf("продажи: %+v", sales)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package db
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNotFound возвращается, когда запрошенная запись отсутствует
+var ErrNotFound = errors.New("запись не найдена")
+
+// CarRepository — доступ к автомобилям
+type CarRepository interface {
+	// Create добавляет автомобиль и записывает присвоенный ID в car.ID
+	Create(car *Car) error
+	// CreateBatch добавляет автомобили в одной транзакции и записывает присвоенные ID в cars[i].ID.
+	// При ошибке в любом из автомобилей не добавляется ни один; ошибка указывает его номер.
+	CreateBatch(cars []Car) error
+	Get(id int) (*Car, error)
+	// List возвращает автомобили в перечисленных состояниях; без аргументов — все автомобили
+	List(statuses ...CarStatus) ([]Car, error)
+	// Search возвращает автомобили, подходящие под фильтр, в заданном порядке.
+	// Марка, модель и цвет сравниваются по DictionaryKey; поиск по словам
+	// без учёта регистра гарантируется только для латиницы.
+	Search(filter CarFilter) ([]Car, error)
+	// Count возвращает число автомобилей, подходящих под фильтр, без учёта Limit и Offset
+	Count(filter CarFilter) (int, error)
+	// SetStatus переводит автомобиль в новое состояние и записывает смену в историю.
+	// Запрещённый переход возвращает ErrInvalidTransition.
+	SetStatus(id int, to CarStatus) error
+	StatusHistory(id int) ([]CarStatusChange, error)
+	// Update сохраняет марку, модель, год, цвет и цену автомобиля car.ID и записывает
+	// каждое изменённое поле в историю от имени администратора, а новую цену — в историю цен.
+	// Состояние не меняется.
+	Update(car Car, adminID int) ([]CarFieldChange, error)
+	// ChangeHistory возвращает историю изменения полей автомобиля в порядке изменений
+	ChangeHistory(id int) ([]CarFieldChange, error)
+	// PriceHistory возвращает все цены автомобиля, начиная с первой выставленной
+	PriceHistory(id int) ([]CarPriceChange, error)
+}
+
+// ClientRepository — доступ к клиентам
+type ClientRepository interface {
+	// Create регистрирует клиента и записывает присвоенный ID в client.ID
+	Create(client *Client) error
+	GetByLogin(login string) (*Client, error)
+	// UpdatePassword сохраняет новое значение пароля (хэш) клиента
+	UpdatePassword(id int, password string) error
+	List() ([]Client, error)
+	// Delete удаляет клиента вместе со всеми его чеками. Автомобили из его неподтверждённых
+	// заказов возвращаются в продажу в той же транзакции.
+	Delete(id int) error
+}
+
+// AdminRepository — доступ к администраторам
+type AdminRepository interface {
+	// Create добавляет администратора и записывает присвоенный ID в admin.ID
+	Create(admin *Admin) error
+	GetByLogin(login string) (*Admin, error)
+	// UpdatePassword сохраняет новое значение пароля (хэш) администратора
+	UpdatePassword(id int, password string) error
+	// List возвращает администраторов в порядке ID без паролей
+	List() ([]Admin, error)
+}
+
+// CheckRepository — доступ к чекам
+type CheckRepository interface {
+	// Create сохраняет чек и записывает присвоенный ID в check.ID.
+	// Чек без статуса сохраняется как ожидающий подтверждения.
+	Create(check *Check) error
+	Get(id int) (*Check, error)
+	// List возвращает чеки, подходящие под фильтр, в порядке оформления
+	List(filter CheckFilter) ([]Check, error)
+	ListByClient(clientID int) ([]Purchase, error)
+	// ListPending возвращает заказы, ожидающие решения администратора, в порядке поступления
+	ListPending() ([]PendingOrder, error)
+	// SalesSummary подводит итоги продаж, подтверждённых в промежутке [from, to)
+	SalesSummary(from, to time.Time) (SalesSummary, error)
+	// ListSales возвращает подтверждённые продажи в порядке оформления.
+	// Продажи архивных и удалённых из базы автомобилей включаются, только если includeArchived.
+	ListSales(includeArchived bool) ([]Sale, error)
+}
+
+// PhotoRepository — доступ к фотографиям автомобилей
+type PhotoRepository interface {
+	// Add сохраняет фотографию последней в галерее автомобиля и записывает присвоенный ID в photo.ID
+	Add(photo *CarPhoto) error
+	// Get возвращает фотографию вместе с исходным файлом
+	Get(id int) (*CarPhoto, error)
+	// List возвращает фотографии автомобиля в порядке галереи, только с миниатюрами
+	List(carID int) ([]CarPhoto, error)
+	Delete(id int) error
+	// Covers возвращает первую фотографию (с миниатюрой) каждого из автомобилей, у которых они есть
+	Covers(carIDs []int) (map[int]CarPhoto, error)
+}
+
+// DictionaryRepository — справочники марок, моделей и цветов.
+// Марка, модель и цвет автомобиля при сохранении сопоставляются со справочниками
+// по DictionaryKey; незнакомые значения добавляются в справочник автоматически.
+type DictionaryRepository interface {
+	// List возвращает значения справочника по алфавиту вместе с числом автомобилей.
+	// Для моделей brandID ограничивает список одной маркой, 0 — модели всех марок.
+	List(kind DictionaryKind, brandID int) ([]DictionaryEntry, error)
+	// Add добавляет значение и записывает присвоенный ID в entry.ID; модели нужен entry.BrandID
+	Add(entry *DictionaryEntry) error
+	// Rename меняет написание значения у всех автомобилей; прежнее написание продолжает распознаваться
+	Rename(kind DictionaryKind, id int, name string) error
+	// Merge переносит автомобили со значения fromID на intoID и удаляет fromID.
+	// Написание fromID после этого распознаётся как intoID.
+	Merge(kind DictionaryKind, fromID, intoID int) error
+	// Delete удаляет значение, которое не используется ни одним автомобилем, иначе — ErrDictionaryInUse
+	Delete(kind DictionaryKind, id int) error
+}
+
+// Store объединяет все репозитории приложения
+type Store struct {
+	Cars         CarRepository
+	Clients      ClientRepository
+	Admins       AdminRepository
+	Checks       CheckRepository
+	Photos       PhotoRepository
+	Purchases    PurchaseService
+	Dictionaries DictionaryRepository
+}
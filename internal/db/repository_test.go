@@ -0,0 +1,248 @@
+package db
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCarRepository(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			car := addTestCar(t, store)
+			other := Car{Brand: "BMW", Model: "X5", Year: 2021, Color: "White", Price: 50000, Mileage: 12000, Fuel: FuelDiesel}
+			if err := store.Cars.Create(&other); err != nil {
+				t.Fatal(err)
+			}
+			if car.ID == 0 || other.ID == car.ID {
+				t.Fatalf("присвоенные ID: %d, %d", car.ID, other.ID)
+			}
+
+			got, err := store.Cars.Get(other.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			other.Status = StatusInStock
+			if *got != other {
+				t.Errorf("Get: %+v, ожидался %+v", *got, other)
+			}
+			if _, err := store.Cars.Get(other.ID + 100); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Get несуществующего автомобиля: %v", err)
+			}
+
+			if err := store.Cars.SetStatus(other.ID, StatusArchived); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.Cars.SetStatus(other.ID+100, StatusArchived); !errors.Is(err, ErrNotFound) {
+				t.Errorf("SetStatus несуществующего автомобиля: %v", err)
+			}
+
+			all, err := store.Cars.List()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(all) != 2 || all[0].ID != car.ID || all[1].ID != other.ID {
+				t.Errorf("List без фильтра: %+v", all)
+			}
+			inStock, err := store.Cars.List(StatusInStock, StatusReserved)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(inStock) != 1 || inStock[0].ID != car.ID {
+				t.Errorf("List в наличии: %+v", inStock)
+			}
+
+			history, err := store.Cars.StatusHistory(other.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Первая запись — появление автомобиля в продаже
+			if len(history) != 2 || history[0].From != "" || history[0].To != StatusInStock ||
+				history[1].From != StatusInStock || history[1].To != StatusArchived || history[1].ChangedAt.IsZero() {
+				t.Errorf("StatusHistory: %+v", history)
+			}
+			if history, err := store.Cars.StatusHistory(car.ID); err != nil || len(history) != 1 {
+				t.Errorf("StatusHistory без смен: %+v, %v", history, err)
+			}
+
+			prices, err := store.Cars.PriceHistory(car.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(prices) != 1 || prices[0].Price != car.Price {
+				t.Errorf("PriceHistory нового автомобиля: %+v", prices)
+			}
+		})
+	}
+}
+
+func TestClientRepository(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			ivanov := addTestClient(t, store, "ivanov")
+			petrov := addTestClient(t, store, "petrov")
+			if ivanov.ID == 0 || petrov.ID == ivanov.ID {
+				t.Fatalf("присвоенные ID: %d, %d", ivanov.ID, petrov.ID)
+			}
+			duplicate := Client{Name: "Другой", LastName: "Иванов", Login: "ivanov", Password: "x"}
+			if err := store.Clients.Create(&duplicate); err == nil {
+				t.Error("повторный логин принят")
+			}
+
+			got, err := store.Clients.GetByLogin("ivanov")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *got != ivanov {
+				t.Errorf("GetByLogin: %+v, ожидался %+v", *got, ivanov)
+			}
+			if _, err := store.Clients.GetByLogin("nobody"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("GetByLogin неизвестного логина: %v", err)
+			}
+
+			if err := store.Clients.UpdatePassword(ivanov.ID, "new-hash"); err != nil {
+				t.Fatal(err)
+			}
+			if got, err := store.Clients.GetByLogin("ivanov"); err != nil || got.Password != "new-hash" {
+				t.Errorf("пароль после UpdatePassword: %+v, %v", got, err)
+			}
+			if err := store.Clients.UpdatePassword(petrov.ID+100, "x"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("UpdatePassword несуществующего клиента: %v", err)
+			}
+
+			clients, err := store.Clients.List()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(clients) != 2 || clients[0].ID != ivanov.ID || clients[1].ID != petrov.ID || clients[0].Password != "" {
+				t.Errorf("List: %+v", clients)
+			}
+
+			if err := store.Clients.Delete(ivanov.ID); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.Clients.Delete(ivanov.ID); !errors.Is(err, ErrNotFound) {
+				t.Errorf("повторное удаление: %v", err)
+			}
+			if _, err := store.Clients.GetByLogin("ivanov"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("удалённый клиент найден: %v", err)
+			}
+		})
+	}
+}
+
+func TestAdminRepository(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			admin := addTestAdmin(t, store)
+			second := Admin{Name: "Ольга", LastName: "Петрова", Login: "olya", Password: "hash", Phone: "555"}
+			if err := store.Admins.Create(&second); err != nil {
+				t.Fatal(err)
+			}
+			duplicate := Admin{Name: "Другой", LastName: "Филин", Login: "admin", Password: "x"}
+			if err := store.Admins.Create(&duplicate); err == nil {
+				t.Error("повторный логин принят")
+			}
+
+			got, err := store.Admins.GetByLogin("olya")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *got != second {
+				t.Errorf("GetByLogin: %+v, ожидался %+v", *got, second)
+			}
+			if _, err := store.Admins.GetByLogin("nobody"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("GetByLogin неизвестного логина: %v", err)
+			}
+
+			if err := store.Admins.UpdatePassword(admin.ID, "new-hash"); err != nil {
+				t.Fatal(err)
+			}
+			if got, err := store.Admins.GetByLogin("admin"); err != nil || got.Password != "new-hash" {
+				t.Errorf("пароль после UpdatePassword: %+v, %v", got, err)
+			}
+			if err := store.Admins.UpdatePassword(second.ID+100, "x"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("UpdatePassword несуществующего администратора: %v", err)
+			}
+
+			admins, err := store.Admins.List()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(admins) != 2 || admins[0].ID != admin.ID || admins[1].Login != "olya" || admins[0].Password != "" || admins[1].Password != "" {
+				t.Errorf("List: %+v", admins)
+			}
+		})
+	}
+}
+
+func TestCheckRepository(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			car := addTestCar(t, store)
+			client := addTestClient(t, store, "ivanov")
+			admin := addTestAdmin(t, store)
+			october := time.Date(2026, time.October, 10, 12, 0, 0, 0, time.UTC)
+
+			pending := Check{ClientID: client.ID, CarID: car.ID, Price: 24000, CreatedAt: october}
+			if err := store.Checks.Create(&pending); err != nil {
+				t.Fatal(err)
+			}
+			approved := Check{ClientID: client.ID, CarID: car.ID, AdminID: admin.ID, Price: 20000, Status: CheckApproved,
+				CreatedAt: october.AddDate(0, 0, 1), DecidedAt: october.AddDate(0, 0, 2)}
+			if err := store.Checks.Create(&approved); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := store.Checks.Get(pending.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Status != CheckPending || got.ClientID != client.ID || got.CarID != car.ID || got.Price != 24000 ||
+				got.AdminID != 0 || !got.CreatedAt.Equal(october) || !got.DecidedAt.IsZero() {
+				t.Errorf("Get ожидающего чека: %+v", *got)
+			}
+			if _, err := store.Checks.Get(approved.ID + 100); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Get несуществующего чека: %v", err)
+			}
+
+			checks, err := store.Checks.List(CheckFilter{Statuses: []CheckStatus{CheckApproved}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(checks) != 1 || checks[0].ID != approved.ID || checks[0].AdminID != admin.ID || !checks[0].DecidedAt.Equal(approved.DecidedAt) {
+				t.Errorf("List подтверждённых: %+v", checks)
+			}
+
+			purchases, err := store.Checks.ListByClient(client.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(purchases) != 2 || purchases[0].CheckID != pending.ID || purchases[0].Brand != car.Brand || purchases[1].Status != CheckApproved {
+				t.Errorf("ListByClient: %+v", purchases)
+			}
+			if purchases, err := store.Checks.ListByClient(client.ID + 100); err != nil || len(purchases) != 0 {
+				t.Errorf("ListByClient чужого клиента: %+v, %v", purchases, err)
+			}
+
+			orders, err := store.Checks.ListPending()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(orders) != 1 || orders[0].CheckID != pending.ID || orders[0].ClientLastName != client.LastName || orders[0].Model != car.Model {
+				t.Errorf("ListPending: %+v", orders)
+			}
+
+			summary, err := store.Checks.SalesSummary(october, october.AddDate(0, 1, 0))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if summary.Units != 1 || summary.Revenue != 20000 {
+				t.Errorf("SalesSummary за октябрь: %+v", summary)
+			}
+			if summary, err := store.Checks.SalesSummary(october.AddDate(0, 1, 0), october.AddDate(0, 2, 0)); err != nil || summary.Units != 0 {
+				t.Errorf("SalesSummary без продаж: %+v, %v", summary, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package db
+
+// FuelType — тип топлива
+type FuelType string
+
+const (
+	FuelPetrol   FuelType = "petrol"
+	FuelDiesel   FuelType = "diesel"
+	FuelHybrid   FuelType = "hybrid"
+	FuelElectric FuelType = "electric"
+	FuelGas      FuelType = "gas"
+)
+
+// FuelTypes — типы топлива в порядке отображения
+var FuelTypes = []FuelType{FuelPetrol, FuelDiesel, FuelHybrid, FuelElectric, FuelGas}
+
+var fuelTitles = map[FuelType]string{
+	FuelPetrol:   "бензин",
+	FuelDiesel:   "дизель",
+	FuelHybrid:   "гибрид",
+	FuelElectric: "электро",
+	FuelGas:      "газ",
+}
+
+// Title возвращает название типа топлива для интерфейса
+func (f FuelType) Title() string { return specTitle(fuelTitles, f) }
+
+// Transmission — тип коробки передач
+type Transmission string
+
+const (
+	TransmissionManual    Transmission = "manual"
+	TransmissionAutomatic Transmission = "automatic"
+	TransmissionRobot     Transmission = "robot"
+	TransmissionCVT       Transmission = "cvt"
+)
+
+// Transmissions — типы коробки передач в порядке отображения
+var Transmissions = []Transmission{TransmissionManual, TransmissionAutomatic, TransmissionRobot, TransmissionCVT}
+
+var transmissionTitles = map[Transmission]string{
+	TransmissionManual:    "механика",
+	TransmissionAutomatic: "автомат",
+	TransmissionRobot:     "робот",
+	TransmissionCVT:       "вариатор",
+}
+
+// Title возвращает название коробки передач для интерфейса
+func (t Transmission) Title() string { return specTitle(transmissionTitles, t) }
+
+// BodyType — тип кузова
+type BodyType string
+
+const (
+	BodySedan       BodyType = "sedan"
+	BodyHatchback   BodyType = "hatchback"
+	BodyWagon       BodyType = "wagon"
+	BodySUV         BodyType = "suv"
+	BodyCoupe       BodyType = "coupe"
+	BodyConvertible BodyType = "convertible"
+	BodyMinivan     BodyType = "minivan"
+	BodyPickup      BodyType = "pickup"
+)
+
+// BodyTypes — типы кузова в порядке отображения
+var BodyTypes = []BodyType{BodySedan, BodyHatchback, BodyWagon, BodySUV, BodyCoupe, BodyConvertible, BodyMinivan, BodyPickup}
+
+var bodyTitles = map[BodyType]string{
+	BodySedan:       "седан",
+	BodyHatchback:   "хэтчбек",
+	BodyWagon:       "универсал",
+	BodySUV:         "внедорожник",
+	BodyCoupe:       "купе",
+	BodyConvertible: "кабриолет",
+	BodyMinivan:     "минивэн",
+	BodyPickup:      "пикап",
+}
+
+// Title возвращает название типа кузова для интерфейса
+func (b BodyType) Title() string { return specTitle(bodyTitles, b) }
+
+// DriveType — тип привода
+type DriveType string
+
+const (
+	DriveFront DriveType = "fwd"
+	DriveRear  DriveType = "rwd"
+	DriveAll   DriveType = "awd"
+)
+
+// DriveTypes — типы привода в порядке отображения
+var DriveTypes = []DriveType{DriveFront, DriveRear, DriveAll}
+
+var driveTitles = map[DriveType]string{
+	DriveFront: "передний",
+	DriveRear:  "задний",
+	DriveAll:   "полный",
+}
+
+// Title возвращает название типа привода для интерфейса
+func (d DriveType) Title() string { return specTitle(driveTitles, d) }
+
+// specTitle возвращает название значения характеристики; пустое значение — «не указан»
+func specTitle[T ~string](titles map[T]string, v T) string {
+	if v == "" {
+		return "не указан"
+	}
+	if title, ok := titles[v]; ok {
+		return title
+	}
+	return string(v)
+}
+
+// knownSpec сообщает, входит ли значение характеристики в список; пустое значение допустимо
+func knownSpec[T ~string](values []T, v T) bool {
+	if v == "" {
+		return true
+	}
+	for _, known := range values {
+		if known == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,844 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewSQLStore создаёт хранилище поверх открытой базы SQLite или PostgreSQL
+func NewSQLStore(db *DB) *Store {
+	return &Store{
+		Cars:         &sqlCarRepository{db: db},
+		Clients:      &sqlClientRepository{db: db},
+		Admins:       &sqlAdminRepository{db: db},
+		Checks:       &sqlCheckRepository{db: db},
+		Photos:       &sqlPhotoRepository{db: db},
+		Purchases:    &sqlPurchaseService{db: db},
+		Dictionaries: &sqlDictionaryRepository{db: db},
+	}
+}
+
+type sqlCarRepository struct {
+	db *DB
+}
+
+func (r *sqlCarRepository) Create(car *Car) error {
+	if err := ValidateCar(*car); err != nil {
+		return err
+	}
+	return inTx(r.db, func(tx *Tx) error {
+		return createCar(tx, car)
+	})
+}
+
+func (r *sqlCarRepository) CreateBatch(cars []Car) error {
+	for i := range cars {
+		if err := ValidateCar(cars[i]); err != nil {
+			return fmt.Errorf("автомобиль %d: %w", i+1, err)
+		}
+	}
+	err := inTx(r.db, func(tx *Tx) error {
+		for i := range cars {
+			if err := createCar(tx, &cars[i]); err != nil {
+				return fmt.Errorf("автомобиль %d: %w", i+1, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		// Транзакция откачена: присвоенные ID недействительны
+		for i := range cars {
+			cars[i].ID = 0
+		}
+	}
+	return err
+}
+
+// createCar добавляет проверенный автомобиль внутри транзакции
+func createCar(tx *Tx, car *Car) error {
+	car.Status = StatusInStock
+	if err := checkVINUnique(tx, car.VIN, 0); err != nil {
+		return err
+	}
+	refs, err := resolveCar(tx, car)
+	if err != nil {
+		return err
+	}
+	car.ID, err = insertID(tx, "ID_Car",
+		"INSERT INTO Cars ("+carWriteColumns+") VALUES ("+placeholders(carColumnCount-1)+")",
+		carValues(*car, refs)...,
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка добавления автомобиля: %w", err)
+	}
+	if err := recordPrice(tx, car.ID, car.Price, 0); err != nil {
+		return err
+	}
+	return recordStatusChange(tx, car.ID, "", car.Status)
+}
+
+// carSpecColumns — столбцы характеристик, общие для Cars и CarDetails
+const carSpecColumns = "VIN, Mileage, EngineVolume, FuelType, Transmission, BodyType, DriveType, Owners"
+
+// carWriteColumns — столбцы Cars в порядке carValues; марка, модель и цвет — ссылки на справочники
+const carWriteColumns = "ID_Brand, ID_Model, YearOfRelease, ID_Color, Price, Status, " + carSpecColumns
+
+// carColumns — столбцы представления CarDetails в порядке, который ожидает scanCar.
+// Автомобили читаются из CarDetails, где вместо ссылок на справочники подставлены названия.
+const carColumns = "ID_Car, Brand, Model, YearOfRelease, Color, Price, Status, " + carSpecColumns
+
+// carColumnCount — число столбцов в carColumns
+const carColumnCount = 15
+
+// carValues возвращает значения столбцов carWriteColumns; пустые VIN и характеристики сохраняются как NULL
+func carValues(car Car, refs carRefs) []any {
+	return []any{
+		refs.brand, refs.model, car.Year, refs.color, car.Price, car.Status,
+		nullString(car.VIN), car.Mileage, car.EngineVolume, nullString(string(car.Fuel)),
+		nullString(string(car.Transmission)), nullString(string(car.Body)), nullString(string(car.Drive)), car.Owners,
+	}
+}
+
+func nullString(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// rowScanner — общее у *sql.Row и *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// queryer — общее у *DB и *Tx
+type queryer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// parseYear разбирает год выпуска, прочитанный строкой: в старой базе встречаются значения
+// вроде «2020е». Такой год читается как 0, а автомобиль остаётся в списках, чтобы его можно было исправить.
+func parseYear(year sql.NullString) int {
+	n, _ := strconv.Atoi(strings.TrimSpace(year.String))
+	return n
+}
+
+func scanCar(row rowScanner) (Car, error) {
+	var car Car
+	// Марка, модель и цвет могут отсутствовать у автомобилей, перенесённых из старых баз
+	var brand, model, color, year, vin, fuel, transmission, body, drive sql.NullString
+	var mileage, owners sql.NullInt64
+	var engineVolume sql.NullFloat64
+	err := row.Scan(&car.ID, &brand, &model, &year, &color, &car.Price, &car.Status,
+		&vin, &mileage, &engineVolume, &fuel, &transmission, &body, &drive, &owners)
+	car.Brand, car.Model, car.Color = brand.String, model.String, color.String
+	car.Year = parseYear(year)
+	car.VIN = vin.String
+	car.Mileage, car.EngineVolume, car.Owners = int(mileage.Int64), engineVolume.Float64, int(owners.Int64)
+	car.Fuel, car.Transmission = FuelType(fuel.String), Transmission(transmission.String)
+	car.Body, car.Drive = BodyType(body.String), DriveType(drive.String)
+	return car, err
+}
+
+func (r *sqlCarRepository) Get(id int) (*Car, error) {
+	car, err := scanCar(r.db.QueryRow("SELECT "+carColumns+" FROM CarDetails WHERE ID_Car = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения автомобиля: %w", err)
+	}
+	return &car, nil
+}
+
+func (r *sqlCarRepository) List(statuses ...CarStatus) ([]Car, error) {
+	query := "SELECT " + carColumns + " FROM CarDetails"
+	var args []any
+	if len(statuses) > 0 {
+		query += " WHERE Status IN (" + placeholders(len(statuses)) + ")"
+		for _, status := range statuses {
+			args = append(args, status)
+		}
+	}
+	return r.query(query+" ORDER BY ID_Car", args...)
+}
+
+func (r *sqlCarRepository) Search(filter CarFilter) ([]Car, error) {
+	where, args := r.where(filter)
+	query := "SELECT " + carColumns + " FROM CarDetails" + where + " ORDER BY " + filter.Sort.orderBy()
+	if filter.Limit > 0 || filter.Offset > 0 {
+		// SQLite не разрешает OFFSET без LIMIT, поэтому без ограничения передаётся наибольшее число
+		limit := int64(math.MaxInt64)
+		if filter.Limit > 0 {
+			limit = int64(filter.Limit)
+		}
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, limit, max(filter.Offset, 0))
+	}
+	return r.query(query, args...)
+}
+
+func (r *sqlCarRepository) Count(filter CarFilter) (int, error) {
+	where, args := r.where(filter)
+	var count int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM CarDetails"+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("ошибка подсчёта автомобилей: %w", err)
+	}
+	return count, nil
+}
+
+// where возвращает условие WHERE (с ведущим пробелом, пустое — без условий) для фильтра и его аргументы
+func (r *sqlCarRepository) where(filter CarFilter) (string, []any) {
+	var where []string
+	var args []any
+	if len(filter.Statuses) > 0 {
+		where = append(where, "Status IN ("+placeholders(len(filter.Statuses))+")")
+		for _, status := range filter.Statuses {
+			args = append(args, status)
+		}
+	}
+	// Марка, модель и цвет сравниваются по DictionaryKey с учётом других написаний из справочника
+	for _, eq := range []struct {
+		kind  DictionaryKind
+		value string
+	}{
+		{DictBrands, filter.Brand}, {DictModels, filter.Model}, {DictColors, filter.Color},
+	} {
+		if eq.value != "" {
+			t, _ := eq.kind.table()
+			where = append(where, fmt.Sprintf(
+				"%[1]s IN (SELECT %[1]s FROM %[2]s WHERE NameKey = ? UNION SELECT ID_Value FROM DictionaryAliases WHERE Kind = ? AND NameKey = ?)",
+				t.id, t.table,
+			))
+			key := DictionaryKey(eq.kind, eq.value)
+			args = append(args, key, eq.kind, key)
+		}
+	}
+	for _, bound := range []struct {
+		cond  string
+		value float64
+	}{
+		{"YearOfRelease >= ?", float64(filter.YearFrom)},
+		{"YearOfRelease <= ?", float64(filter.YearTo)},
+		{"Price >= ?", filter.PriceFrom},
+		{"Price <= ?", filter.PriceTo},
+	} {
+		if bound.value != 0 {
+			where = append(where, bound.cond)
+			args = append(args, bound.value)
+		}
+	}
+	for _, term := range filter.searchTerms() {
+		where = append(where, fmt.Sprintf(
+			`(Brand %[1]s ? ESCAPE '\' OR Model %[1]s ? ESCAPE '\' OR Color %[1]s ? ESCAPE '\' OR CAST(YearOfRelease AS TEXT) %[1]s ? ESCAPE '\')`,
+			r.db.dialect.like(),
+		))
+		pattern := "%" + escapeLike(term) + "%"
+		args = append(args, pattern, pattern, pattern, pattern)
+	}
+
+	if len(where) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(where, " AND "), args
+}
+
+func (r *sqlCarRepository) query(query string, args ...any) ([]Car, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения списка автомобилей: %w", err)
+	}
+	defer rows.Close()
+
+	var cars []Car
+	for rows.Next() {
+		car, err := scanCar(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка чтения автомобиля: %w", err)
+		}
+		cars = append(cars, car)
+	}
+	return cars, rows.Err()
+}
+
+func (r *sqlCarRepository) SetStatus(id int, to CarStatus) error {
+	return inTx(r.db, func(tx *Tx) error {
+		return setStatusTx(tx, id, to, checkManualTransition)
+	})
+}
+
+func (r *sqlCarRepository) StatusHistory(id int) ([]CarStatusChange, error) {
+	rows, err := r.db.Query(
+		"SELECT ID_Car, FromStatus, ToStatus, ChangedAt FROM CarStatusHistory WHERE ID_Car = ? ORDER BY ID_Change", id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения истории статусов: %w", err)
+	}
+	defer rows.Close()
+
+	var history []CarStatusChange
+	for rows.Next() {
+		var change CarStatusChange
+		var from sql.NullString
+		if err := rows.Scan(&change.CarID, &from, &change.To, &change.ChangedAt); err != nil {
+			return nil, fmt.Errorf("ошибка чтения истории статусов: %w", err)
+		}
+		change.From = CarStatus(from.String)
+		history = append(history, change)
+	}
+	return history, rows.Err()
+}
+
+func (r *sqlCarRepository) Update(car Car, adminID int) ([]CarFieldChange, error) {
+	if err := ValidateCar(car); err != nil {
+		return nil, err
+	}
+
+	var changes []CarFieldChange
+	err := inTx(r.db, func(tx *Tx) error {
+		old, err := scanCar(tx.QueryRow("SELECT "+carColumns+" FROM CarDetails WHERE ID_Car = ?", car.ID))
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("ошибка получения автомобиля: %w", err)
+		}
+
+		refs, err := resolveCar(tx, &car)
+		if err != nil {
+			return err
+		}
+		changes = diffCars(old, car, adminID, time.Now().UTC())
+		if len(changes) == 0 {
+			return nil
+		}
+		if err := checkVINUnique(tx, car.VIN, car.ID); err != nil {
+			return err
+		}
+		// Состояние меняется только через SetStatus, поэтому сохраняется прежнее
+		car.Status = old.Status
+		_, err = tx.Exec(
+			"UPDATE Cars SET ("+carWriteColumns+") = ("+placeholders(carColumnCount-1)+") WHERE ID_Car = ?",
+			append(carValues(car, refs), car.ID)...,
+		)
+		if err != nil {
+			return fmt.Errorf("ошибка сохранения автомобиля: %w", err)
+		}
+		for _, c := range changes {
+			_, err := tx.Exec(
+				"INSERT INTO CarChangeHistory (ID_Car, Field, OldValue, NewValue, ID_Admin, ChangedAt) VALUES (?, ?, ?, ?, ?, ?)",
+				c.CarID, c.Field, c.OldValue, c.NewValue, c.AdminID, c.ChangedAt,
+			)
+			if err != nil {
+				return fmt.Errorf("ошибка записи истории изменений: %w", err)
+			}
+			if c.Field == FieldPrice {
+				if err := recordPrice(tx, car.ID, car.Price, adminID); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+func (r *sqlCarRepository) ChangeHistory(id int) ([]CarFieldChange, error) {
+	rows, err := r.db.Query(
+		"SELECT ID_Car, Field, OldValue, NewValue, COALESCE(ID_Admin, 0), ChangedAt FROM CarChangeHistory WHERE ID_Car = ? ORDER BY ID_Change", id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения истории изменений: %w", err)
+	}
+	defer rows.Close()
+
+	var history []CarFieldChange
+	for rows.Next() {
+		var c CarFieldChange
+		if err := rows.Scan(&c.CarID, &c.Field, &c.OldValue, &c.NewValue, &c.AdminID, &c.ChangedAt); err != nil {
+			return nil, fmt.Errorf("ошибка чтения истории изменений: %w", err)
+		}
+		history = append(history, c)
+	}
+	return history, rows.Err()
+}
+
+func (r *sqlCarRepository) PriceHistory(id int) ([]CarPriceChange, error) {
+	rows, err := r.db.Query(
+		"SELECT ID_Car, Price, COALESCE(ID_Admin, 0), ChangedAt FROM CarPriceHistory WHERE ID_Car = ? ORDER BY ID_Price", id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения истории цен: %w", err)
+	}
+	defer rows.Close()
+
+	var history []CarPriceChange
+	for rows.Next() {
+		var p CarPriceChange
+		if err := rows.Scan(&p.CarID, &p.Price, &p.AdminID, &p.ChangedAt); err != nil {
+			return nil, fmt.Errorf("ошибка чтения истории цен: %w", err)
+		}
+		history = append(history, p)
+	}
+	return history, rows.Err()
+}
+
+// recordPrice добавляет запись в CarPriceHistory; adminID 0 сохраняется как NULL
+func recordPrice(tx *Tx, carID int, price float64, adminID int) error {
+	var admin any
+	if adminID != 0 {
+		admin = adminID
+	}
+	_, err := tx.Exec(
+		"INSERT INTO CarPriceHistory (ID_Car, Price, ID_Admin, ChangedAt) VALUES (?, ?, ?, ?)",
+		carID, price, admin, time.Now().UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка записи истории цен: %w", err)
+	}
+	return nil
+}
+
+// checkVINUnique возвращает ErrDuplicateVIN, если VIN занят другим автомобилем, кроме exceptID
+func checkVINUnique(tx *Tx, vin string, exceptID int) error {
+	if vin == "" {
+		return nil
+	}
+	var exists bool
+	err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM Cars WHERE VIN = ? AND ID_Car <> ?)", vin, exceptID).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("ошибка проверки VIN: %w", err)
+	}
+	if exists {
+		return ErrDuplicateVIN
+	}
+	return nil
+}
+
+// setStatusTx проверяет переход функцией check и меняет состояние автомобиля внутри транзакции
+func setStatusTx(tx *Tx, id int, to CarStatus, check func(from, to CarStatus) error) error {
+	var from CarStatus
+	err := tx.QueryRow("SELECT Status FROM Cars WHERE ID_Car = ?", id).Scan(&from)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка получения статуса автомобиля: %w", err)
+	}
+	if err := check(from, to); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("UPDATE Cars SET Status = ? WHERE ID_Car = ?", to, id); err != nil {
+		return fmt.Errorf("ошибка смены статуса автомобиля: %w", err)
+	}
+	return recordStatusChange(tx, id, from, to)
+}
+
+// recordStatusChange добавляет запись в CarStatusHistory
+func recordStatusChange(tx *Tx, carID int, from, to CarStatus) error {
+	var fromValue any
+	if from != "" {
+		fromValue = from
+	}
+	_, err := tx.Exec(
+		"INSERT INTO CarStatusHistory (ID_Car, FromStatus, ToStatus, ChangedAt) VALUES (?, ?, ?, ?)",
+		carID, fromValue, to, time.Now().UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка записи истории статусов: %w", err)
+	}
+	return nil
+}
+
+type sqlClientRepository struct {
+	db *DB
+}
+
+func (r *sqlClientRepository) Create(client *Client) error {
+	id, err := insertID(r.db, "ID_Client",
+		"INSERT INTO Client (Name, LastName, Phone, Login, Password) VALUES (?, ?, ?, ?, ?)",
+		client.Name, client.LastName, client.Phone, client.Login, client.Password,
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка регистрации клиента: %w", err)
+	}
+	client.ID = id
+	return nil
+}
+
+func (r *sqlClientRepository) GetByLogin(login string) (*Client, error) {
+	var c Client
+	err := r.db.QueryRow(
+		"SELECT ID_Client, Name, LastName, Phone, Login, Password FROM Client WHERE Login = ?", login,
+	).Scan(&c.ID, &c.Name, &c.LastName, &c.Phone, &c.Login, &c.Password)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения клиента: %w", err)
+	}
+	return &c, nil
+}
+
+func (r *sqlClientRepository) UpdatePassword(id int, password string) error {
+	res, err := r.db.Exec("UPDATE Client SET Password = ? WHERE ID_Client = ?", password, id)
+	if err != nil {
+		return fmt.Errorf("ошибка обновления пароля: %w", err)
+	}
+	return expectAffected(res)
+}
+
+func (r *sqlClientRepository) List() ([]Client, error) {
+	rows, err := r.db.Query("SELECT ID_Client, Name, LastName, Phone, Login FROM Client ORDER BY ID_Client")
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения пользователей: %w", err)
+	}
+	defer rows.Close()
+
+	var clients []Client
+	for rows.Next() {
+		var c Client
+		if err := rows.Scan(&c.ID, &c.Name, &c.LastName, &c.Phone, &c.Login); err != nil {
+			return nil, fmt.Errorf("ошибка чтения пользователя: %w", err)
+		}
+		clients = append(clients, c)
+	}
+	return clients, rows.Err()
+}
+
+func (r *sqlClientRepository) Delete(id int) error {
+	return inTx(r.db, func(tx *Tx) error {
+		if err := releasePendingCars(tx, id); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("DELETE FROM Checks WHERE ID_Client = ?", id); err != nil {
+			return fmt.Errorf("ошибка удаления чеков пользователя: %w", err)
+		}
+		res, err := tx.Exec("DELETE FROM Client WHERE ID_Client = ?", id)
+		if err != nil {
+			return fmt.Errorf("ошибка удаления пользователя: %w", err)
+		}
+		return expectAffected(res)
+	})
+}
+
+// releasePendingCars возвращает в продажу автомобили, забронированные неподтверждёнными
+// заказами клиента: после удаления заказов бронь снять было бы уже некому
+func releasePendingCars(tx *Tx, clientID int) error {
+	rows, err := tx.Query(
+		"SELECT c.ID_Car FROM Checks ch JOIN Cars c ON c.ID_Car = ch.ID_Car WHERE ch.ID_Client = ? AND ch.Status = ? AND c.Status = ?",
+		clientID, CheckPending, StatusReserved,
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка поиска заказов пользователя: %w", err)
+	}
+	var carIDs []int
+	for rows.Next() {
+		var carID int
+		if err := rows.Scan(&carID); err != nil {
+			rows.Close()
+			return fmt.Errorf("ошибка чтения заказов пользователя: %w", err)
+		}
+		carIDs = append(carIDs, carID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, carID := range carIDs {
+		if err := setStatusTx(tx, carID, StatusInStock, checkTransition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type sqlAdminRepository struct {
+	db *DB
+}
+
+func (r *sqlAdminRepository) Create(admin *Admin) error {
+	id, err := insertID(r.db, "ID_Admin",
+		"INSERT INTO Administrator (Name, LastName, Login, Password, Phone) VALUES (?, ?, ?, ?, ?)",
+		admin.Name, admin.LastName, admin.Login, admin.Password, admin.Phone,
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка добавления администратора: %w", err)
+	}
+	admin.ID = id
+	return nil
+}
+
+func (r *sqlAdminRepository) GetByLogin(login string) (*Admin, error) {
+	var a Admin
+	var phone sql.NullString
+	err := r.db.QueryRow(
+		"SELECT ID_Admin, Name, LastName, Login, Password, Phone FROM Administrator WHERE Login = ?", login,
+	).Scan(&a.ID, &a.Name, &a.LastName, &a.Login, &a.Password, &phone)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения администратора: %w", err)
+	}
+	a.Phone = phone.String
+	return &a, nil
+}
+
+func (r *sqlAdminRepository) UpdatePassword(id int, password string) error {
+	res, err := r.db.Exec("UPDATE Administrator SET Password = ? WHERE ID_Admin = ?", password, id)
+	if err != nil {
+		return fmt.Errorf("ошибка обновления пароля: %w", err)
+	}
+	return expectAffected(res)
+}
+
+func (r *sqlAdminRepository) List() ([]Admin, error) {
+	rows, err := r.db.Query("SELECT ID_Admin, Name, LastName, Login, Phone FROM Administrator ORDER BY ID_Admin")
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения администраторов: %w", err)
+	}
+	defer rows.Close()
+
+	var admins []Admin
+	for rows.Next() {
+		var a Admin
+		var phone sql.NullString
+		if err := rows.Scan(&a.ID, &a.Name, &a.LastName, &a.Login, &phone); err != nil {
+			return nil, fmt.Errorf("ошибка чтения администратора: %w", err)
+		}
+		a.Phone = phone.String
+		admins = append(admins, a)
+	}
+	return admins, rows.Err()
+}
+
+type sqlCheckRepository struct {
+	db *DB
+}
+
+func (r *sqlCheckRepository) Create(check *Check) error {
+	if check.Status == "" {
+		check.Status = CheckPending
+	}
+	if check.CreatedAt.IsZero() {
+		check.CreatedAt = time.Now().UTC()
+	}
+	var adminID, decidedAt any
+	if check.AdminID != 0 {
+		adminID = check.AdminID
+	}
+	if !check.DecidedAt.IsZero() {
+		decidedAt = check.DecidedAt
+	}
+	id, err := insertID(r.db, "ID_Check",
+		"INSERT INTO Checks (ID_Client, ID_Car, ID_Admin, Price, Status, CreatedAt, DecidedAt) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		check.ClientID, check.CarID, adminID, check.Price, check.Status, check.CreatedAt.UTC(), decidedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка при добавлении чека: %w", err)
+	}
+	check.ID = id
+	return nil
+}
+
+func (r *sqlCheckRepository) Get(id int) (*Check, error) {
+	return getCheck(r.db, id)
+}
+
+// getCheck читает чек через соединение или транзакцию
+func getCheck(q queryer, id int) (*Check, error) {
+	check, err := scanCheck(q.QueryRow("SELECT "+checkColumns+" FROM Checks WHERE ID_Check = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения чека: %w", err)
+	}
+	return &check, nil
+}
+
+// checkColumns — столбцы таблицы Checks в порядке scanCheck
+const checkColumns = "ID_Check, ID_Client, ID_Car, ID_Admin, Price, Status, CreatedAt, DecidedAt"
+
+// scanCheck читает строку со столбцами checkColumns
+func scanCheck(row rowScanner) (Check, error) {
+	var check Check
+	var adminID sql.NullInt64
+	var createdAt, decidedAt sql.NullTime
+	err := row.Scan(&check.ID, &check.ClientID, &check.CarID, &adminID, &check.Price, &check.Status, &createdAt, &decidedAt)
+	check.AdminID = int(adminID.Int64)
+	check.CreatedAt = createdAt.Time
+	check.DecidedAt = decidedAt.Time
+	return check, err
+}
+
+func (r *sqlCheckRepository) List(filter CheckFilter) ([]Check, error) {
+	where, args := filter.where()
+	rows, err := r.db.Query("SELECT "+checkColumns+" FROM Checks WHERE "+where+" ORDER BY ID_Check", args...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения чеков: %w", err)
+	}
+	defer rows.Close()
+
+	var checks []Check
+	for rows.Next() {
+		check, err := scanCheck(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка чтения чека: %w", err)
+		}
+		checks = append(checks, check)
+	}
+	return checks, rows.Err()
+}
+
+func (r *sqlCheckRepository) ListByClient(clientID int) ([]Purchase, error) {
+	rows, err := r.db.Query(`
+		SELECT chk.ID_Check, c.Brand, c.Model, c.YearOfRelease, chk.Price, chk.Status, chk.CreatedAt, chk.DecidedAt
+		FROM Checks chk
+		LEFT JOIN CarDetails c ON chk.ID_Car = c.ID_Car
+		WHERE chk.ID_Client = ?
+		ORDER BY chk.ID_Check
+	`, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения истории покупок: %w", err)
+	}
+	defer rows.Close()
+
+	var purchases []Purchase
+	for rows.Next() {
+		var p Purchase
+		var brand, model, year sql.NullString
+		var createdAt, decidedAt sql.NullTime
+		if err := rows.Scan(&p.CheckID, &brand, &model, &year, &p.Price, &p.Status, &createdAt, &decidedAt); err != nil {
+			return nil, fmt.Errorf("ошибка чтения истории покупок: %w", err)
+		}
+		p.Brand, p.Model, p.Year = brand.String, model.String, parseYear(year)
+		p.CreatedAt, p.DecidedAt = createdAt.Time, decidedAt.Time
+		p.CarDeleted = !year.Valid
+		purchases = append(purchases, p)
+	}
+	return purchases, rows.Err()
+}
+
+func (r *sqlCheckRepository) ListPending() ([]PendingOrder, error) {
+	rows, err := r.db.Query(`
+		SELECT chk.ID_Check, chk.ID_Client, cl.Name, cl.LastName, chk.ID_Car, c.Brand, c.Model, c.YearOfRelease, chk.Price
+		FROM Checks chk
+		JOIN Client cl ON chk.ID_Client = cl.ID_Client
+		JOIN CarDetails c ON chk.ID_Car = c.ID_Car
+		WHERE chk.Status = ?
+		ORDER BY chk.ID_Check
+	`, CheckPending)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения заказов: %w", err)
+	}
+	defer rows.Close()
+
+	var orders []PendingOrder
+	for rows.Next() {
+		var o PendingOrder
+		var brand, model, year sql.NullString
+		if err := rows.Scan(&o.CheckID, &o.ClientID, &o.ClientName, &o.ClientLastName, &o.CarID, &brand, &model, &year, &o.Price); err != nil {
+			return nil, fmt.Errorf("ошибка чтения заказа: %w", err)
+		}
+		o.Brand, o.Model, o.Year = brand.String, model.String, parseYear(year)
+		orders = append(orders, o)
+	}
+	return orders, rows.Err()
+}
+
+func (r *sqlCheckRepository) SalesSummary(from, to time.Time) (SalesSummary, error) {
+	var summary SalesSummary
+	err := r.db.QueryRow(`
+		SELECT COALESCE(SUM(Price), 0), COUNT(ID_Check)
+		FROM Checks
+		WHERE Status = ? AND DecidedAt >= ? AND DecidedAt < ?
+	`, CheckApproved, from.UTC(), to.UTC()).Scan(&summary.Revenue, &summary.Units)
+	if err != nil {
+		return SalesSummary{}, fmt.Errorf("ошибка подсчёта продаж за период: %w", err)
+	}
+	return summary, nil
+}
+
+func (r *sqlCheckRepository) ListSales(includeArchived bool) ([]Sale, error) {
+	query := `
+		SELECT chk.ID_Check, chk.ID_Car, c.Brand, c.Model, c.YearOfRelease, c.Color, c.Status,
+			COALESCE(chk.ID_Admin, 0), a.Name, a.LastName, chk.Price, chk.CreatedAt, chk.DecidedAt,
+			(SELECT ph.Price FROM CarPriceHistory ph
+			 WHERE ph.ID_Car = chk.ID_Car AND ph.ChangedAt <= chk.CreatedAt ORDER BY ph.ID_Price LIMIT 1)
+		FROM Checks chk
+		LEFT JOIN CarDetails c ON chk.ID_Car = c.ID_Car
+		LEFT JOIN Administrator a ON chk.ID_Admin = a.ID_Admin
+		WHERE chk.Status = ?`
+	args := []any{CheckApproved}
+	if !includeArchived {
+		query += " AND c.Status IS NOT NULL AND c.Status <> ?"
+		args = append(args, StatusArchived)
+	}
+	rows, err := r.db.Query(query+" ORDER BY chk.ID_Check", args...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка анализа продаж: %w", err)
+	}
+	defer rows.Close()
+
+	var sales []Sale
+	for rows.Next() {
+		var s Sale
+		var brand, model, year, color, status, adminName, adminLastName sql.NullString
+		var createdAt, decidedAt sql.NullTime
+		var listed sql.NullFloat64
+		if err := rows.Scan(&s.CheckID, &s.CarID, &brand, &model, &year, &color, &status,
+			&s.AdminID, &adminName, &adminLastName, &s.Price, &createdAt, &decidedAt, &listed); err != nil {
+			return nil, fmt.Errorf("ошибка чтения результатов анализа: %w", err)
+		}
+		s.Year = parseYear(year)
+		s.Brand, s.Model, s.Color = brand.String, model.String, color.String
+		s.CarStatus, s.CarDeleted = CarStatus(status.String), !status.Valid
+		s.AdminName, s.AdminLastName = adminName.String, adminLastName.String
+		s.CreatedAt, s.DecidedAt = createdAt.Time, decidedAt.Time
+		s.ListedPrice = s.Price
+		if listed.Valid {
+			s.ListedPrice = listed.Float64
+		}
+		sales = append(sales, s)
+	}
+	return sales, rows.Err()
+}
+
+// insertID выполняет INSERT и возвращает значение столбца idColumn добавленной строки.
+// Драйвер PostgreSQL не поддерживает LastInsertId, а RETURNING понимают обе СУБД.
+func insertID(q queryer, idColumn, query string, args ...any) (int, error) {
+	var id int
+	err := q.QueryRow(query+" RETURNING "+idColumn, args...).Scan(&id)
+	return id, err
+}
+
+// placeholders возвращает n параметров запроса через запятую: "?, ?, ?"
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// expectAffected возвращает ErrNotFound, если запрос не изменил ни одной строки
+func expectAffected(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// CarStatus — состояние автомобиля в жизненном цикле продажи
+type CarStatus string
+
+const (
+	StatusInStock   CarStatus = "in_stock"  // в наличии, доступен для покупки
+	StatusReserved  CarStatus = "reserved"  // забронирован под оформляемую покупку
+	StatusSold      CarStatus = "sold"      // продан
+	StatusWithdrawn CarStatus = "withdrawn" // временно снят с продажи
+	StatusArchived  CarStatus = "archived"  // удалён администратором
+)
+
+// CarStatuses — все состояния в порядке отображения
+var CarStatuses = []CarStatus{StatusInStock, StatusReserved, StatusSold, StatusWithdrawn, StatusArchived}
+
+// carStatusTransitions — разрешённые переходы между состояниями. Переходы в бронь и из неё
+// выполняет только PurchaseService: бронь ставится заказом и снимается решением по нему.
+// Проданным автомобиль становится только из брони, то есть подтверждением заказа:
+// у каждой продажи есть чек с покупателем и ценой.
+var carStatusTransitions = map[CarStatus][]CarStatus{
+	StatusInStock:   {StatusReserved, StatusWithdrawn, StatusArchived},
+	StatusReserved:  {StatusInStock, StatusSold},
+	StatusSold:      {StatusArchived},
+	StatusWithdrawn: {StatusInStock, StatusArchived},
+	StatusArchived:  {StatusInStock},
+}
+
+var carStatusTitles = map[CarStatus]string{
+	StatusInStock:   "в наличии",
+	StatusReserved:  "забронирован",
+	StatusSold:      "продан",
+	StatusWithdrawn: "снят с продажи",
+	StatusArchived:  "в архиве",
+}
+
+// ErrInvalidTransition возвращается при попытке запрещённого перехода состояния
+var ErrInvalidTransition = errors.New("недопустимая смена статуса автомобиля")
+
+// Title возвращает название состояния для интерфейса
+func (s CarStatus) Title() string {
+	if title, ok := carStatusTitles[s]; ok {
+		return title
+	}
+	return string(s)
+}
+
+// Next возвращает состояния, в которые можно перейти из s
+func (s CarStatus) Next() []CarStatus {
+	return carStatusTransitions[s]
+}
+
+// CanTransitionTo сообщает, разрешён ли переход из s в to
+func (s CarStatus) CanTransitionTo(to CarStatus) bool {
+	for _, next := range carStatusTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// checkTransition возвращает ErrInvalidTransition с пояснением, если переход запрещён
+func checkTransition(from, to CarStatus) error {
+	if !from.CanTransitionTo(to) {
+		return fmt.Errorf("%w: %s → %s", ErrInvalidTransition, from.Title(), to.Title())
+	}
+	return nil
+}
+
+// ManualNext возвращает состояния, в которые администратор может перевести автомобиль из s сам
+func (s CarStatus) ManualNext() []CarStatus {
+	var next []CarStatus
+	for _, to := range carStatusTransitions[s] {
+		if s.CanSetManually(to) {
+			next = append(next, to)
+		}
+	}
+	return next
+}
+
+// CanSetManually сообщает, может ли администратор сам перевести автомобиль из s в to.
+// Бронь вручную не ставится и не снимается: иначе заказ остался бы ждать решения
+// по автомобилю, который уже продан или свободен для другого покупателя.
+func (s CarStatus) CanSetManually(to CarStatus) bool {
+	return s != StatusReserved && to != StatusReserved && s.CanTransitionTo(to)
+}
+
+// checkManualTransition — checkTransition для смены состояния администратором
+func checkManualTransition(from, to CarStatus) error {
+	if err := checkTransition(from, to); err != nil {
+		return err
+	}
+	if !from.CanSetManually(to) {
+		return fmt.Errorf("%w: %s → %s, бронь меняется только решением по заказу", ErrInvalidTransition, from.Title(), to.Title())
+	}
+	return nil
+}
+
+// CarStatusChange — запись истории смены состояния.
+// From пустой для записи о постановке автомобиля на учёт.
+type CarStatusChange struct {
+	CarID     int
+	From      CarStatus
+	To        CarStatus
+	ChangedAt time.Time
+}
+
+// CheckStatus — состояние заказа (чека)
+type CheckStatus string
+
+const (
+	CheckPending  CheckStatus = "pending"  // ожидает решения администратора
+	CheckApproved CheckStatus = "approved" // продажа подтверждена
+	CheckRejected CheckStatus = "rejected" // заказ отклонён
+)
+
+// CheckStatuses — состояния заказа в порядке отображения
+var CheckStatuses = []CheckStatus{CheckPending, CheckApproved, CheckRejected}
+
+var checkStatusTitles = map[CheckStatus]string{
+	CheckPending:  "ожидает подтверждения",
+	CheckApproved: "подтверждён",
+	CheckRejected: "отклонён",
+}
+
+// Title возвращает название состояния заказа для интерфейса
+func (s CheckStatus) Title() string {
+	if title, ok := checkStatusTitles[s]; ok {
+		return title
+	}
+	return string(s)
+}
@@ -0,0 +1,59 @@
+package db
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSetStatusEnforcesTransitions(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			car := addTestCar(t, store)
+			if car.Status != StatusInStock {
+				t.Fatalf("статус нового автомобиля: %s", car.Status)
+			}
+
+			if err := store.Cars.SetStatus(car.ID, StatusSold); !errors.Is(err, ErrInvalidTransition) {
+				t.Errorf("в наличии → продан без заказа: ожидалась ErrInvalidTransition, получено %v", err)
+			}
+			if err := store.Cars.SetStatus(car.ID, StatusWithdrawn); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.Cars.SetStatus(car.ID, StatusSold); !errors.Is(err, ErrInvalidTransition) {
+				t.Errorf("снятый с продажи → продан: ожидалась ErrInvalidTransition, получено %v", err)
+			}
+			if err := store.Cars.SetStatus(car.ID, StatusArchived); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.Cars.SetStatus(car.ID+100, StatusArchived); !errors.Is(err, ErrNotFound) {
+				t.Errorf("несуществующий автомобиль: ожидалась ErrNotFound, получено %v", err)
+			}
+
+			history, err := store.Cars.StatusHistory(car.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := []CarStatusChange{
+				{CarID: car.ID, From: "", To: StatusInStock},
+				{CarID: car.ID, From: StatusInStock, To: StatusWithdrawn},
+				{CarID: car.ID, From: StatusWithdrawn, To: StatusArchived},
+			}
+			if len(history) != len(want) {
+				t.Fatalf("история: %+v", history)
+			}
+			for i, change := range history {
+				if change.From != want[i].From || change.To != want[i].To || change.ChangedAt.IsZero() {
+					t.Errorf("запись %d: %+v, ожидалось %s → %s", i, change, want[i].From, want[i].To)
+				}
+			}
+
+			archived, err := store.Cars.List(StatusArchived)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(archived) != 1 || archived[0].ID != car.ID {
+				t.Errorf("фильтр по статусу: %+v", archived)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package db
+
+import (
+	"car-sales-system/internal/vin"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Допустимый диапазон года выпуска автомобиля
+var (
+	MinCarYear = 1970
+	MaxCarYear = 2024
+)
+
+// MaxEngineVolume — наибольший допустимый объём двигателя, л
+const MaxEngineVolume = 10.0
+
+// ErrInvalidCar оборачивает все ошибки проверки данных автомобиля
+var ErrInvalidCar = errors.New("неверные данные автомобиля")
+
+// ErrDuplicateVIN возвращается, если автомобиль с таким VIN уже есть в базе
+var ErrDuplicateVIN = errors.New("автомобиль с таким VIN уже есть в базе")
+
+// ValidateCar проверяет данные автомобиля перед сохранением.
+// Те же правила применяются в формах добавления и редактирования.
+// VIN и характеристики необязательны, но заполненные должны быть корректными;
+// VIN ожидается нормализованным (vin.Normalize).
+func ValidateCar(car Car) error {
+	if car.VIN != "" {
+		if err := vin.Validate(car.VIN); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidCar, err)
+		}
+	}
+	switch {
+	case strings.TrimSpace(car.Brand) == "" || strings.TrimSpace(car.Model) == "" || strings.TrimSpace(car.Color) == "":
+		return fmt.Errorf("%w: все поля должны быть заполнены", ErrInvalidCar)
+	case containsDigit(car.Brand):
+		return fmt.Errorf("%w: марка не должна содержать цифры", ErrInvalidCar)
+	case containsDigit(car.Color):
+		return fmt.Errorf("%w: цвет не должен содержать цифры", ErrInvalidCar)
+	case car.Year < MinCarYear || car.Year > MaxCarYear:
+		return fmt.Errorf("%w: год выпуска должен быть в диапазоне от %d до %d", ErrInvalidCar, MinCarYear, MaxCarYear)
+	case car.Price <= 0:
+		return fmt.Errorf("%w: цена должна быть больше нуля", ErrInvalidCar)
+	case car.Mileage < 0:
+		return fmt.Errorf("%w: пробег не может быть отрицательным", ErrInvalidCar)
+	case car.EngineVolume < 0 || car.EngineVolume > MaxEngineVolume:
+		return fmt.Errorf("%w: объём двигателя должен быть от 0 до %.0f л", ErrInvalidCar, MaxEngineVolume)
+	case car.Owners < 0:
+		return fmt.Errorf("%w: число владельцев не может быть отрицательным", ErrInvalidCar)
+	case !knownSpec(FuelTypes, car.Fuel), !knownSpec(Transmissions, car.Transmission),
+		!knownSpec(BodyTypes, car.Body), !knownSpec(DriveTypes, car.Drive):
+		return fmt.Errorf("%w: неизвестное значение характеристики", ErrInvalidCar)
+	}
+	return nil
+}
+
+func containsDigit(s string) bool {
+	return strings.IndexFunc(s, unicode.IsDigit) >= 0
+}
+
+// Поля автомобиля, изменения которых записываются в историю
+const (
+	FieldBrand        = "brand"
+	FieldModel        = "model"
+	FieldYear         = "year"
+	FieldColor        = "color"
+	FieldPrice        = "price"
+	FieldVIN          = "vin"
+	FieldMileage      = "mileage"
+	FieldEngineVolume = "engine_volume"
+	FieldFuel         = "fuel"
+	FieldTransmission = "transmission"
+	FieldBody         = "body"
+	FieldDrive        = "drive"
+	FieldOwners       = "owners"
+)
+
+var carFieldTitles = map[string]string{
+	FieldBrand:        "Марка",
+	FieldModel:        "Модель",
+	FieldYear:         "Год выпуска",
+	FieldColor:        "Цвет",
+	FieldPrice:        "Цена",
+	FieldVIN:          "VIN",
+	FieldMileage:      "Пробег",
+	FieldEngineVolume: "Объём двигателя",
+	FieldFuel:         "Топливо",
+	FieldTransmission: "Коробка передач",
+	FieldBody:         "Кузов",
+	FieldDrive:        "Привод",
+	FieldOwners:       "Владельцев",
+}
+
+// CarFieldTitle возвращает название поля автомобиля для интерфейса
+func CarFieldTitle(field string) string {
+	if title, ok := carFieldTitles[field]; ok {
+		return title
+	}
+	return field
+}
+
+// CarFieldChange — запись истории изменения одного поля автомобиля
+type CarFieldChange struct {
+	CarID     int
+	Field     string
+	OldValue  string
+	NewValue  string
+	AdminID   int
+	ChangedAt time.Time
+}
+
+// CarPriceChange — запись истории цены автомобиля.
+// AdminID равен нулю для цены, выставленной при добавлении автомобиля или перенесённой из старой базы.
+type CarPriceChange struct {
+	CarID     int
+	Price     float64
+	AdminID   int
+	ChangedAt time.Time
+}
+
+// diffCars возвращает изменённые поля автомобиля в порядке формы
+func diffCars(old, updated Car, adminID int, at time.Time) []CarFieldChange {
+	fields := []struct {
+		name     string
+		old, new string
+	}{
+		{FieldBrand, old.Brand, updated.Brand},
+		{FieldModel, old.Model, updated.Model},
+		{FieldYear, strconv.Itoa(old.Year), strconv.Itoa(updated.Year)},
+		{FieldColor, old.Color, updated.Color},
+		{FieldPrice, formatPrice(old.Price), formatPrice(updated.Price)},
+		{FieldVIN, old.VIN, updated.VIN},
+		{FieldMileage, strconv.Itoa(old.Mileage), strconv.Itoa(updated.Mileage)},
+		{FieldEngineVolume, formatVolume(old.EngineVolume), formatVolume(updated.EngineVolume)},
+		{FieldFuel, string(old.Fuel), string(updated.Fuel)},
+		{FieldTransmission, string(old.Transmission), string(updated.Transmission)},
+		{FieldBody, string(old.Body), string(updated.Body)},
+		{FieldDrive, string(old.Drive), string(updated.Drive)},
+		{FieldOwners, strconv.Itoa(old.Owners), strconv.Itoa(updated.Owners)},
+	}
+
+	var changes []CarFieldChange
+	for _, f := range fields {
+		if f.old != f.new {
+			changes = append(changes, CarFieldChange{
+				CarID: updated.ID, Field: f.name, OldValue: f.old, NewValue: f.new, AdminID: adminID, ChangedAt: at,
+			})
+		}
+	}
+	return changes
+}
+
+func formatPrice(price float64) string {
+	return strconv.FormatFloat(price, 'f', 2, 64)
+}
+
+func formatVolume(volume float64) string {
+	return strconv.FormatFloat(volume, 'f', 1, 64)
+}
@@ -0,0 +1,172 @@
+package db
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateCar(t *testing.T) {
+	valid := Car{Brand: "Toyota", Model: "Camry", Year: 2020, Color: "Black", Price: 24000}
+	if err := ValidateCar(valid); err != nil {
+		t.Fatalf("корректный автомобиль: %v", err)
+	}
+
+	invalid := map[string]func(c *Car){
+		"пустая модель":       func(c *Car) { c.Model = " " },
+		"цифры в марке":       func(c *Car) { c.Brand = "Toyota2" },
+		"цифры в цвете":       func(c *Car) { c.Color = "Black1" },
+		"год слишком ранний":  func(c *Car) { c.Year = MinCarYear - 1 },
+		"год слишком поздний": func(c *Car) { c.Year = MaxCarYear + 1 },
+		"нулевая цена":        func(c *Car) { c.Price = 0 },
+	}
+	for name, mutate := range invalid {
+		car := valid
+		mutate(&car)
+		if err := ValidateCar(car); !errors.Is(err, ErrInvalidCar) {
+			t.Errorf("%s: ожидалась ErrInvalidCar, получено %v", name, err)
+		}
+	}
+}
+
+func TestUpdateRecordsFieldChanges(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			car := addTestCar(t, store)
+			admin := addTestAdmin(t, store)
+			if err := store.Cars.SetStatus(car.ID, StatusWithdrawn); err != nil {
+				t.Fatal(err)
+			}
+
+			edited := car
+			edited.Color = "White"
+			edited.Price = 23500
+			changes, err := store.Cars.Update(edited, admin.ID)
+			if err != nil {
+				t.Fatalf("Update: %v", err)
+			}
+			if len(changes) != 2 || changes[0].Field != FieldColor || changes[1].Field != FieldPrice {
+				t.Fatalf("изменения: %+v", changes)
+			}
+			assertCarStatus(t, store, car.ID, StatusWithdrawn)
+
+			saved, err := store.Cars.Get(car.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if saved.Color != "White" || saved.Price != 23500 {
+				t.Errorf("сохранённый автомобиль: %+v", saved)
+			}
+
+			if changes, err := store.Cars.Update(edited, admin.ID); err != nil || len(changes) != 0 {
+				t.Errorf("повторное сохранение без изменений: %+v, %v", changes, err)
+			}
+			edited.Year = 1900
+			if _, err := store.Cars.Update(edited, admin.ID); !errors.Is(err, ErrInvalidCar) {
+				t.Errorf("неверный год: ожидалась ErrInvalidCar, получено %v", err)
+			}
+
+			history, err := store.Cars.ChangeHistory(car.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(history) != 2 || history[0].OldValue != "Black" || history[0].NewValue != "White" ||
+				history[1].OldValue != "24000.00" || history[1].AdminID != admin.ID || history[1].ChangedAt.IsZero() {
+				t.Errorf("история изменений: %+v", history)
+			}
+
+			prices, err := store.Cars.PriceHistory(car.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(prices) != 2 || prices[0].Price != 24000 || prices[0].AdminID != 0 ||
+				prices[1].Price != 23500 || prices[1].AdminID != admin.ID {
+				t.Errorf("история цен: %+v", prices)
+			}
+		})
+	}
+}
+
+func TestCarSpecsAndUniqueVIN(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			admin := addTestAdmin(t, store)
+			car := Car{
+				Brand: "Honda", Model: "Accord", Year: 2003, Color: "Silver", Price: 5000,
+				VIN: "1HGCM82633A004352", Mileage: 210000, EngineVolume: 2.4, Fuel: FuelPetrol,
+				Transmission: TransmissionAutomatic, Body: BodySedan, Drive: DriveFront, Owners: 3,
+			}
+			if err := store.Cars.Create(&car); err != nil {
+				t.Fatal(err)
+			}
+			saved, err := store.Cars.Get(car.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *saved != car {
+				t.Errorf("сохранённый автомобиль: %+v, ожидался %+v", *saved, car)
+			}
+
+			duplicate := car
+			if err := store.Cars.Create(&duplicate); !errors.Is(err, ErrDuplicateVIN) {
+				t.Errorf("повторный VIN: ожидалась ErrDuplicateVIN, получено %v", err)
+			}
+			badVIN := car
+			badVIN.VIN = "1HGCM82683A004352"
+			if err := store.Cars.Create(&badVIN); !errors.Is(err, ErrInvalidCar) {
+				t.Errorf("неверная контрольная цифра: ожидалась ErrInvalidCar, получено %v", err)
+			}
+			european := car
+			european.VIN = "WVWZZZ1JZ3W386752"
+			if err := store.Cars.Create(&european); err != nil {
+				t.Errorf("европейский VIN без контрольной цифры: %v", err)
+			}
+
+			// Автомобили без VIN не мешают друг другу
+			first, second := addTestCar(t, store), addTestCar(t, store)
+			second.VIN = car.VIN
+			if _, err := store.Cars.Update(second, admin.ID); !errors.Is(err, ErrDuplicateVIN) {
+				t.Errorf("VIN другого автомобиля: ожидалась ErrDuplicateVIN, получено %v", err)
+			}
+			first.Mileage, first.Owners = 15000, 1
+			changes, err := store.Cars.Update(first, admin.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(changes) != 2 || changes[0].Field != FieldMileage || changes[1].Field != FieldOwners {
+				t.Errorf("изменения характеристик: %+v", changes)
+			}
+		})
+	}
+}
+
+func TestCreateBatchIsAtomic(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			cars := []Car{
+				{Brand: "Kia", Model: "Rio", Year: 2018, Color: "Red", Price: 9000, VIN: "1M8GDM9AXKP042788"},
+				{Brand: "Kia", Model: "Ceed", Year: 2019, Color: "Red", Price: 11000, VIN: "1M8GDM9AXKP042788"},
+			}
+			if err := store.Cars.CreateBatch(cars); !errors.Is(err, ErrDuplicateVIN) {
+				t.Fatalf("повтор VIN в пакете: ожидалась ErrDuplicateVIN, получено %v", err)
+			}
+			if cars[0].ID != 0 {
+				t.Errorf("после отката у автомобиля остался ID %d", cars[0].ID)
+			}
+			list, err := store.Cars.List()
+			if err != nil || len(list) != 0 {
+				t.Fatalf("после неудачного пакета: %v, %v", list, err)
+			}
+
+			cars[1].VIN = ""
+			if err := store.Cars.CreateBatch(cars); err != nil {
+				t.Fatal(err)
+			}
+			for _, car := range cars {
+				saved, err := store.Cars.Get(car.ID)
+				if err != nil || saved.Model != car.Model || saved.Status != StatusInStock {
+					t.Errorf("автомобиль %d: %+v, %v", car.ID, saved, err)
+				}
+			}
+		})
+	}
+}
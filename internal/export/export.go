@@ -0,0 +1,250 @@
+// Package export выгружает автомобили, клиентов, администраторов и чеки в файлы CSV, JSON и XLSX.
+// Пароли клиентов и администраторов не выгружаются.
+package export
+
+import (
+	"car-sales-system/internal/db"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format — формат файла выгрузки
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatJSON Format = "json"
+	FormatXLSX Format = "xlsx"
+)
+
+// Formats — поддерживаемые форматы в порядке отображения; совпадают с расширениями файлов
+var Formats = []Format{FormatCSV, FormatJSON, FormatXLSX}
+
+// ErrFormat возвращается для неподдерживаемого формата
+var ErrFormat = errors.New("поддерживаются форматы CSV, JSON и XLSX")
+
+// FormatFromPath определяет формат по расширению файла
+func FormatFromPath(path string) (Format, error) {
+	return ParseFormat(strings.TrimPrefix(filepath.Ext(path), "."))
+}
+
+// ParseFormat возвращает формат по названию без учёта регистра
+func ParseFormat(name string) (Format, error) {
+	f := Format(strings.ToLower(name))
+	for _, known := range Formats {
+		if f == known {
+			return f, nil
+		}
+	}
+	return "", ErrFormat
+}
+
+// Dataset — выгружаемая таблица
+type Dataset string
+
+const (
+	Cars    Dataset = "cars"
+	Clients Dataset = "clients"
+	Admins  Dataset = "admins"
+	Checks  Dataset = "checks"
+)
+
+// Datasets — выгружаемые таблицы в порядке отображения
+var Datasets = []Dataset{Cars, Clients, Admins, Checks}
+
+var datasetTitles = map[Dataset]string{
+	Cars:    "Автомобили",
+	Clients: "Клиенты",
+	Admins:  "Администраторы",
+	Checks:  "Чеки",
+}
+
+// Title возвращает название таблицы для интерфейса
+func (d Dataset) Title() string {
+	if title, ok := datasetTitles[d]; ok {
+		return title
+	}
+	return string(d)
+}
+
+// Filter — условия выгрузки. Нулевые поля не ограничивают выборку;
+// поля, не относящиеся к выгружаемой таблице, не учитываются.
+type Filter struct {
+	// CarStatuses ограничивает выгрузку автомобилей состояниями
+	CarStatuses []db.CarStatus
+	// CheckStatuses ограничивает выгрузку чеков состояниями
+	CheckStatuses []db.CheckStatus
+	// From и To ограничивают время оформления чеков промежутком [From, To)
+	From time.Time
+	To   time.Time
+}
+
+// DayRange возвращает промежуток с начала дня first до конца дня last.
+// Нулевая дата оставляет соответствующую границу открытой.
+func DayRange(first, last time.Time) (from, to time.Time, err error) {
+	if !first.IsZero() {
+		from = time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, first.Location())
+	}
+	if !last.IsZero() {
+		to = time.Date(last.Year(), last.Month(), last.Day(), 0, 0, 0, 0, last.Location()).AddDate(0, 0, 1)
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("дата начала периода позже даты конца")
+	}
+	return from, to, nil
+}
+
+// Column — столбец выгрузки. Key — название столбца в CSV и ключ в JSON,
+// совпадает с названиями, которые понимает импорт; Title — заголовок столбца в XLSX.
+type Column struct {
+	Key   string
+	Title string
+}
+
+// Table — выгружаемые данные. Значения ячеек — string, int, float64, time.Time или nil для пустых.
+type Table struct {
+	Dataset Dataset
+	Columns []Column
+	Rows    [][]any
+}
+
+var (
+	carColumns = []Column{
+		{"id", "ID"}, {"brand", "Марка"}, {"model", "Модель"}, {"year", "Год выпуска"}, {"color", "Цвет"},
+		{"price", "Цена"}, {"status", "Статус"}, {"vin", "VIN"}, {"mileage", "Пробег, км"},
+		{"engine_volume", "Объём двигателя, л"}, {"fuel", "Топливо"}, {"transmission", "Коробка передач"},
+		{"body", "Кузов"}, {"drive", "Привод"}, {"owners", "Число владельцев"},
+	}
+	clientColumns = []Column{
+		{"id", "ID"}, {"name", "Имя"}, {"last_name", "Фамилия"}, {"phone", "Телефон"}, {"login", "Логин"},
+	}
+	adminColumns = []Column{
+		{"id", "ID"}, {"name", "Имя"}, {"last_name", "Фамилия"}, {"login", "Логин"}, {"phone", "Телефон"},
+	}
+	checkColumns = []Column{
+		{"id", "ID"}, {"client_id", "ID клиента"}, {"car_id", "ID автомобиля"}, {"admin_id", "ID администратора"},
+		{"price", "Цена"}, {"status", "Статус"}, {"created_at", "Оформлен"}, {"decided_at", "Рассмотрен"},
+	}
+)
+
+// Build читает выгружаемую таблицу из базы
+func Build(store *db.Store, dataset Dataset, filter Filter) (*Table, error) {
+	switch dataset {
+	case Cars:
+		cars, err := store.Cars.List(filter.CarStatuses...)
+		if err != nil {
+			return nil, err
+		}
+		return CarTable(cars), nil
+	case Clients:
+		clients, err := store.Clients.List()
+		if err != nil {
+			return nil, err
+		}
+		return ClientTable(clients), nil
+	case Admins:
+		admins, err := store.Admins.List()
+		if err != nil {
+			return nil, err
+		}
+		return AdminTable(admins), nil
+	case Checks:
+		checks, err := store.Checks.List(db.CheckFilter{Statuses: filter.CheckStatuses, From: filter.From, To: filter.To})
+		if err != nil {
+			return nil, err
+		}
+		return CheckTable(checks), nil
+	default:
+		return nil, fmt.Errorf("неизвестная таблица выгрузки: %s", dataset)
+	}
+}
+
+// CarTable возвращает таблицу автомобилей
+func CarTable(cars []db.Car) *Table {
+	table := &Table{Dataset: Cars, Columns: carColumns}
+	for _, c := range cars {
+		table.Rows = append(table.Rows, []any{
+			c.ID, c.Brand, c.Model, c.Year, c.Color, c.Price, string(c.Status), optional(c.VIN),
+			optional(c.Mileage), optional(c.EngineVolume), optional(string(c.Fuel)), optional(string(c.Transmission)),
+			optional(string(c.Body)), optional(string(c.Drive)), optional(c.Owners),
+		})
+	}
+	return table
+}
+
+// ClientTable возвращает таблицу клиентов без паролей
+func ClientTable(clients []db.Client) *Table {
+	table := &Table{Dataset: Clients, Columns: clientColumns}
+	for _, c := range clients {
+		table.Rows = append(table.Rows, []any{c.ID, c.Name, c.LastName, c.Phone, c.Login})
+	}
+	return table
+}
+
+// AdminTable возвращает таблицу администраторов без паролей
+func AdminTable(admins []db.Admin) *Table {
+	table := &Table{Dataset: Admins, Columns: adminColumns}
+	for _, a := range admins {
+		table.Rows = append(table.Rows, []any{a.ID, a.Name, a.LastName, a.Login, optional(a.Phone)})
+	}
+	return table
+}
+
+// CheckTable возвращает таблицу чеков
+func CheckTable(checks []db.Check) *Table {
+	table := &Table{Dataset: Checks, Columns: checkColumns}
+	for _, c := range checks {
+		table.Rows = append(table.Rows, []any{
+			c.ID, c.ClientID, c.CarID, optional(c.AdminID), c.Price, string(c.Status),
+			optional(c.CreatedAt), optional(c.DecidedAt),
+		})
+	}
+	return table
+}
+
+// optional заменяет нулевое значение незаполненного поля на nil
+func optional[T comparable](v T) any {
+	var zero T
+	if v == zero {
+		return nil
+	}
+	return v
+}
+
+// Write записывает таблицу в выбранном формате
+func Write(w io.Writer, format Format, table *Table) error {
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, table)
+	case FormatJSON:
+		return writeJSON(w, table)
+	case FormatXLSX:
+		return writeXLSX(w, table)
+	default:
+		return ErrFormat
+	}
+}
+
+// dateTimeLayout — формат времени в CSV и XLSX
+const dateTimeLayout = "2006-01-02 15:04:05"
+
+// cellText возвращает значение ячейки строкой; время — в местном часовом поясе
+func cellText(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		return v.Local().Format(dateTimeLayout)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
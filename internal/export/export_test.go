@@ -0,0 +1,198 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"car-sales-system/internal/db"
+	"car-sales-system/internal/importer"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testStore(t *testing.T) *db.Store {
+	t.Helper()
+
+	store := db.NewMemoryStore()
+	cars := []db.Car{
+		{Brand: "Honda", Model: "Accord", Year: 2003, Color: "Серый", Price: 5000.5,
+			VIN: "1HGCM82633A004352", Mileage: 210000, EngineVolume: 2.4, Fuel: db.FuelPetrol, Body: db.BodySedan, Owners: 3},
+		{Brand: "Toyota", Model: "Camry", Year: 2020, Color: "Black", Price: 24000},
+	}
+	if err := store.Cars.CreateBatch(cars); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Cars.SetStatus(cars[1].ID, db.StatusWithdrawn); err != nil {
+		t.Fatal(err)
+	}
+	client := db.Client{Name: "Иван", LastName: "Петров", Phone: "79990000000", Login: "ivan", Password: "hash"}
+	if err := store.Clients.Create(&client); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Admins.Create(&db.Admin{Name: "Михаил", LastName: "Филин", Login: "admin", Password: "hash"}); err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i, status := range []db.CheckStatus{db.CheckApproved, db.CheckRejected, db.CheckApproved} {
+		check := db.Check{ClientID: client.ID, CarID: cars[0].ID, Price: 5000, Status: status, CreatedAt: day.AddDate(0, 0, i*10)}
+		if err := store.Checks.Create(&check); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return store
+}
+
+func TestExportCarsCanBeImported(t *testing.T) {
+	store := testStore(t)
+	table, err := Build(store, Cars, Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	original, err := store.Cars.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, format := range []Format{FormatCSV, FormatJSON} {
+		var buf bytes.Buffer
+		if err := Write(&buf, format, table); err != nil {
+			t.Fatal(err)
+		}
+		report, err := importer.Prepare(&buf, importer.Format(format), db.NewMemoryStore().Cars)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if len(report.Ignored) != 2 || len(report.Accepted()) != len(original) {
+			t.Fatalf("%s: пропущены столбцы %v, принято %d", format, report.Ignored, len(report.Accepted()))
+		}
+		for i, row := range report.Rows {
+			want := original[i]
+			want.ID, want.Status = 0, ""
+			if row.Car != want {
+				t.Errorf("%s: запись %d: %+v, ожидалась %+v", format, row.Number, row.Car, want)
+			}
+		}
+	}
+}
+
+func TestExportFilters(t *testing.T) {
+	store := testStore(t)
+
+	table, err := Build(store, Cars, Filter{CarStatuses: []db.CarStatus{db.StatusInStock}})
+	if err != nil || len(table.Rows) != 1 || table.Rows[0][1] != "Honda" {
+		t.Errorf("автомобили в наличии: %+v, %v", table, err)
+	}
+
+	from, to, err := DayRange(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	table, err = Build(store, Checks, Filter{CheckStatuses: []db.CheckStatus{db.CheckApproved}, From: from, To: to})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(table.Rows) != 1 || table.Rows[0][5] != string(db.CheckApproved) || table.Rows[0][3] != nil {
+		t.Errorf("подтверждённые чеки за период: %+v", table.Rows)
+	}
+	if _, _, err := DayRange(to, from); err == nil {
+		t.Error("период с началом позже конца принят")
+	}
+
+	for _, dataset := range []Dataset{Clients, Admins} {
+		table, err := Build(store, dataset, Filter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := Write(&buf, FormatJSON, table); err != nil {
+			t.Fatal(err)
+		}
+		var objects []map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &objects); err != nil {
+			t.Fatal(err)
+		}
+		if len(objects) != 1 || objects[0]["login"] == nil || strings.Contains(buf.String(), "hash") {
+			t.Errorf("%s: %s", dataset, buf.String())
+		}
+	}
+}
+
+func TestWriteXLSX(t *testing.T) {
+	table := &Table{
+		Dataset: Checks,
+		Columns: []Column{{"id", "ID"}, {"note", "Примечание"}, {"price", "Цена"}, {"at", "Время"}},
+		Rows: [][]any{
+			{1, "<не>&", 1500.25, time.Date(2024, 3, 1, 12, 0, 0, 0, time.Local)},
+			{2, nil, 10.0, nil},
+		},
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatXLSX, table); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string][]byte)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[f.Name], _ = io.ReadAll(rc)
+		rc.Close()
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels"} {
+		if err := xml.Unmarshal(files[name], new(struct{})); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+	if !strings.Contains(string(files["xl/workbook.xml"]), `name="Чеки"`) {
+		t.Errorf("название листа: %s", files["xl/workbook.xml"])
+	}
+
+	var sheet struct {
+		Rows []struct {
+			Cells []struct {
+				Ref    string `xml:"r,attr"`
+				Value  string `xml:"v"`
+				Inline string `xml:"is>t"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := xml.Unmarshal(files["xl/worksheets/sheet1.xml"], &sheet); err != nil {
+		t.Fatal(err)
+	}
+	if len(sheet.Rows) != 3 {
+		t.Fatalf("строк на листе: %d", len(sheet.Rows))
+	}
+	cells := map[string]string{}
+	for _, row := range sheet.Rows {
+		for _, c := range row.Cells {
+			cells[c.Ref] = c.Value + c.Inline
+		}
+	}
+	want := map[string]string{
+		"B1": "Примечание", "A2": "1", "B2": "<не>&", "C2": "1500.25", "D2": "2024-03-01 12:00:00", "C3": "10",
+	}
+	for ref, v := range want {
+		if cells[ref] != v {
+			t.Errorf("ячейка %s: %q, ожидалось %q", ref, cells[ref], v)
+		}
+	}
+	if _, ok := cells["B3"]; ok {
+		t.Error("пустое значение записано в ячейку")
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	for i, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		if got := columnName(i); got != want {
+			t.Errorf("columnName(%d) = %s, ожидалось %s", i, got, want)
+		}
+	}
+}
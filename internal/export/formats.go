@@ -0,0 +1,106 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// WriteText печатает таблицу для терминала: столбцы выровнены пробелами,
+// заголовки — названия столбцов заглавными буквами, пустые ячейки — «-»
+func WriteText(w io.Writer, table *Table) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := make([]string, len(table.Columns))
+	for i, c := range table.Columns {
+		header[i] = strings.ToUpper(c.Title)
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	cells := make([]string, len(table.Columns))
+	for _, row := range table.Rows {
+		for i, v := range row {
+			cells[i] = cellText(v)
+			if cells[i] == "" {
+				cells[i] = "-"
+			}
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+// writeCSV записывает таблицу с заголовком. Файл начинается с метки порядка байтов UTF-8,
+// чтобы Excel не искажал кириллицу.
+func writeCSV(w io.Writer, table *Table) error {
+	bw := bufio.NewWriter(w)
+	if _, err := bw.WriteString("\ufeff"); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(bw)
+	header := make([]string, len(table.Columns))
+	for i, c := range table.Columns {
+		header[i] = c.Key
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	record := make([]string, len(table.Columns))
+	for _, row := range table.Rows {
+		for i, v := range row {
+			record[i] = cellText(v)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// writeJSON записывает таблицу массивом объектов с ключами столбцов.
+// Пустые значения записываются как null, время — в формате RFC 3339.
+func writeJSON(w io.Writer, table *Table) error {
+	objects := make([]jsonObject, 0, len(table.Rows))
+	for _, row := range table.Rows {
+		objects = append(objects, jsonObject{columns: table.Columns, values: row})
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(objects)
+}
+
+// jsonObject — строка таблицы, которая кодируется объектом с ключами в порядке столбцов
+type jsonObject struct {
+	columns []Column
+	values  []any
+}
+
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	buf := []byte{'{'}
+	for i, c := range o.columns {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		key, err := json.Marshal(c.Key)
+		if err != nil {
+			return nil, err
+		}
+		v := o.values[i]
+		if t, ok := v.(time.Time); ok {
+			v = t.Format(time.RFC3339)
+		}
+		value, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		buf = append(append(append(buf, key...), ':'), value...)
+	}
+	return append(buf, '}'), nil
+}
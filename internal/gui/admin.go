@@ -1,11 +1,11 @@
 package gui
 
 import (
-	"database/sql"
+	"car-sales-system/internal/auth"
+	"car-sales-system/internal/db"
 	"errors"
 	"fmt"
 	"regexp"
-	"strconv"
 	"strings"
 
 	"fyne.io/fyne/v2"
@@ -17,53 +17,44 @@ import (
 var AnameValidationRegex = regexp.MustCompile(`^[а-яА-Яa-zA-Z]+$`)
 var AphoneValidationRegex = regexp.MustCompile(`^[0-9]+$`)
 
-// StartAdminGUI запускает интерфейс администратора
-func StartAdminGUI(database *sql.DB, app fyne.App) {
-	adminWindow := app.NewWindow("Администратор: Главная")
+// StartAdminGUI запускает интерфейс администратора в рамках его сессии
+func StartAdminGUI(store *db.Store, authenticator auth.Authenticator, app fyne.App, session *auth.Session) {
+	ui := newSessionUI(store, authenticator, app, session)
+	adminWindow := ui.newWindow("Администратор: Главная")
 	adminWindow.Resize(fyne.NewSize(600, 400))
 
 	// Кнопки функционала администратора
-	addCarButton := widget.NewButton("Добавить автомобиль", func() { // Функция доабвления автомобиля
+	addCarButton := ui.button("Добавить автомобиль", func() { // Функция доабвления автомобиля
 		// Реализация добавления автомобиля
-		addCarWindow := app.NewWindow("Добавить автомобиль")
-		addCarWindow.Resize(fyne.NewSize(400, 400))
-
-		brandEntry := CreateValidatedEntry("Марка", addCarWindow, `^[^\d]+$`, "Марка не должна содержать цифры")
-		modelEntry := widget.NewEntry()
-		modelEntry.SetPlaceHolder("Модель")
-		yearEntry := CreateValidatedEntry("Год выпуска", addCarWindow, `^\d+$`, "Год выпуска должен содержать только цифры")
-		colorEntry := CreateValidatedEntry("Цвет", addCarWindow, `^[^\d]+$`, "Цвет не должен содержать цифры")
-		priceEntry := CreateValidatedEntry("Цена", addCarWindow, `^\d+$`, "Цена должна содержать только цифры")
-
-		saveButton := widget.NewButton("Сохранить", func() {
-			brand := brandEntry.Text
-			model := modelEntry.Text
-			year := yearEntry.Text
-			color := colorEntry.Text
-			price := priceEntry.Text
-
-			yearInt, err := strconv.Atoi(year)
-			if err != nil || yearInt > 2024 || yearInt < 1970 {
-				dialog.ShowError(fmt.Errorf("ошибка: Год выпуска должен быть в диапазоне от 1970 до 2024"), addCarWindow)
-				return
-			}
+		addCarWindow := ui.newWindow("Добавить автомобиль")
+		addCarWindow.Resize(fyne.NewSize(450, 600))
 
-			if brand == "" || model == "" || year == "" || color == "" || price == "" {
-				dialog.ShowError(fmt.Errorf("все поля должны быть заполнены"), addCarWindow)
-				return
-			}
+		form := newCarForm(addCarWindow, store.Dictionaries)
 
-			_, err = database.Exec(
-				"INSERT INTO Cars (Brand, Model, YearOfRelease, Color, Price) VALUES (?, ?, ?, ?, ?)",
-				brand, model, year, color, price,
-			)
+		saveButton := ui.button("Сохранить", func() {
+			car, err := form.car()
 			if err != nil {
-				dialog.ShowError(fmt.Errorf("ошибка добавления автомобиля: %v", err), addCarWindow)
+				dialog.ShowError(err, addCarWindow)
 				return
 			}
-
-			dialog.ShowInformation("Успех", "Автомобиль успешно добавлен", addCarWindow)
-
+			create := func() {
+				if err := store.Cars.Create(&car); err != nil {
+					dialog.ShowError(err, addCarWindow)
+					return
+				}
+				dialog.ShowInformation("Успех", "Автомобиль успешно добавлен", addCarWindow)
+			}
+			if mismatches := form.vinMismatches(); len(mismatches) > 0 {
+				dialog.ShowConfirm("Данные не совпадают с VIN",
+					strings.Join(mismatches, "\n")+"\n\nВсё равно сохранить автомобиль?",
+					func(ok bool) {
+						if ok {
+							create()
+						}
+					}, addCarWindow)
+				return
+			}
+			create()
 		})
 
 		cancelButton := widget.NewButton("Отмена", func() {
@@ -72,39 +63,35 @@ func StartAdminGUI(database *sql.DB, app fyne.App) {
 
 		addCarWindow.SetContent(container.NewVBox(
 			widget.NewLabel("Введите данные для нового автомобиля:"),
-			brandEntry,
-			modelEntry,
-			yearEntry,
-			colorEntry,
-			priceEntry,
+			form.content(),
 			container.NewHBox(saveButton, cancelButton),
 		))
 
 		addCarWindow.Show()
 	})
 
-	deleteClientButton := widget.NewButton("Удалить пользователя", func() {
-		openDeleteClientWindow(database, app)
+	importButton := ui.button("Импорт автомобилей", func() {
+		openImportWindow(ui)
+	})
+
+	deleteClientButton := ui.button("Удалить пользователя", func() {
+		openDeleteClientWindow(ui)
 	})
 
-	deleteCarButton := widget.NewButton("Удалить автомобиль", func() { //Удаление автомобиля из базы данных
+	deleteCarButton := ui.button("Удалить автомобиль", func() { //Удаление автомобиля из базы данных
 
-		rows, err := database.Query(`SELECT ID_Car, Brand, Model FROM Cars WHERE IsArchived = FALSE`)
+		// Удалить (перевести в архив) можно только автомобили без оформляемой покупки
+		cars, err := store.Cars.List(db.StatusInStock, db.StatusWithdrawn, db.StatusSold)
 		if err != nil {
-			dialog.ShowError(fmt.Errorf("ошибка получения списка автомобилей: %v", err), adminWindow)
+			dialog.ShowError(err, adminWindow)
 			return
 		}
-		defer rows.Close()
 
 		var carList []string
 		var carIDs []int
-		for rows.Next() {
-			var id int
-			var brand, model string
-			if err := rows.Scan(&id, &brand, &model); err == nil {
-				carList = append(carList, fmt.Sprintf("%s %s", brand, model))
-				carIDs = append(carIDs, id)
-			}
+		for _, car := range cars {
+			carList = append(carList, fmt.Sprintf("%s %s (%s)", car.Brand, car.Model, car.Status.Title()))
+			carIDs = append(carIDs, car.ID)
 		}
 
 		if len(carList) == 0 {
@@ -115,9 +102,8 @@ func StartAdminGUI(database *sql.DB, app fyne.App) {
 		carSelect := widget.NewSelect(carList, func(selected string) {
 			for i, car := range carList {
 				if car == selected {
-					_, err := database.Exec(`UPDATE Cars SET IsArchived = TRUE WHERE ID_Car = ?`, carIDs[i])
-					if err != nil {
-						dialog.ShowError(fmt.Errorf("ошибка удаления автомобиля: %v", err), adminWindow)
+					if err := store.Cars.SetStatus(carIDs[i], db.StatusArchived); err != nil {
+						dialog.ShowError(err, adminWindow)
 					} else {
 						dialog.ShowInformation("успех", "автомобиль успешно удален.", adminWindow)
 					}
@@ -126,7 +112,7 @@ func StartAdminGUI(database *sql.DB, app fyne.App) {
 			}
 		})
 
-		popup := app.NewWindow("Удаление автомобиля")
+		popup := ui.newWindow("Удаление автомобиля")
 		popup.SetContent(container.NewVBox(
 			widget.NewLabel("Выберите автомобиль для удаления:"),
 			carSelect,
@@ -136,70 +122,55 @@ func StartAdminGUI(database *sql.DB, app fyne.App) {
 		popup.Show()
 	})
 
-	analyzeButton := widget.NewButton("Анализ продаж", func() { //Функция для анализа продаж
-		// SQL-запрос для анализа продаж
-		rows, err := database.Query(`
-			SELECT 
-				Cars.Brand, 
-				Cars.Model, 
-				SUM(Checks.Price) AS TotalRevenue, 
-				COUNT(Checks.ID_Check) AS TotalSales
-			FROM Cars
-			JOIN Checks ON Cars.ID_Car = Checks.ID_Car
-			WHERE Cars.IsArchived = FALSE
-			GROUP BY Cars.ID_Car
-			ORDER BY TotalSales DESC
-			LIMIT 3;
-		`)
-		if err != nil {
-			dialog.ShowError(fmt.Errorf("ошибка анализа продаж: %v", err), adminWindow)
-			return
-		}
-		defer rows.Close()
-
-		// Формируем список результатов
-		var results []string
-		for rows.Next() {
-			var brand, model string
-			var totalRevenue float64
-			var totalSales int
-			if err := rows.Scan(&brand, &model, &totalRevenue, &totalSales); err == nil {
-				results = append(results, fmt.Sprintf("%s %s: продаж: %d ,  доход: %.2f Р", brand, model, totalSales, totalRevenue))
-			}
-		}
+	ordersButton := ui.button("Подтверждение заказов", func() {
+		openApprovalQueueWindow(ui)
+	})
 
-		// Проверяем, есть ли результаты
-		if len(results) == 0 {
-			dialog.ShowInformation("Результаты анализа", "Продаж пока нет", adminWindow)
-			return
-		}
+	editCarButton := ui.button("Редактировать автомобиль", func() {
+		openEditCarWindow(ui)
+	})
 
-		// Отображаем результаты в новом окне
-		resultsWindow := app.NewWindow("Результаты анализа")
-		resultsWindow.Resize(fyne.NewSize(400, 300))
-		resultsWindow.SetContent(container.NewVBox(
-			widget.NewLabel("Топ-3 самых продаваемых автомобиля:"),
-			widget.NewLabel(strings.Join(results, "\n")),
-			widget.NewButton("Закрыть", func() {
-				resultsWindow.Close()
-			}),
-		))
-		resultsWindow.Show()
+	carStatusButton := ui.button("Статус автомобиля", func() {
+		openCarStatusWindow(ui)
+	})
+
+	analyzeButton := ui.button("Анализ продаж", func() { //Функция для анализа продаж
+		openSalesAnalysisWindow(ui)
+	})
+
+	dashboardButton := ui.button("Панель продаж", func() {
+		openDashboardWindow(ui)
+	})
+
+	exportButton := ui.button("Выгрузка данных", func() {
+		openExportWindow(ui)
+	})
+
+	dictionariesButton := ui.button("Справочники", func() {
+		openDictionariesWindow(ui)
 	})
 
 	// Размещение кнопок
 	adminWindow.SetContent(container.NewVBox(
 		widget.NewLabel("Добро пожаловать, Администратор!"),
 		addCarButton,
+		importButton,
+		editCarButton,
+		ordersButton,
 		deleteCarButton,
+		carStatusButton,
+		dictionariesButton,
 		deleteClientButton,
 		analyzeButton,
+		dashboardButton,
+		exportButton,
+		ui.button("Выйти", ui.logout),
 	))
 
 	adminWindow.Show()
 }
 
-func openAdminLogin(database *sql.DB, app fyne.App) { //функция входа в систему для админа
+func openAdminLogin(store *db.Store, authenticator auth.Authenticator, app fyne.App) { //функция входа в систему для админа
 	loginWindow := app.NewWindow("Админ: вход")
 	loginWindow.Resize(fyne.NewSize(300, 300))
 
@@ -213,15 +184,15 @@ func openAdminLogin(database *sql.DB, app fyne.App) { //функция вход
 		login := loginEntry.Text
 		password := passwordEntry.Text
 
-		var id int
-		err := database.QueryRow("SELECT ID_Admin FROM Administrator WHERE Login = ? AND Password = ?", login, password).Scan(&id)
+		admin, err := authenticator.LoginAdmin(login, password)
 		if err != nil {
-			dialog.ShowError(fmt.Errorf("неверный логин или пароль"), loginWindow)
+			dialog.ShowError(err, loginWindow)
 			return
 		}
+		session := auth.NewSession(auth.RoleAdmin, admin.ID, admin.Login)
 
 		dialog.ShowInformation("Успешный вход", "Добро пожаловать!", loginWindow)
-		StartAdminGUI(database, app) // Запуск GUI админа
+		StartAdminGUI(store, authenticator, app, session) // Запуск GUI админа
 		loginWindow.Close()
 	})
 
@@ -239,7 +210,12 @@ func openAdminLogin(database *sql.DB, app fyne.App) { //функция вход
 func CreateValidatedEntry(placeHolder string, parentWindow fyne.Window, pattern string, errorMessage string) *widget.Entry { //Функцция проверки вводимых символов
 	entry := widget.NewEntry()
 	entry.SetPlaceHolder(placeHolder)
+	validateInput(entry, parentWindow, pattern, errorMessage)
+	return entry
+}
 
+// validateInput удаляет последний введённый символ и показывает ошибку, если текст не подходит под шаблон
+func validateInput(entry *widget.Entry, parentWindow fyne.Window, pattern string, errorMessage string) {
 	regex := regexp.MustCompile(pattern)
 	entry.OnChanged = func(input string) {
 		if !regex.MatchString(input) && len(input) > 0 {
@@ -255,33 +231,27 @@ func CreateValidatedEntry(placeHolder string, parentWindow fyne.Window, pattern
 			)
 		}
 	}
-
-	return entry
 }
 
-func openDeleteClientWindow(database *sql.DB, app fyne.App) { //Функция удаления пользователя
-	deleteClientWindow := app.NewWindow("Удалить пользователя")
+func openDeleteClientWindow(ui *sessionUI) { //Функция удаления пользователя
+	store := ui.store
+	deleteClientWindow := ui.newWindow("Удалить пользователя")
 	deleteClientWindow.Resize(fyne.NewSize(400, 300))
 
 	// Получение списка пользователей из базы данных
-	rows, err := database.Query("SELECT ID_Client, Name, LastName FROM Client")
+	clientRecords, err := store.Clients.List()
 	if err != nil {
-		dialog.ShowError(fmt.Errorf("ошибка получения пользователей: %v", err), deleteClientWindow)
+		dialog.ShowError(err, deleteClientWindow)
 		return
 	}
-	defer rows.Close()
 
 	var clients []string
 	clientMap := make(map[string]int)
 
-	for rows.Next() {
-		var id int
-		var name, lastName string
-		if err := rows.Scan(&id, &name, &lastName); err == nil {
-			clientLabel := fmt.Sprintf("%s %s (ID: %d)", name, lastName, id)
-			clients = append(clients, clientLabel)
-			clientMap[clientLabel] = id
-		}
+	for _, c := range clientRecords {
+		clientLabel := fmt.Sprintf("%s %s (ID: %d)", c.Name, c.LastName, c.ID)
+		clients = append(clients, clientLabel)
+		clientMap[clientLabel] = c.ID
 	}
 
 	if len(clients) == 0 {
@@ -292,7 +262,7 @@ func openDeleteClientWindow(database *sql.DB, app fyne.App) { //Функция 
 	clientSelect := widget.NewSelect(clients, func(selected string) {})
 	clientSelect.PlaceHolder = "Выберите пользователя"
 
-	deleteButton := widget.NewButton("Удалить", func() {
+	deleteButton := ui.button("Удалить", func() {
 		selectedClient := clientSelect.Selected
 		if selectedClient == "" {
 			dialog.ShowError(fmt.Errorf("пользователь не выбран"), deleteClientWindow)
@@ -301,16 +271,9 @@ func openDeleteClientWindow(database *sql.DB, app fyne.App) { //Функция 
 
 		clientID := clientMap[selectedClient]
 
-		// Удаление пользователя и его чеков из базы данных
-		_, err := database.Exec("DELETE FROM Checks WHERE ID_Client = ?", clientID)
-		if err != nil {
-			dialog.ShowError(fmt.Errorf("ошибка удаления чеков пользователя: %v", err), deleteClientWindow)
-			return
-		}
-
-		_, err = database.Exec("DELETE FROM Client WHERE ID_Client = ?", clientID)
-		if err != nil {
-			dialog.ShowError(fmt.Errorf("ошибка удаления пользователя: %v", err), deleteClientWindow)
+		// Удаление пользователя и его чеков из базы данных; забронированные им автомобили возвращаются в продажу
+		if err := store.Clients.Delete(clientID); err != nil {
+			dialog.ShowError(err, deleteClientWindow)
 			return
 		}
 
@@ -0,0 +1,238 @@
+package gui
+
+import (
+	"car-sales-system/internal/analytics"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// dateLayout — формат ввода дат в интерфейсе
+const dateLayout = "02.01.2006"
+
+// openSalesAnalysisWindow показывает продажи за выбранный период в сравнении
+// с предыдущим периодом и настраиваемую таблицу продаж по группам
+func openSalesAnalysisWindow(ui *sessionUI) {
+	store := ui.store
+	analysisWindow := ui.newWindow("Анализ продаж")
+	analysisWindow.Resize(fyne.NewSize(600, 650))
+
+	fromEntry := widget.NewEntry()
+	fromEntry.SetPlaceHolder("С (ДД.ММ.ГГГГ)")
+	toEntry := widget.NewEntry()
+	toEntry.SetPlaceHolder("По (ДД.ММ.ГГГГ)")
+	customRange := container.NewGridWithColumns(2, fromEntry, toEntry)
+	customRange.Hide()
+
+	titles := make([]string, len(analytics.Granularities))
+	byTitle := make(map[string]analytics.Granularity, len(analytics.Granularities))
+	for i, g := range analytics.Granularities {
+		titles[i] = g.Title()
+		byTitle[g.Title()] = g
+	}
+	granularitySelect := widget.NewSelect(titles, func(title string) {
+		if byTitle[title] == analytics.Custom {
+			customRange.Show()
+		} else {
+			customRange.Hide()
+		}
+	})
+	granularitySelect.SetSelected(analytics.Month.Title())
+
+	reportLabel := widget.NewLabel("")
+
+	// selectedPeriod собирает период из выбранного вида и введённых дат
+	selectedPeriod := func() (analytics.Period, error) {
+		g := byTitle[granularitySelect.Selected]
+		if g != analytics.Custom {
+			return analytics.PeriodContaining(g, time.Now())
+		}
+		from, err := time.ParseInLocation(dateLayout, fromEntry.Text, time.Local)
+		if err != nil {
+			return analytics.Period{}, fmt.Errorf("неверная дата начала периода, используйте формат ДД.ММ.ГГГГ")
+		}
+		to, err := time.ParseInLocation(dateLayout, toEntry.Text, time.Local)
+		if err != nil {
+			return analytics.Period{}, fmt.Errorf("неверная дата конца периода, используйте формат ДД.ММ.ГГГГ")
+		}
+		return analytics.CustomPeriod(from, to)
+	}
+
+	showButton := ui.button("Показать", func() {
+		period, err := selectedPeriod()
+		if err != nil {
+			dialog.ShowError(err, analysisWindow)
+			return
+		}
+		report, err := analytics.BuildPeriodReport(store.Checks, period)
+		if err != nil {
+			dialog.ShowError(err, analysisWindow)
+			return
+		}
+		reportLabel.SetText(formatPeriodReport(report))
+	})
+
+	salesTable, salesControls := newSalesTable(ui, analysisWindow)
+
+	analysisWindow.SetContent(container.NewBorder(
+		container.NewVBox(
+			widget.NewLabel("Период:"),
+			granularitySelect,
+			customRange,
+			showButton,
+			reportLabel,
+			widget.NewSeparator(),
+			salesControls,
+		),
+		widget.NewButton("Закрыть", func() {
+			analysisWindow.Close()
+		}),
+		nil, nil,
+		salesTable,
+	))
+	showButton.OnTapped()
+	analysisWindow.Show()
+}
+
+// formatPeriodReport выводит показатели периода и их изменение к предыдущему
+func formatPeriodReport(r analytics.PeriodReport) string {
+	return strings.Join([]string{
+		fmt.Sprintf("%s %s (предыдущий: %s)", r.Period.Granularity.Title(), r.Period, r.Previous),
+		fmt.Sprintf("Выручка: %s, изменение: %s", formatMoney(r.Current.Revenue), r.RevenueChange()),
+		fmt.Sprintf("Продано автомобилей: %d, изменение: %s", r.Current.Units, r.UnitsChange()),
+		fmt.Sprintf("Средняя цена: %s, изменение: %s", formatMoney(r.Current.AveragePrice()), r.AveragePriceChange()),
+	}, "\n")
+}
+
+// salesColumns — столбцы таблицы продаж после столбца группы
+var salesColumns = analytics.SortOrders
+
+// newSalesTable создаёт таблицу продаж с группировкой и ограничением числа строк.
+// Нажатие на заголовок показателя сортирует по нему, повторное — меняет направление.
+func newSalesTable(ui *sessionUI, parent fyne.Window) (*widget.Table, fyne.CanvasObject) {
+	query := analytics.SalesQuery{GroupBy: analytics.ByModel, SortBy: analytics.SortUnits, Limit: 3}
+	var rows []analytics.SalesRow
+
+	table := widget.NewTable(
+		func() (int, int) { return len(rows), len(salesColumns) + 1 },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			r := rows[id.Row]
+			label := obj.(*widget.Label)
+			switch {
+			case id.Col == 0:
+				label.SetText(r.Group)
+			case salesColumns[id.Col-1] == analytics.SortUnits:
+				label.SetText(fmt.Sprintf("%d", r.Units))
+			case salesColumns[id.Col-1] == analytics.SortAverage:
+				label.SetText(formatMoney(r.AveragePrice()))
+			case salesColumns[id.Col-1] == analytics.SortDiscount:
+				label.SetText(formatDiscount(r))
+			default:
+				label.SetText(formatMoney(r.Revenue))
+			}
+		},
+	)
+	table.SetColumnWidth(0, 200)
+	for i, column := range salesColumns {
+		width := float32(120)
+		if column == analytics.SortDiscount {
+			width = 170
+		}
+		table.SetColumnWidth(i+1, width)
+	}
+
+	discountLabel := widget.NewLabel("")
+	reload := func() {
+		sales, err := ui.store.Checks.ListSales(query.IncludeArchived)
+		if err != nil {
+			dialog.ShowError(err, parent)
+			return
+		}
+		rows = analytics.GroupSales(sales, query)
+		discountLabel.SetText("Скидка от первой выставленной цены по всем продажам: " + formatDiscount(analytics.TotalSales(sales)))
+		table.Refresh()
+	}
+
+	table.ShowHeaderRow = true
+	table.CreateHeader = func() fyne.CanvasObject { return widget.NewButton("", nil) }
+	table.UpdateHeader = func(id widget.TableCellID, obj fyne.CanvasObject) {
+		header := obj.(*widget.Button)
+		if id.Col == 0 {
+			header.SetText(query.GroupBy.Title())
+			header.OnTapped = nil
+			return
+		}
+		column := salesColumns[id.Col-1]
+		title := column.Title()
+		if column == query.SortBy {
+			if query.Ascending {
+				title += " ▲"
+			} else {
+				title += " ▼"
+			}
+		}
+		header.SetText(title)
+		header.OnTapped = func() {
+			ui.session.Touch()
+			if query.SortBy == column {
+				query.Ascending = !query.Ascending
+			} else {
+				query.SortBy, query.Ascending = column, false
+			}
+			reload()
+		}
+	}
+
+	groupTitles := make([]string, len(analytics.Groupings))
+	groupByTitle := make(map[string]analytics.GroupBy, len(analytics.Groupings))
+	for i, g := range analytics.Groupings {
+		groupTitles[i] = g.Title()
+		groupByTitle[g.Title()] = g
+	}
+	groupSelect := widget.NewSelect(groupTitles, nil)
+	groupSelect.SetSelected(query.GroupBy.Title())
+
+	limitEntry := widget.NewEntry()
+	limitEntry.SetText(strconv.Itoa(query.Limit))
+	limitEntry.SetPlaceHolder("Все")
+
+	archivedCheck := widget.NewCheck("Учитывать архивные автомобили", nil)
+
+	applyButton := ui.button("Построить", func() {
+		limit := 0
+		if text := strings.TrimSpace(limitEntry.Text); text != "" {
+			n, err := strconv.Atoi(text)
+			if err != nil || n < 0 {
+				dialog.ShowError(fmt.Errorf("число строк должно быть целым неотрицательным числом"), parent)
+				return
+			}
+			limit = n
+		}
+		query.GroupBy = groupByTitle[groupSelect.Selected]
+		query.Limit = limit
+		query.IncludeArchived = archivedCheck.Checked
+		reload()
+	})
+	reload()
+
+	controls := container.NewVBox(
+		widget.NewLabel("Продажи по группам (пустое число строк — все группы):"),
+		container.NewGridWithColumns(2, groupSelect, limitEntry),
+		archivedCheck,
+		applyButton,
+		discountLabel,
+	)
+	return table, controls
+}
+
+// formatDiscount выводит скидку суммой и в процентах от первых выставленных цен
+func formatDiscount(r analytics.SalesRow) string {
+	return fmt.Sprintf("%s (%.1f%%)", formatMoney(r.Discount()), r.DiscountPercent())
+}
@@ -0,0 +1,137 @@
+package gui
+
+import (
+	"car-sales-system/internal/db"
+	"fmt"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// openCarDetailWindow показывает все данные автомобиля, фотографии и состав цены.
+// Покупка оформляется только после подтверждения итоговой суммы; onPurchased
+// вызывается после успешного оформления заказа, а также если автомобиль стал недоступен.
+func openCarDetailWindow(ui *sessionUI, carID int, onPurchased func()) {
+	store := ui.store
+	detailWindow := ui.newWindow("Автомобиль")
+	detailWindow.Resize(fyne.NewSize(450, 650))
+
+	car, err := store.Cars.Get(carID)
+	if err != nil {
+		dialog.ShowError(err, detailWindow)
+		return
+	}
+	history, err := store.Cars.PriceHistory(carID)
+	if err != nil {
+		dialog.ShowError(err, detailWindow)
+		return
+	}
+	price := db.BreakdownPrice(*car, history)
+	detailWindow.SetTitle(fmt.Sprintf("%s %s", car.Brand, car.Model))
+
+	attributes := widget.NewForm(
+		widget.NewFormItem("Марка", widget.NewLabel(car.Brand)),
+		widget.NewFormItem("Модель", widget.NewLabel(car.Model)),
+		widget.NewFormItem("Год выпуска", widget.NewLabel(strconv.Itoa(car.Year))),
+		widget.NewFormItem("Цвет", widget.NewLabel(car.Color)),
+		widget.NewFormItem("Статус", widget.NewLabel(car.Status.Title())),
+		widget.NewFormItem("Номер в каталоге", widget.NewLabel(strconv.Itoa(car.ID))),
+	)
+	for _, spec := range carSpecs(*car) {
+		attributes.Append(spec.title, widget.NewLabel(spec.value))
+	}
+
+	priceItems := []*widget.FormItem{
+		widget.NewFormItem("Первоначальная цена", widget.NewLabel(formatMoney(price.ListedPrice))),
+	}
+	if price.Discount > 0 {
+		priceItems = append(priceItems, widget.NewFormItem("Скидка", widget.NewLabel(formatMoneyChange(-price.Discount))))
+	} else if price.Discount < 0 {
+		priceItems = append(priceItems, widget.NewFormItem("Наценка", widget.NewLabel(formatMoneyChange(-price.Discount))))
+	}
+	priceItems = append(priceItems,
+		widget.NewFormItem("Итого к оплате", widget.NewLabelWithStyle(formatMoney(price.Price), fyne.TextAlignLeading, fyne.TextStyle{Bold: true})),
+	)
+
+	photos := newGallery(store, car.ID, detailWindow)
+	photos.onTapped = func(p db.CarPhoto) { showPhotoWindow(ui, detailWindow, p.ID) }
+
+	buyButton := ui.button("Купить", func() {
+		message := fmt.Sprintf("Оформить заказ на %s %s (%d)?\nИтого к оплате: %s", car.Brand, car.Model, car.Year, formatMoney(price.Price))
+		dialog.ShowConfirm("Подтверждение покупки", message, func(confirmed bool) {
+			if !confirmed || !ui.session.Active() {
+				return
+			}
+			// Покупка выполняется одной транзакцией: чек и бронирование автомобиля.
+			// Передаётся цена из подтверждения: если администратор успел её изменить, заказ не оформится.
+			_, err := store.Purchases.Purchase(ui.session.UserID, car.ID, price.Price)
+			if onPurchased != nil {
+				onPurchased()
+			}
+			if err != nil {
+				dialog.ShowError(err, detailWindow)
+				return
+			}
+			dialog.ShowInformation("Заказ оформлен", "Автомобиль забронирован. Покупка ожидает подтверждения администратора.", detailWindow)
+		}, detailWindow)
+	})
+	if car.Status != db.StatusInStock {
+		buyButton.Disable()
+	}
+
+	detailWindow.SetContent(container.NewBorder(
+		nil,
+		container.NewHBox(buyButton, widget.NewButton("Закрыть", func() { detailWindow.Close() })),
+		nil, nil,
+		container.NewVScroll(container.NewVBox(
+			widget.NewLabelWithStyle(fmt.Sprintf("%s %s", car.Brand, car.Model), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			photos.content(),
+			attributes,
+			widget.NewSeparator(),
+			widget.NewLabel("Состав цены:"),
+			widget.NewForm(priceItems...),
+		)),
+	))
+	detailWindow.Show()
+}
+
+// carSpec — заполненная характеристика автомобиля для карточки
+type carSpec struct {
+	title, value string
+}
+
+// carSpecs возвращает заполненные характеристики автомобиля
+func carSpecs(car db.Car) []carSpec {
+	var specs []carSpec
+	add := func(field, value string) {
+		if value != "" {
+			specs = append(specs, carSpec{db.CarFieldTitle(field), value})
+		}
+	}
+	add(db.FieldVIN, car.VIN)
+	if car.Mileage > 0 {
+		add(db.FieldMileage, fmt.Sprintf("%d км", car.Mileage))
+	}
+	if car.EngineVolume > 0 {
+		add(db.FieldEngineVolume, fmt.Sprintf("%.1f л", car.EngineVolume))
+	}
+	if car.Fuel != "" {
+		add(db.FieldFuel, car.Fuel.Title())
+	}
+	if car.Transmission != "" {
+		add(db.FieldTransmission, car.Transmission.Title())
+	}
+	if car.Body != "" {
+		add(db.FieldBody, car.Body.Title())
+	}
+	if car.Drive != "" {
+		add(db.FieldDrive, car.Drive.Title())
+	}
+	if car.Owners > 0 {
+		add(db.FieldOwners, strconv.Itoa(car.Owners))
+	}
+	return specs
+}
@@ -0,0 +1,327 @@
+package gui
+
+import (
+	"car-sales-system/internal/db"
+	"car-sales-system/internal/vin"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// notSpecified — пункт списка характеристики, оставляющий её незаполненной
+const notSpecified = "Не указан"
+
+// carForm — поля данных автомобиля, общие для форм добавления и редактирования.
+// Марка, модель и цвет подсказываются из справочников по мере ввода.
+type carForm struct {
+	brand, model, color *widget.SelectEntry
+	year, price         *widget.Entry
+
+	vin, mileage, engineVolume, owners *widget.Entry
+	fuel                               *specSelect[db.FuelType]
+	transmission                       *specSelect[db.Transmission]
+	body                               *specSelect[db.BodyType]
+	drive                              *specSelect[db.DriveType]
+
+	// vinHint показывает марку и год, расшифрованные из VIN, и расхождения с введёнными
+	vinHint *widget.Label
+
+	// brands, models и colors — значения справочников для подсказок
+	brands, models, colors []db.DictionaryEntry
+}
+
+func newCarForm(parent fyne.Window, dictionaries db.DictionaryRepository) *carForm {
+	f := &carForm{
+		brand: newSuggestEntry("Марка", parent, `^[^\d]+$`, "Марка не должна содержать цифры"),
+		model: newSuggestEntry("Модель", parent, "", ""),
+		year:  CreateValidatedEntry("Год выпуска", parent, `^\d+$`, "Год выпуска должен содержать только цифры"),
+		color: newSuggestEntry("Цвет", parent, `^[^\d]+$`, "Цвет не должен содержать цифры"),
+		price: CreateValidatedEntry("Цена", parent, `^\d+(\.\d{0,2})?$`, "Цена должна быть числом"),
+
+		vin:          CreateValidatedEntry("VIN (заполнит марку и год)", parent, `^[A-HJ-NPR-Za-hj-npr-z0-9]{0,17}$`, "VIN может содержать только латинские буквы, кроме I, O и Q, и цифры"),
+		mileage:      CreateValidatedEntry("Пробег, км", parent, `^\d+$`, "Пробег должен содержать только цифры"),
+		engineVolume: CreateValidatedEntry("Объём двигателя, л", parent, `^\d+(\.\d?)?$`, "Объём двигателя должен быть числом"),
+		owners:       CreateValidatedEntry("Число владельцев", parent, `^\d+$`, "Число владельцев должно содержать только цифры"),
+		fuel:         newSpecSelect("Топливо", db.FuelTypes),
+		transmission: newSpecSelect("Коробка передач", db.Transmissions),
+		body:         newSpecSelect("Кузов", db.BodyTypes),
+		drive:        newSpecSelect("Привод", db.DriveTypes),
+	}
+	f.vinHint = widget.NewLabel("")
+	f.vinHint.Wrapping = fyne.TextWrapWord
+	f.vinHint.Hide()
+	chainOnChanged(f.vin, func() { f.checkVIN(true) })
+	chainOnChanged(&f.brand.Entry, func() {
+		f.checkVIN(false)
+		f.suggest()
+	})
+	chainOnChanged(&f.model.Entry, f.suggest)
+	chainOnChanged(&f.color.Entry, f.suggest)
+	chainOnChanged(f.year, func() { f.checkVIN(false) })
+
+	for _, d := range []struct {
+		kind db.DictionaryKind
+		dest *[]db.DictionaryEntry
+	}{{db.DictBrands, &f.brands}, {db.DictModels, &f.models}, {db.DictColors, &f.colors}} {
+		entries, err := dictionaries.List(d.kind, 0)
+		if err != nil {
+			dialog.ShowError(err, parent)
+			break
+		}
+		*d.dest = entries
+	}
+	f.suggest()
+	return f
+}
+
+// newSuggestEntry создаёт поле ввода с выпадающим списком подсказок; непустой pattern проверяет вводимые символы
+func newSuggestEntry(placeHolder string, parent fyne.Window, pattern, errorMessage string) *widget.SelectEntry {
+	entry := widget.NewSelectEntry(nil)
+	entry.SetPlaceHolder(placeHolder)
+	if pattern != "" {
+		validateInput(&entry.Entry, parent, pattern, errorMessage)
+	}
+	return entry
+}
+
+// suggest обновляет подсказки марки, модели и цвета по введённому тексту.
+// Модели подсказываются только для введённой марки.
+func (f *carForm) suggest() {
+	brandKey := db.DictionaryKey(db.DictBrands, f.brand.Text)
+	var models []db.DictionaryEntry
+	for _, m := range f.models {
+		if db.DictionaryKey(db.DictBrands, m.BrandName) == brandKey {
+			models = append(models, m)
+		}
+	}
+	f.brand.SetOptions(suggestions(db.DictBrands, f.brands, f.brand.Text))
+	f.model.SetOptions(suggestions(db.DictModels, models, f.model.Text))
+	f.color.SetOptions(suggestions(db.DictColors, f.colors, f.color.Text))
+}
+
+// suggestions возвращает названия, начинающиеся с введённого текста без учёта регистра,
+// и названия, для которых введено другое написание того же значения
+func suggestions(kind db.DictionaryKind, entries []db.DictionaryEntry, typed string) []string {
+	prefix := strings.ToLower(strings.TrimSpace(typed))
+	key := db.DictionaryKey(kind, typed)
+	var names []string
+	for _, e := range entries {
+		if strings.HasPrefix(strings.ToLower(e.Name), prefix) || db.DictionaryKey(kind, e.Name) == key {
+			names = append(names, e.Name)
+		}
+	}
+	return names
+}
+
+// chainOnChanged добавляет действие после уже назначенной проверки ввода
+func chainOnChanged(entry *widget.Entry, action func()) {
+	check := entry.OnChanged
+	entry.OnChanged = func(input string) {
+		if check != nil {
+			check(input)
+		}
+		action()
+	}
+}
+
+// decodeVIN расшифровывает введённый VIN; ok ложно, пока VIN не введён полностью
+func (f *carForm) decodeVIN() (info vin.Info, ok bool) {
+	info, err := vin.Decode(f.vin.Text, db.MaxCarYear)
+	return info, err == nil
+}
+
+// checkVIN обновляет подсказку по VIN. При prefill пустые поля марки и года
+// заполняются расшифрованными значениями.
+func (f *carForm) checkVIN(prefill bool) {
+	info, ok := f.decodeVIN()
+	if !ok {
+		f.vinHint.Hide()
+		return
+	}
+	if prefill {
+		if strings.TrimSpace(f.brand.Text) == "" && info.Brand != "" {
+			f.brand.SetText(info.Brand)
+		}
+		if strings.TrimSpace(f.year.Text) == "" && info.Year != 0 {
+			f.year.SetText(strconv.Itoa(info.Year))
+		}
+	}
+
+	lines := []string{formatVINInfo(info)}
+	lines = append(lines, f.vinMismatches()...)
+	f.vinHint.SetText(strings.Join(lines, "\n"))
+	f.vinHint.Show()
+}
+
+// vinMismatches перечисляет расхождения введённых марки и года с расшифровкой VIN
+func (f *carForm) vinMismatches() []string {
+	info, ok := f.decodeVIN()
+	if !ok {
+		return nil
+	}
+	var mismatches []string
+	if brand := strings.TrimSpace(f.brand.Text); !db.BrandMatchesVIN(info.Brand, brand) {
+		mismatches = append(mismatches, fmt.Sprintf("⚠ Марка «%s» не совпадает с маркой по VIN «%s»", brand, info.Brand))
+	}
+	if year, err := strconv.Atoi(strings.TrimSpace(f.year.Text)); err == nil && len(info.Years) > 0 && !containsYear(info.Years, year) {
+		mismatches = append(mismatches, fmt.Sprintf("⚠ Год выпуска %d не совпадает с модельным годом по VIN (%s)", year, joinYears(info.Years)))
+	}
+	return mismatches
+}
+
+// formatVINInfo выводит расшифровку VIN одной строкой
+func formatVINInfo(info vin.Info) string {
+	brand := "производитель не найден"
+	if info.Brand != "" {
+		brand = fmt.Sprintf("%s (%s)", info.Brand, info.Country)
+	}
+	year := "год не определён"
+	if len(info.Years) > 0 {
+		year = "модельный год " + joinYears(info.Years)
+	}
+	return fmt.Sprintf("По VIN: %s, %s", brand, year)
+}
+
+func containsYear(years []int, year int) bool {
+	for _, y := range years {
+		if y == year {
+			return true
+		}
+	}
+	return false
+}
+
+func joinYears(years []int) string {
+	parts := make([]string, len(years))
+	for i, y := range years {
+		parts[i] = strconv.Itoa(y)
+	}
+	return strings.Join(parts, " или ")
+}
+
+// fill заполняет поля данными автомобиля
+func (f *carForm) fill(car db.Car) {
+	f.brand.SetText(car.Brand)
+	f.model.SetText(car.Model)
+	f.year.SetText(strconv.Itoa(car.Year))
+	f.color.SetText(car.Color)
+	f.price.SetText(strconv.FormatFloat(car.Price, 'f', -1, 64))
+
+	f.vin.SetText(car.VIN)
+	f.mileage.SetText(optionalNumber(float64(car.Mileage)))
+	f.engineVolume.SetText(optionalNumber(car.EngineVolume))
+	f.owners.SetText(optionalNumber(float64(car.Owners)))
+	f.fuel.set(car.Fuel)
+	f.transmission.set(car.Transmission)
+	f.body.set(car.Body)
+	f.drive.set(car.Drive)
+}
+
+// car читает и проверяет введённые данные; ID и состояние остаются нулевыми
+func (f *carForm) car() (db.Car, error) {
+	if f.brand.Text == "" || f.model.Text == "" || f.year.Text == "" || f.color.Text == "" || f.price.Text == "" {
+		return db.Car{}, fmt.Errorf("все поля должны быть заполнены")
+	}
+
+	year, err := strconv.Atoi(strings.TrimSpace(f.year.Text))
+	if err != nil {
+		return db.Car{}, fmt.Errorf("ошибка: Год выпуска должен быть числом")
+	}
+	price, err := strconv.ParseFloat(strings.TrimSpace(f.price.Text), 64)
+	if err != nil {
+		return db.Car{}, fmt.Errorf("ошибка: Цена должна быть числом")
+	}
+
+	car := db.Car{
+		Brand: strings.TrimSpace(f.brand.Text),
+		Model: strings.TrimSpace(f.model.Text),
+		Year:  year,
+		Color: strings.TrimSpace(f.color.Text),
+		Price: price,
+
+		VIN:          vin.Normalize(f.vin.Text),
+		Fuel:         f.fuel.value(),
+		Transmission: f.transmission.value(),
+		Body:         f.body.value(),
+		Drive:        f.drive.value(),
+	}
+	var volume, mileage, owners float64
+	for _, n := range []struct {
+		entry *widget.Entry
+		dest  *float64
+	}{{f.mileage, &mileage}, {f.engineVolume, &volume}, {f.owners, &owners}} {
+		if text := strings.TrimSpace(n.entry.Text); text != "" {
+			if *n.dest, err = strconv.ParseFloat(text, 64); err != nil {
+				return db.Car{}, fmt.Errorf("ошибка: поле '%s' должно быть числом", n.entry.PlaceHolder)
+			}
+		}
+	}
+	car.Mileage, car.EngineVolume, car.Owners = int(mileage), volume, int(owners)
+
+	if err := db.ValidateCar(car); err != nil {
+		return db.Car{}, err
+	}
+	return car, nil
+}
+
+// content возвращает поля формы: основные данные столбцом, характеристики — сеткой
+func (f *carForm) content() *fyne.Container {
+	return container.NewVBox(
+		f.vin, f.vinHint,
+		f.brand, f.model, f.year, f.color, f.price,
+		widget.NewLabel("Характеристики (необязательно):"),
+		container.NewGridWithColumns(3, f.mileage, f.engineVolume, f.owners),
+		container.NewGridWithColumns(2, f.fuel.Select, f.transmission.Select),
+		container.NewGridWithColumns(2, f.body.Select, f.drive.Select),
+	)
+}
+
+// optionalNumber возвращает пустую строку для нуля, чтобы незаполненная характеристика оставалась пустой
+func optionalNumber(v float64) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// specSelect — выпадающий список значений характеристики с пунктом «Не указан»
+type specSelect[T interface {
+	~string
+	Title() string
+}] struct {
+	*widget.Select
+	byTitle map[string]T
+}
+
+func newSpecSelect[T interface {
+	~string
+	Title() string
+}](placeHolder string, values []T) *specSelect[T] {
+	s := &specSelect[T]{byTitle: make(map[string]T, len(values))}
+	options := []string{notSpecified}
+	for _, v := range values {
+		options = append(options, v.Title())
+		s.byTitle[v.Title()] = v
+	}
+	s.Select = widget.NewSelect(options, nil)
+	s.PlaceHolder = placeHolder
+	return s
+}
+
+// value возвращает выбранное значение или пустое, если характеристика не указана
+func (s *specSelect[T]) value() T {
+	return s.byTitle[s.Selected]
+}
+
+func (s *specSelect[T]) set(v T) {
+	if v == "" {
+		s.ClearSelected()
+		return
+	}
+	s.SetSelected(v.Title())
+}
@@ -0,0 +1,116 @@
+package gui
+
+import (
+	"car-sales-system/internal/db"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// openCarStatusWindow показывает статус выбранного автомобиля, историю его смены
+// и позволяет перевести автомобиль в одно из разрешённых состояний
+func openCarStatusWindow(ui *sessionUI) {
+	store := ui.store
+	statusWindow := ui.newWindow("Статус автомобиля")
+	statusWindow.Resize(fyne.NewSize(500, 400))
+
+	cars, err := store.Cars.List()
+	if err != nil {
+		dialog.ShowError(err, statusWindow)
+		return
+	}
+
+	var carList []string
+	carMap := make(map[string]int)
+	for _, car := range cars {
+		label := fmt.Sprintf("%s %s (ID: %d)", car.Brand, car.Model, car.ID)
+		carList = append(carList, label)
+		carMap[label] = car.ID
+	}
+
+	currentLabel := widget.NewLabel("")
+	var history []string
+	historyList := widget.NewList(
+		func() int { return len(history) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(i widget.ListItemID, obj fyne.CanvasObject) {
+			obj.(*widget.Label).SetText(history[i])
+		},
+	)
+
+	statusTitles := make(map[string]db.CarStatus)
+	nextSelect := widget.NewSelect(nil, nil)
+	nextSelect.PlaceHolder = "Новый статус"
+
+	var selectedCarID int
+	refresh := func() {
+		car, err := store.Cars.Get(selectedCarID)
+		if err != nil {
+			dialog.ShowError(err, statusWindow)
+			return
+		}
+		current := "Текущий статус: " + car.Status.Title()
+		if car.Status == db.StatusReserved {
+			current += " (сменится решением по заказу)"
+		}
+		currentLabel.SetText(current)
+
+		var options []string
+		for _, next := range car.Status.ManualNext() {
+			options = append(options, next.Title())
+			statusTitles[next.Title()] = next
+		}
+		nextSelect.Options = options
+		nextSelect.ClearSelected()
+
+		changes, err := store.Cars.StatusHistory(selectedCarID)
+		if err != nil {
+			dialog.ShowError(err, statusWindow)
+			return
+		}
+		history = history[:0]
+		for _, change := range changes {
+			from := "—"
+			if change.From != "" {
+				from = change.From.Title()
+			}
+			history = append(history, fmt.Sprintf("%s: %s → %s", change.ChangedAt.Local().Format("02.01.2006 15:04"), from, change.To.Title()))
+		}
+		historyList.Refresh()
+	}
+
+	carSelect := widget.NewSelect(carList, func(selected string) {
+		selectedCarID = carMap[selected]
+		refresh()
+	})
+	carSelect.PlaceHolder = "Выберите автомобиль"
+
+	applyButton := ui.button("Сменить статус", func() {
+		if selectedCarID == 0 || nextSelect.Selected == "" {
+			dialog.ShowError(fmt.Errorf("выберите автомобиль и новый статус"), statusWindow)
+			return
+		}
+		if err := store.Cars.SetStatus(selectedCarID, statusTitles[nextSelect.Selected]); err != nil {
+			dialog.ShowError(err, statusWindow)
+			return
+		}
+		refresh()
+	})
+
+	statusWindow.SetContent(container.NewBorder(
+		container.NewVBox(
+			widget.NewLabel("Выберите автомобиль:"),
+			carSelect,
+			currentLabel,
+			container.NewHBox(nextSelect, applyButton),
+			widget.NewLabel("История статусов:"),
+		),
+		widget.NewButton("Закрыть", func() { statusWindow.Close() }),
+		nil, nil,
+		historyList,
+	))
+	statusWindow.Show()
+}
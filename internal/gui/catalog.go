@@ -0,0 +1,219 @@
+package gui
+
+import (
+	"car-sales-system/internal/db"
+	"fmt"
+	"sort"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// anyOption — пункт списка, снимающий ограничение фильтра
+const anyOption = "Любой"
+
+// openCatalogWindow показывает каталог автомобилей в наличии с фильтрами,
+// сортировкой и поиском. Выбор автомобиля в списке открывает его карточку.
+func openCatalogWindow(ui *sessionUI) {
+	store := ui.store
+	catalogWindow := ui.newWindow("Каталог автомобилей")
+	catalogWindow.Resize(fyne.NewSize(650, 600))
+
+	searchEntry := widget.NewEntry()
+	searchEntry.SetPlaceHolder("Поиск: марка, модель, цвет или год")
+	brandSelect := widget.NewSelect(nil, nil)
+	modelSelect := widget.NewSelect(nil, nil)
+	colorSelect := widget.NewSelect(nil, nil)
+	yearFromEntry := rangeEntry("Год от")
+	yearToEntry := rangeEntry("Год до")
+	priceFromEntry := rangeEntry("Цена от")
+	priceToEntry := rangeEntry("Цена до")
+
+	sortTitles := make([]string, len(db.CarSorts))
+	sortByTitle := make(map[string]db.CarSort, len(db.CarSorts))
+	for i, s := range db.CarSorts {
+		sortTitles[i] = s.Title()
+		sortByTitle[s.Title()] = s
+	}
+	sortSelect := widget.NewSelect(sortTitles, nil)
+	sortSelect.SetSelected(db.SortNewest.Title())
+
+	// Варианты марок, моделей и цветов берутся из автомобилей в наличии
+	fillOptions := func() {
+		cars, err := store.Cars.List(db.StatusInStock)
+		if err != nil {
+			dialog.ShowError(err, catalogWindow)
+			return
+		}
+		brands, colors := map[string]bool{}, map[string]bool{}
+		models := map[string]map[string]bool{}
+		for _, car := range cars {
+			brands[car.Brand], colors[car.Color] = true, true
+			if models[car.Brand] == nil {
+				models[car.Brand] = map[string]bool{}
+			}
+			models[car.Brand][car.Model] = true
+		}
+		brandSelect.Options = selectOptions(brands)
+		colorSelect.Options = selectOptions(colors)
+		// Обработчик назначается заново при каждом обновлении каталога, поэтому сам отмечает действие пользователя
+		brandSelect.OnChanged = func(brand string) {
+			ui.session.Touch()
+			modelSelect.Options = selectOptions(models[brand])
+			modelSelect.SetSelected(anyOption)
+		}
+		brandSelect.SetSelected(anyOption)
+		colorSelect.SetSelected(anyOption)
+	}
+
+	var cars []db.Car
+	covers := map[int]db.CarPhoto{}
+	countLabel := widget.NewLabel("")
+	carList := widget.NewList(
+		func() int { return len(cars) },
+		func() fyne.CanvasObject {
+			cover := canvas.NewImageFromResource(nil)
+			cover.FillMode = canvas.ImageFillContain
+			cover.SetMinSize(fyne.NewSize(64, 48))
+			return container.NewBorder(nil, nil, cover, nil, widget.NewLabel(""))
+		},
+		func(i widget.ListItemID, obj fyne.CanvasObject) {
+			car := cars[i]
+			row := obj.(*fyne.Container)
+			row.Objects[0].(*widget.Label).SetText(fmt.Sprintf("%s %s - %d, %s, Цена: %s", car.Brand, car.Model, car.Year, car.Color, formatMoney(car.Price)))
+
+			cover := row.Objects[1].(*canvas.Image)
+			if p, ok := covers[car.ID]; ok {
+				cover.Resource = fyne.NewStaticResource(fmt.Sprintf("thumb-%d.jpg", p.ID), p.Thumbnail)
+			} else {
+				cover.Resource = theme.FileImageIcon()
+			}
+			cover.Refresh()
+		},
+	)
+
+	search := func() {
+		filter, err := readCatalogFilter(searchEntry, yearFromEntry, yearToEntry, priceFromEntry, priceToEntry)
+		if err != nil {
+			dialog.ShowError(err, catalogWindow)
+			return
+		}
+		filter.Brand = selectedOption(brandSelect)
+		filter.Model = selectedOption(modelSelect)
+		filter.Color = selectedOption(colorSelect)
+		filter.Sort = sortByTitle[sortSelect.Selected]
+
+		cars, err = store.Cars.Search(filter)
+		if err != nil {
+			dialog.ShowError(err, catalogWindow)
+			return
+		}
+		ids := make([]int, len(cars))
+		for i, car := range cars {
+			ids[i] = car.ID
+		}
+		if covers, err = store.Photos.Covers(ids); err != nil {
+			dialog.ShowError(err, catalogWindow)
+			return
+		}
+		carList.UnselectAll()
+		carList.Refresh()
+		if len(cars) == 0 {
+			countLabel.SetText("Автомобили по заданным условиям не найдены")
+		} else {
+			countLabel.SetText(fmt.Sprintf("Найдено автомобилей: %d. Выберите автомобиль, чтобы открыть его карточку.", len(cars)))
+		}
+	}
+	searchEntry.OnSubmitted = func(string) { search() }
+
+	carList.OnSelected = func(i widget.ListItemID) {
+		carList.UnselectAll()
+		if !ui.session.Active() {
+			return
+		}
+		ui.session.Touch()
+		// Купленный или ставший недоступным автомобиль пропадает из каталога
+		openCarDetailWindow(ui, cars[i].ID, search)
+	}
+
+	resetButton := ui.button("Сбросить", func() {
+		for _, e := range []*widget.Entry{searchEntry, yearFromEntry, yearToEntry, priceFromEntry, priceToEntry} {
+			e.SetText("")
+		}
+		sortSelect.SetSelected(db.SortNewest.Title())
+		fillOptions()
+		search()
+	})
+
+	fillOptions()
+	catalogWindow.SetContent(container.NewBorder(
+		container.NewVBox(
+			searchEntry,
+			container.NewGridWithColumns(3, brandSelect, modelSelect, colorSelect),
+			container.NewGridWithColumns(4, yearFromEntry, yearToEntry, priceFromEntry, priceToEntry),
+			container.NewGridWithColumns(3, sortSelect, ui.button("Найти", search), resetButton),
+			countLabel,
+		),
+		widget.NewButton("Закрыть", func() { catalogWindow.Close() }),
+		nil, nil,
+		carList,
+	))
+	search()
+	catalogWindow.Show()
+}
+
+// rangeEntry создаёт поле границы диапазона года или цены
+func rangeEntry(placeHolder string) *widget.Entry {
+	entry := widget.NewEntry()
+	entry.SetPlaceHolder(placeHolder)
+	return entry
+}
+
+// readCatalogFilter разбирает строку поиска и границы диапазонов; пустое поле не ограничивает выборку
+func readCatalogFilter(search, yearFrom, yearTo, priceFrom, priceTo *widget.Entry) (db.CarFilter, error) {
+	filter := db.CarFilter{Statuses: []db.CarStatus{db.StatusInStock}, Search: search.Text}
+
+	for _, f := range []struct {
+		entry *widget.Entry
+		dest  *int
+	}{{yearFrom, &filter.YearFrom}, {yearTo, &filter.YearTo}} {
+		year, err := db.ParseYearBound(f.entry.Text)
+		if err != nil {
+			return db.CarFilter{}, fmt.Errorf("поле '%s' должно содержать год", f.entry.PlaceHolder)
+		}
+		*f.dest = year
+	}
+	for _, f := range []struct {
+		entry *widget.Entry
+		dest  *float64
+	}{{priceFrom, &filter.PriceFrom}, {priceTo, &filter.PriceTo}} {
+		price, err := db.ParsePriceBound(f.entry.Text)
+		if err != nil {
+			return db.CarFilter{}, fmt.Errorf("поле '%s' должно содержать число", f.entry.PlaceHolder)
+		}
+		*f.dest = price
+	}
+	return filter, nil
+}
+
+// selectOptions возвращает пункт «Любой» и значения по алфавиту
+func selectOptions(values map[string]bool) []string {
+	options := make([]string, 0, len(values))
+	for v := range values {
+		options = append(options, v)
+	}
+	sort.Strings(options)
+	return append([]string{anyOption}, options...)
+}
+
+// selectedOption возвращает выбранное значение или пустую строку для «Любой»
+func selectedOption(s *widget.Select) string {
+	if s.Selected == anyOption {
+		return ""
+	}
+	return s.Selected
+}
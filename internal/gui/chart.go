@@ -0,0 +1,298 @@
+package gui
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ChartKind — вид диаграммы
+type ChartKind int
+
+const (
+	BarChart ChartKind = iota
+	LineChart
+	PieChart
+)
+
+// ChartPoint — значение диаграммы с подписью
+type ChartPoint struct {
+	Label string
+	Value float64
+}
+
+// chartPalette — цвета столбцов и секторов, повторяются по кругу
+var chartPalette = []color.NRGBA{
+	{R: 0x42, G: 0x85, B: 0xf4, A: 0xff},
+	{R: 0xdb, G: 0x44, B: 0x37, A: 0xff},
+	{R: 0xf4, G: 0xb4, B: 0x00, A: 0xff},
+	{R: 0x0f, G: 0x9d, B: 0x58, A: 0xff},
+	{R: 0xab, G: 0x47, B: 0xbc, A: 0xff},
+	{R: 0x00, G: 0xac, B: 0xc1, A: 0xff},
+	{R: 0xff, G: 0x70, B: 0x43, A: 0xff},
+	{R: 0x9e, G: 0x9d, B: 0x24, A: 0xff},
+}
+
+const (
+	chartPadding   = float32(8)
+	chartTextSize  = float32(11)
+	chartMinWidth  = float32(280)
+	chartMinHeight = float32(200)
+)
+
+// Chart — диаграмма, нарисованная примитивами canvas без внешних сервисов.
+// Точки задаются при создании и заменяются через SetPoints.
+type Chart struct {
+	widget.BaseWidget
+
+	kind   ChartKind
+	title  string
+	points []ChartPoint
+	// format выводит значение в подписях, по умолчанию с двумя знаками после запятой
+	format func(float64) string
+}
+
+// NewChart создаёт диаграмму заданного вида
+func NewChart(kind ChartKind, title string, points []ChartPoint) *Chart {
+	c := &Chart{kind: kind, title: title, points: points, format: func(v float64) string {
+		return fmt.Sprintf("%.2f", v)
+	}}
+	c.ExtendBaseWidget(c)
+	return c
+}
+
+// SetPoints заменяет данные диаграммы и перерисовывает её
+func (c *Chart) SetPoints(points []ChartPoint) {
+	c.points = points
+	c.Refresh()
+}
+
+// SetValueFormat задаёт формат значений в подписях
+func (c *Chart) SetValueFormat(format func(float64) string) {
+	c.format = format
+	c.Refresh()
+}
+
+// CreateRenderer реализует fyne.Widget
+func (c *Chart) CreateRenderer() fyne.WidgetRenderer {
+	return &chartRenderer{chart: c}
+}
+
+// chartRenderer заново строит фигуры диаграммы при каждом изменении размера или данных
+type chartRenderer struct {
+	chart   *Chart
+	size    fyne.Size
+	objects []fyne.CanvasObject
+}
+
+func (r *chartRenderer) Layout(size fyne.Size) {
+	r.size = size
+	r.build()
+}
+
+func (r *chartRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(chartMinWidth, chartMinHeight)
+}
+
+func (r *chartRenderer) Refresh() {
+	r.build()
+	canvas.Refresh(r.chart)
+}
+
+func (r *chartRenderer) Objects() []fyne.CanvasObject {
+	return r.objects
+}
+
+func (r *chartRenderer) Destroy() {}
+
+func (r *chartRenderer) build() {
+	r.objects = nil
+
+	title := chartText(r.chart.title, theme.TextSize())
+	title.TextStyle.Bold = true
+	title.Move(fyne.NewPos(chartPadding, 0))
+	r.objects = append(r.objects, title)
+
+	top := title.MinSize().Height + chartPadding
+	area := fyne.NewSize(r.size.Width-2*chartPadding, r.size.Height-top-chartPadding)
+	if area.Width <= 0 || area.Height <= 0 {
+		return
+	}
+	if len(r.chart.points) == 0 {
+		empty := chartText("Нет данных", chartTextSize)
+		empty.Move(fyne.NewPos(chartPadding, top))
+		r.objects = append(r.objects, empty)
+		return
+	}
+
+	origin := fyne.NewPos(chartPadding, top)
+	switch r.chart.kind {
+	case PieChart:
+		r.buildPie(origin, area)
+	default:
+		r.buildAxes(origin, area)
+	}
+}
+
+// buildAxes рисует столбчатую или линейную диаграмму с осями и подписями
+func (r *chartRenderer) buildAxes(origin fyne.Position, area fyne.Size) {
+	points := r.chart.points
+	maxValue := 0.0
+	for _, p := range points {
+		maxValue = math.Max(maxValue, p.Value)
+	}
+	if maxValue == 0 {
+		maxValue = 1
+	}
+
+	maxLabel := chartText(r.chart.format(maxValue), chartTextSize)
+	axisX := origin.X + maxLabel.MinSize().Width + chartPadding
+	labelHeight := maxLabel.MinSize().Height
+	plotBottom := origin.Y + area.Height - labelHeight
+	plotHeight := plotBottom - origin.Y - labelHeight
+	plotWidth := origin.X + area.Width - axisX
+	if plotHeight <= 0 || plotWidth <= 0 {
+		return
+	}
+
+	axisColor := theme.Color(theme.ColorNameForeground)
+	yAxis := canvas.NewLine(axisColor)
+	yAxis.Position1 = fyne.NewPos(axisX, origin.Y+labelHeight)
+	yAxis.Position2 = fyne.NewPos(axisX, plotBottom)
+	xAxis := canvas.NewLine(axisColor)
+	xAxis.Position1 = fyne.NewPos(axisX, plotBottom)
+	xAxis.Position2 = fyne.NewPos(axisX+plotWidth, plotBottom)
+	maxLabel.Move(fyne.NewPos(origin.X, origin.Y+labelHeight/2))
+	zeroLabel := chartText(r.chart.format(0), chartTextSize)
+	zeroLabel.Move(fyne.NewPos(axisX-zeroLabel.MinSize().Width-chartPadding/2, plotBottom-labelHeight/2))
+	r.objects = append(r.objects, yAxis, xAxis, maxLabel, zeroLabel)
+
+	slot := plotWidth / float32(len(points))
+	// Подписи оси X прореживаются, чтобы не налезать друг на друга
+	labelStep := 1
+	if widest := widestLabel(points); widest > slot {
+		labelStep = int(math.Ceil(float64(widest / slot)))
+	}
+
+	var prev fyne.Position
+	for i, p := range points {
+		height := float32(p.Value/maxValue) * plotHeight
+		centerX := axisX + slot*float32(i) + slot/2
+
+		switch r.chart.kind {
+		case LineChart:
+			pos := fyne.NewPos(centerX, plotBottom-height)
+			if i > 0 {
+				segment := canvas.NewLine(chartPalette[0])
+				segment.StrokeWidth = 2
+				segment.Position1, segment.Position2 = prev, pos
+				r.objects = append(r.objects, segment)
+			}
+			dot := canvas.NewCircle(chartPalette[0])
+			dot.Resize(fyne.NewSize(6, 6))
+			dot.Move(pos.Subtract(fyne.NewPos(3, 3)))
+			r.objects = append(r.objects, dot)
+			prev = pos
+		default:
+			bar := canvas.NewRectangle(chartPalette[i%len(chartPalette)])
+			barWidth := slot * 0.7
+			bar.Resize(fyne.NewSize(barWidth, height))
+			bar.Move(fyne.NewPos(centerX-barWidth/2, plotBottom-height))
+			r.objects = append(r.objects, bar)
+
+			if p.Value > 0 {
+				value := chartText(r.chart.format(p.Value), chartTextSize)
+				if value.MinSize().Width <= slot {
+					value.Move(fyne.NewPos(centerX-value.MinSize().Width/2, plotBottom-height-labelHeight))
+					r.objects = append(r.objects, value)
+				}
+			}
+		}
+
+		if i%labelStep == 0 {
+			label := chartText(p.Label, chartTextSize)
+			label.Move(fyne.NewPos(centerX-label.MinSize().Width/2, plotBottom))
+			r.objects = append(r.objects, label)
+		}
+	}
+}
+
+// buildPie рисует круговую диаграмму растром и легенду с долями справа от неё
+func (r *chartRenderer) buildPie(origin fyne.Position, area fyne.Size) {
+	points := r.chart.points
+	total := 0.0
+	for _, p := range points {
+		total += math.Max(p.Value, 0)
+	}
+	if total == 0 {
+		empty := chartText("Нет данных", chartTextSize)
+		empty.Move(origin)
+		r.objects = append(r.objects, empty)
+		return
+	}
+
+	// Границы секторов как доли полного круга, начиная с «12 часов» по часовой стрелке
+	bounds := make([]float64, len(points))
+	acc := 0.0
+	for i, p := range points {
+		acc += math.Max(p.Value, 0) / total
+		bounds[i] = acc
+	}
+
+	diameter := fyne.Min(area.Height, area.Width/2)
+	pie := canvas.NewRasterWithPixels(func(x, y, w, h int) color.Color {
+		radius := float64(fyne.Min(float32(w), float32(h))) / 2
+		dx, dy := float64(x)-float64(w)/2, float64(y)-float64(h)/2
+		if dx*dx+dy*dy > radius*radius {
+			return color.Transparent
+		}
+		share := math.Atan2(dx, -dy) / (2 * math.Pi)
+		if share < 0 {
+			share++
+		}
+		for i, b := range bounds {
+			if share <= b {
+				return chartPalette[i%len(chartPalette)]
+			}
+		}
+		return chartPalette[(len(bounds)-1)%len(chartPalette)]
+	})
+	pie.Resize(fyne.NewSize(diameter, diameter))
+	pie.Move(origin)
+	r.objects = append(r.objects, pie)
+
+	legendX := origin.X + diameter + chartPadding*2
+	y := origin.Y
+	for i, p := range points {
+		label := chartText(fmt.Sprintf("%s — %.1f%%", p.Label, math.Max(p.Value, 0)/total*100), chartTextSize)
+		rowHeight := label.MinSize().Height
+		if y+rowHeight > origin.Y+area.Height {
+			break
+		}
+		swatch := canvas.NewRectangle(chartPalette[i%len(chartPalette)])
+		swatch.Resize(fyne.NewSize(rowHeight*0.7, rowHeight*0.7))
+		swatch.Move(fyne.NewPos(legendX, y+rowHeight*0.15))
+		label.Move(fyne.NewPos(legendX+rowHeight, y))
+		r.objects = append(r.objects, swatch, label)
+		y += rowHeight
+	}
+}
+
+func chartText(text string, size float32) *canvas.Text {
+	t := canvas.NewText(text, theme.Color(theme.ColorNameForeground))
+	t.TextSize = size
+	return t
+}
+
+func widestLabel(points []ChartPoint) float32 {
+	widest := float32(0)
+	for _, p := range points {
+		widest = fyne.Max(widest, chartText(p.Label, chartTextSize).MinSize().Width+chartPadding/2)
+	}
+	return widest
+}
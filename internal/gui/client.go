@@ -1,7 +1,8 @@
 package gui
 
 import (
-	"database/sql"
+	"car-sales-system/internal/auth"
+	"car-sales-system/internal/db"
 	"fmt"
 	"regexp"
 	"time"
@@ -14,7 +15,6 @@ import (
 
 var nameValidationRegex = regexp.MustCompile(`^[а-яА-Яa-zA-Z]+$`)
 var phoneValidationRegex = regexp.MustCompile(`^[0-9]+$`)
-var currentClientID int
 
 func createValidatedEntry(placeHolder string, parentWindow fyne.Window) *widget.Entry { //Функция проверки вводимых символов
 	entry := widget.NewEntry()
@@ -50,86 +50,19 @@ func createPhoneValidatedEntry(placeHolder string, parentWindow fyne.Window) *wi
 	return entry
 }
 
-func StartClientGUI(database *sql.DB, app fyne.App) {
-	clientWindow := app.NewWindow("Клиент: Главная")
+// StartClientGUI открывает главное окно клиента в рамках его сессии
+func StartClientGUI(store *db.Store, authenticator auth.Authenticator, app fyne.App, session *auth.Session) {
+	ui := newSessionUI(store, authenticator, app, session)
+	clientWindow := ui.newWindow("Клиент: Главная")
 	clientWindow.Resize(fyne.NewSize(600, 400))
 
 	// Кнопки функционала клиента
-	browseCarsButton := widget.NewButton("Просмотр автомобилей", func() { // фукнция для просмотра и покупки автомобилей
-		dialog.ShowInformation("Важная информация", "Для того чтобы купить автомобиль просто нажмите на него", clientWindow)
-		rows, err := database.Query("SELECT ID_Car, Brand, Model, YearOfRelease, Price FROM Cars")
-		if err != nil {
-			dialog.ShowError(fmt.Errorf("ошибка при загрузке списка автомобилей: %v", err), clientWindow)
-			return
-		}
-		defer rows.Close()
-
-		var cars []string
-		var carIDs []int
-		for rows.Next() {
-			var id int
-			var brand, model string
-			var year int
-			var price float64
-			if err := rows.Scan(&id, &brand, &model, &year, &price); err == nil {
-				carDetails := fmt.Sprintf("%s %s - %d, Цена: %.2f", brand, model, year, price)
-				cars = append(cars, carDetails)
-				carIDs = append(carIDs, id)
-			}
-		}
-
-		if len(cars) == 0 {
-			dialog.ShowInformation("Список пуст", "Автомобили отсутствуют.", clientWindow)
-			return
-		}
-
-		// Создаем виджеты для каждого автомобиля
-		var carWidgets []fyne.CanvasObject
-		for i, car := range cars {
-			index := i // Создаём копию переменной, чтобы избежать проблем с замыканием
-			carButton := widget.NewButton(fmt.Sprintf("Купить: %s Р", car), func() {
-				carID := carIDs[index]
-				var price float64
-				err := database.QueryRow("SELECT Price FROM Cars WHERE ID_Car = ?", carID).Scan(&price)
-				if err != nil {
-					dialog.ShowError(fmt.Errorf("ошибка при получении цены: %v", err), clientWindow)
-					return
-				}
-
-				// Вставка данных в таблицу Checks
-				_, err = database.Exec(
-					"INSERT INTO Checks (ID_Client, ID_Car, ID_Admin, Price) VALUES (?, ?, NULL, ?)",
-					currentClientID, carID, price,
-				)
-				if err != nil {
-					dialog.ShowError(fmt.Errorf("ошибка при добавлении чека: %v", err), clientWindow)
-					return
-				}
-
-				// Сообщение об успешной покупке
-				dialog.ShowInformation("Успешная покупка", "Автомобиль успешно куплен!", clientWindow)
-			})
-			carWidgets = append(carWidgets, carButton)
-		}
-
-		// Создаем контейнер для списка автомобилей
-		carList := container.NewVBox(carWidgets...)
-
-		// Открываем всплывающее окно с прокручиваемым списком автомобилей
-		popup := app.NewWindow("Список автомобилей")
-		popup.SetContent(container.NewVScroll(carList))
-		popup.Resize(fyne.NewSize(400, 300))
-		popup.Show()
+	browseCarsButton := ui.button("Просмотр автомобилей", func() { // фукнция для просмотра и покупки автомобилей
+		openCatalogWindow(ui)
 	})
 
-	purchaseHistoryButton := widget.NewButton("История покупок", func() { // Фукнция которая показывает историю покупок клиента
-		rows, err := database.Query(`
-			SELECT c.Brand, c.Model, c.YearOfRelease, chk.Price
-			FROM Checks chk
-			LEFT JOIN Cars c ON chk.ID_Car = c.ID_Car
-			WHERE chk.ID_Client = ?
-			  AND (c.IsArchived = FALSE OR c.IsArchived = TRUE OR c.ID_Car IS NULL)
-		`, currentClientID)
+	purchaseHistoryButton := ui.button("История покупок", func() { // Фукнция которая показывает историю покупок клиента
+		records, err := store.Checks.ListByClient(session.UserID)
 		if err != nil {
 			fyne.CurrentApp().SendNotification(&fyne.Notification{
 				Title:   "Ошибка",
@@ -137,21 +70,23 @@ func StartClientGUI(database *sql.DB, app fyne.App) {
 			})
 			return
 		}
-		defer rows.Close()
 
 		var purchases []string
-		for rows.Next() {
-			var brand, model string
-			var year sql.NullInt32
-			var price float64
-			if err := rows.Scan(&brand, &model, &year, &price); err == nil {
-				if year.Valid {
-					purchase := fmt.Sprintf("%s %s (%d), Цена: %.2f Р", brand, model, year.Int32, price)
-					purchases = append(purchases, purchase)
-				} else {
-					purchase := fmt.Sprintf("%s %s (удалено из базы), Цена: %.2f", brand, model, price)
-					purchases = append(purchases, purchase)
-				}
+		for _, p := range records {
+			state := p.Status.Title()
+			if !p.DecidedAt.IsZero() {
+				state += " " + p.DecidedAt.Local().Format("02.01.2006 15:04")
+			}
+			ordered := ""
+			if !p.CreatedAt.IsZero() {
+				ordered = p.CreatedAt.Local().Format("02.01.2006") + ": "
+			}
+			if !p.CarDeleted {
+				purchase := ordered + fmt.Sprintf("%s %s (%d), Цена: %s — %s", p.Brand, p.Model, p.Year, formatMoney(p.Price), state)
+				purchases = append(purchases, purchase)
+			} else {
+				purchase := ordered + fmt.Sprintf("%s %s (удалено из базы), Цена: %s — %s", p.Brand, p.Model, formatMoney(p.Price), state)
+				purchases = append(purchases, purchase)
 			}
 		}
 
@@ -167,23 +102,26 @@ func StartClientGUI(database *sql.DB, app fyne.App) {
 			},
 		)
 
-		popup := app.NewWindow("История покупок")
+		popup := ui.newWindow("История покупок")
 		popup.SetContent(container.NewMax(purchaseList))
 		popup.Resize(fyne.NewSize(400, 300))
 		popup.Show()
 	})
 
+	logoutButton := ui.button("Выйти", ui.logout)
+
 	// Размещение кнопок
 	clientWindow.SetContent(container.NewVBox(
 		widget.NewLabel("Добро пожаловать, Клиент!"),
 		browseCarsButton,
 		purchaseHistoryButton,
+		logoutButton,
 	))
 
 	clientWindow.Show()
 }
 
-func openClientLogin(database *sql.DB, app fyne.App) { //функция входа для клиента
+func openClientLogin(store *db.Store, authenticator auth.Authenticator, app fyne.App) { //функция входа для клиента
 	loginWindow := app.NewWindow("Клиент: Вход")
 	loginWindow.Resize(fyne.NewSize(400, 300))
 
@@ -200,22 +138,21 @@ func openClientLogin(database *sql.DB, app fyne.App) { //функция вход
 			dialog.ShowError(fmt.Errorf("все поля должны быть заполнены"), loginWindow)
 		}
 
-		var id int
-		err := database.QueryRow("SELECT ID_Client FROM Client WHERE Login = ? AND Password = ?", login, password).Scan(&id)
+		client, err := authenticator.LoginClient(login, password)
 		if err != nil {
-			dialog.ShowError(fmt.Errorf("неверный логин или пароль"), loginWindow)
+			dialog.ShowError(err, loginWindow)
 			return
 		}
 
-		currentClientID = id
+		session := auth.NewSession(auth.RoleClient, client.ID, client.Login)
 
 		dialog.ShowInformation("Успешный вход", "Добро пожаловать!", loginWindow)
-		StartClientGUI(database, app) // Запуск GUI клиента
+		StartClientGUI(store, authenticator, app, session) // Запуск GUI клиента
 		loginWindow.Close()
 	})
 
 	registerButton := widget.NewButton("Зарегистрироваться", func() {
-		openClientRegister(database, app)
+		openClientRegister(authenticator, app)
 
 	})
 
@@ -230,7 +167,7 @@ func openClientLogin(database *sql.DB, app fyne.App) { //функция вход
 	loginWindow.Show()
 }
 
-func openClientRegister(database *sql.DB, app fyne.App) { //Фукнция регистрации для клиента
+func openClientRegister(authenticator auth.Authenticator, app fyne.App) { //Фукнция регистрации для клиента
 	registerWindow := app.NewWindow("Клиент: Регистрация")
 	registerWindow.Resize(fyne.NewSize(400, 400))
 
@@ -254,11 +191,8 @@ func openClientRegister(database *sql.DB, app fyne.App) { //Фукнция ре
 			return
 		}
 
-		_, err := database.Exec(
-			"INSERT INTO Client (Name, LastName, Phone, Login, Password) VALUES (?, ?, ?, ?, ?)",
-			name, lastName, phone, login, password,
-		)
-		if err != nil {
+		client := db.Client{Name: name, LastName: lastName, Phone: phone, Login: login, Password: password}
+		if err := authenticator.RegisterClient(&client); err != nil {
 			dialog.ShowError(fmt.Errorf("ошибка при регистрации"), registerWindow)
 			return
 		}
@@ -0,0 +1,102 @@
+package gui
+
+import (
+	"car-sales-system/internal/analytics"
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// dashboardPeriods — число периодов на графике выручки для каждого вида периода
+var dashboardPeriods = map[analytics.Granularity]int{
+	analytics.Day:   14,
+	analytics.Week:  12,
+	analytics.Month: 12,
+}
+
+// openDashboardWindow показывает панель продаж: выручку по времени,
+// доли марок в выручке и количество автомобилей по состояниям
+func openDashboardWindow(ui *sessionUI) {
+	store := ui.store
+	dashboardWindow := ui.newWindow("Панель продаж")
+	dashboardWindow.Resize(fyne.NewSize(900, 650))
+
+	money := formatMoneyRounded
+	count := func(v float64) string { return fmt.Sprintf("%.0f", v) }
+
+	revenueChart := NewChart(LineChart, "Выручка", nil)
+	revenueChart.SetValueFormat(money)
+	brandChart := NewChart(PieChart, "Доля марок в выручке", nil)
+	inventoryChart := NewChart(BarChart, "Автомобили по состояниям", nil)
+	inventoryChart.SetValueFormat(count)
+
+	granularity := analytics.Month
+	titles := []string{analytics.Day.Title(), analytics.Week.Title(), analytics.Month.Title()}
+	byTitle := map[string]analytics.Granularity{}
+	for g := range dashboardPeriods {
+		byTitle[g.Title()] = g
+	}
+
+	reload := func() {
+		sales, err := store.Checks.ListSales(true)
+		if err != nil {
+			dialog.ShowError(err, dashboardWindow)
+			return
+		}
+		series, err := analytics.RevenueSeries(sales, granularity, dashboardPeriods[granularity], time.Now())
+		if err != nil {
+			dialog.ShowError(err, dashboardWindow)
+			return
+		}
+		revenuePoints := make([]ChartPoint, len(series))
+		for i, p := range series {
+			revenuePoints[i] = ChartPoint{Label: p.Label(), Value: p.Revenue}
+		}
+		revenueChart.SetPoints(revenuePoints)
+
+		brands := analytics.GroupSales(sales, analytics.SalesQuery{GroupBy: analytics.ByBrand, SortBy: analytics.SortRevenue})
+		brandPoints := make([]ChartPoint, len(brands))
+		for i, b := range brands {
+			brandPoints[i] = ChartPoint{Label: b.Group, Value: b.Revenue}
+		}
+		brandChart.SetPoints(brandPoints)
+
+		cars, err := store.Cars.List()
+		if err != nil {
+			dialog.ShowError(err, dashboardWindow)
+			return
+		}
+		var inventoryPoints []ChartPoint
+		for _, c := range analytics.InventoryByStatus(cars) {
+			inventoryPoints = append(inventoryPoints, ChartPoint{Label: c.Status.Title(), Value: float64(c.Count)})
+		}
+		inventoryChart.SetPoints(inventoryPoints)
+	}
+
+	granularitySelect := widget.NewSelect(titles, func(title string) {
+		granularity = byTitle[title]
+		reload()
+	})
+
+	dashboardWindow.SetContent(container.NewBorder(
+		container.NewHBox(
+			widget.NewLabel("Выручка по периодам:"),
+			granularitySelect,
+			ui.button("Обновить", reload),
+		),
+		widget.NewButton("Закрыть", func() {
+			dashboardWindow.Close()
+		}),
+		nil, nil,
+		container.NewGridWithRows(2,
+			revenueChart,
+			container.NewGridWithColumns(2, brandChart, inventoryChart),
+		),
+	))
+	granularitySelect.SetSelected(granularity.Title())
+	dashboardWindow.Show()
+}
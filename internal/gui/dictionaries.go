@@ -0,0 +1,224 @@
+package gui
+
+import (
+	"car-sales-system/internal/db"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// allBrands — пункт фильтра моделей без ограничения маркой
+const allBrands = "Все марки"
+
+// openDictionariesWindow показывает справочники марок, моделей и цветов.
+// Значения можно добавлять, переименовывать, объединять с другими и удалять неиспользуемые.
+func openDictionariesWindow(ui *sessionUI) {
+	dictWindow := ui.newWindow("Справочники")
+	dictWindow.Resize(fyne.NewSize(550, 600))
+
+	// Объединение марок меняет и модели, поэтому после любого изменения обновляются все вкладки
+	var reloads []func()
+	reloadAll := func() {
+		for _, reload := range reloads {
+			reload()
+		}
+	}
+
+	tabs := container.NewAppTabs()
+	for _, kind := range db.DictionaryKinds {
+		content, reload := newDictionaryTab(ui, dictWindow, kind, reloadAll)
+		reloads = append(reloads, reload)
+		tabs.Append(container.NewTabItem(kind.Title(), content))
+	}
+	reloadAll()
+
+	dictWindow.SetContent(container.NewBorder(
+		nil,
+		widget.NewButton("Закрыть", func() { dictWindow.Close() }),
+		nil, nil,
+		tabs,
+	))
+	dictWindow.Show()
+}
+
+// newDictionaryTab создаёт вкладку одного справочника и функцию её обновления.
+// Для моделей над списком выбирается марка: она ограничивает список и нужна для добавления модели.
+func newDictionaryTab(ui *sessionUI, parent fyne.Window, kind db.DictionaryKind, changed func()) (fyne.CanvasObject, func()) {
+	store := ui.store
+	var entries []db.DictionaryEntry
+	selected := -1
+
+	list := widget.NewList(
+		func() int { return len(entries) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(i widget.ListItemID, obj fyne.CanvasObject) {
+			obj.(*widget.Label).SetText(dictionaryEntryTitle(entries[i]))
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) { selected = id }
+	list.OnUnselected = func(widget.ListItemID) { selected = -1 }
+
+	brandIDs := make(map[string]int)
+	brandSelect := widget.NewSelect(nil, nil)
+
+	reload := func() {
+		brandID := brandIDs[brandSelect.Selected]
+		if kind == db.DictModels {
+			brands, err := store.Dictionaries.List(db.DictBrands, 0)
+			if err != nil {
+				dialog.ShowError(err, parent)
+				return
+			}
+			options := []string{allBrands}
+			clear(brandIDs)
+			for _, b := range brands {
+				options = append(options, b.Name)
+				brandIDs[b.Name] = b.ID
+			}
+			brandSelect.Options = options
+			if _, ok := brandIDs[brandSelect.Selected]; !ok {
+				brandSelect.Selected, brandID = allBrands, 0
+			}
+			brandSelect.Refresh()
+		}
+
+		var err error
+		entries, err = store.Dictionaries.List(kind, brandID)
+		if err != nil {
+			dialog.ShowError(err, parent)
+			return
+		}
+		list.UnselectAll()
+		selected = -1
+		list.Refresh()
+	}
+	brandSelect.OnChanged = func(string) {
+		ui.session.Touch()
+		reload()
+	}
+
+	// current возвращает выбранное значение или показывает ошибку
+	current := func() (db.DictionaryEntry, bool) {
+		if selected < 0 || selected >= len(entries) {
+			dialog.ShowError(fmt.Errorf("значение не выбрано"), parent)
+			return db.DictionaryEntry{}, false
+		}
+		return entries[selected], true
+	}
+	// apply показывает ошибку изменения или обновляет справочники
+	apply := func(err error) {
+		if err != nil {
+			dialog.ShowError(err, parent)
+			return
+		}
+		changed()
+	}
+
+	addButton := ui.button("Добавить", func() {
+		entry := db.DictionaryEntry{Kind: kind}
+		if kind == db.DictModels {
+			entry.BrandID = brandIDs[brandSelect.Selected]
+			if entry.BrandID == 0 {
+				dialog.ShowError(fmt.Errorf("выберите марку, к которой относится модель"), parent)
+				return
+			}
+		}
+		askName("Добавить значение", "", parent, func(name string) {
+			entry.Name = name
+			apply(store.Dictionaries.Add(&entry))
+		})
+	})
+
+	renameButton := ui.button("Переименовать", func() {
+		e, ok := current()
+		if !ok {
+			return
+		}
+		askName("Переименовать", e.Name, parent, func(name string) {
+			apply(store.Dictionaries.Rename(kind, e.ID, name))
+		})
+	})
+
+	mergeButton := ui.button("Объединить с…", func() {
+		e, ok := current()
+		if !ok {
+			return
+		}
+		// Модели объединяются только в пределах одной марки
+		targets := make(map[string]int)
+		var options []string
+		for _, other := range entries {
+			if other.ID == e.ID || (kind == db.DictModels && other.BrandID != e.BrandID) {
+				continue
+			}
+			title := dictionaryEntryTitle(other)
+			targets[title] = other.ID
+			options = append(options, title)
+		}
+		if len(options) == 0 {
+			dialog.ShowError(fmt.Errorf("не с чем объединить значение «%s»", e.Name), parent)
+			return
+		}
+		targetSelect := widget.NewSelect(options, nil)
+		dialog.ShowForm(fmt.Sprintf("Объединить «%s»", e.Name), "Объединить", "Отмена",
+			[]*widget.FormItem{widget.NewFormItem("Оставить значение", targetSelect)},
+			func(confirmed bool) {
+				if !confirmed || targetSelect.Selected == "" {
+					return
+				}
+				ui.session.Touch()
+				apply(store.Dictionaries.Merge(kind, e.ID, targets[targetSelect.Selected]))
+			}, parent)
+	})
+
+	deleteButton := ui.button("Удалить", func() {
+		e, ok := current()
+		if !ok {
+			return
+		}
+		dialog.ShowConfirm("Удаление", fmt.Sprintf("Удалить «%s» из справочника?", e.Name), func(confirmed bool) {
+			if confirmed {
+				apply(store.Dictionaries.Delete(kind, e.ID))
+			}
+		}, parent)
+	})
+
+	hint := widget.NewLabel("При объединении автомобили получают оставленное значение, а прежнее написание распознаётся при вводе.")
+	hint.Wrapping = fyne.TextWrapWord
+	top := container.NewVBox(hint)
+	if kind == db.DictModels {
+		top.Add(brandSelect)
+	}
+
+	return container.NewBorder(
+		top,
+		container.NewGridWithColumns(4, addButton, renameButton, mergeButton, deleteButton),
+		nil, nil,
+		list,
+	), reload
+}
+
+// dictionaryEntryTitle выводит значение справочника с числом автомобилей
+func dictionaryEntryTitle(e db.DictionaryEntry) string {
+	name := e.Name
+	if e.Kind == db.DictModels {
+		name = e.BrandName + " " + e.Name
+	}
+	return fmt.Sprintf("%s — автомобилей: %d", name, e.Cars)
+}
+
+// askName запрашивает название значения справочника
+func askName(title, initial string, parent fyne.Window, done func(name string)) {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetText(initial)
+	dialog.ShowForm(title, "Сохранить", "Отмена",
+		[]*widget.FormItem{widget.NewFormItem("Название", nameEntry)},
+		func(confirmed bool) {
+			if confirmed {
+				done(nameEntry.Text)
+			}
+		}, parent)
+}
@@ -0,0 +1,212 @@
+package gui
+
+import (
+	"car-sales-system/internal/db"
+	"car-sales-system/internal/photo"
+	"fmt"
+	"io"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// openEditCarWindow позволяет исправить данные выбранного автомобиля.
+// Каждое изменённое поле записывается в историю изменений автомобиля;
+// на отдельных вкладках показывается, как менялась цена, и загружаются фотографии.
+func openEditCarWindow(ui *sessionUI) {
+	store := ui.store
+	editWindow := ui.newWindow("Редактировать автомобиль")
+	editWindow.Resize(fyne.NewSize(600, 850))
+
+	cars, err := store.Cars.List()
+	if err != nil {
+		dialog.ShowError(err, editWindow)
+		return
+	}
+
+	var carList []string
+	carMap := make(map[string]db.Car)
+	for _, car := range cars {
+		label := fmt.Sprintf("%s %s (ID: %d)", car.Brand, car.Model, car.ID)
+		carList = append(carList, label)
+		carMap[label] = car
+	}
+
+	form := newCarForm(editWindow, store.Dictionaries)
+	var history []string
+	historyList := widget.NewList(
+		func() int { return len(history) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(i widget.ListItemID, obj fyne.CanvasObject) {
+			obj.(*widget.Label).SetText(history[i])
+		},
+	)
+
+	priceChart := NewChart(LineChart, "Цена", nil)
+	priceChart.SetValueFormat(func(v float64) string { return formatMoneyRounded(v) })
+	var prices []string
+	priceList := widget.NewList(
+		func() int { return len(prices) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(i widget.ListItemID, obj fyne.CanvasObject) {
+			obj.(*widget.Label).SetText(prices[i])
+		},
+	)
+
+	var selected db.Car
+	photos := newGallery(store, 0, editWindow)
+	uploadButton := ui.button("Загрузить фотографию", func() {
+		if selected.ID == 0 {
+			dialog.ShowError(fmt.Errorf("автомобиль не выбран"), editWindow)
+			return
+		}
+		uploadPhoto(ui, editWindow, selected.ID, photos.reload)
+	})
+	openPhotoButton := ui.button("Открыть", func() {
+		if p, ok := photos.selectedPhoto(); ok {
+			showPhotoWindow(ui, editWindow, p.ID)
+		}
+	})
+	removePhotoButton := ui.button("Удалить выбранную", func() {
+		p, ok := photos.selectedPhoto()
+		if !ok {
+			dialog.ShowError(fmt.Errorf("фотография не выбрана"), editWindow)
+			return
+		}
+		dialog.ShowConfirm("Удаление фотографии", "Удалить выбранную фотографию?", func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			if err := store.Photos.Delete(p.ID); err != nil {
+				dialog.ShowError(err, editWindow)
+			}
+			photos.reload()
+		}, editWindow)
+	})
+
+	refreshPrices := func() {
+		history, err := store.Cars.PriceHistory(selected.ID)
+		if err != nil {
+			dialog.ShowError(err, editWindow)
+			return
+		}
+		points := make([]ChartPoint, len(history))
+		prices = prices[:0]
+		for i, p := range history {
+			points[i] = ChartPoint{Label: p.ChangedAt.Local().Format("02.01"), Value: p.Price}
+			line := fmt.Sprintf("%s: %s", p.ChangedAt.Local().Format("02.01.2006 15:04"), formatMoney(p.Price))
+			if i > 0 {
+				line += " (" + formatMoneyChange(p.Price-history[i-1].Price) + ")"
+			}
+			prices = append(prices, line)
+		}
+		priceChart.SetPoints(points)
+		priceList.Refresh()
+	}
+
+	refreshHistory := func() {
+		changes, err := store.Cars.ChangeHistory(selected.ID)
+		if err != nil {
+			dialog.ShowError(err, editWindow)
+			return
+		}
+		history = history[:0]
+		for _, c := range changes {
+			history = append(history, fmt.Sprintf("%s: %s «%s» → «%s»",
+				c.ChangedAt.Local().Format("02.01.2006 15:04"), db.CarFieldTitle(c.Field), c.OldValue, c.NewValue))
+		}
+		historyList.Refresh()
+		refreshPrices()
+	}
+
+	carSelect := widget.NewSelect(carList, func(label string) {
+		selected = carMap[label]
+		form.fill(selected)
+		photos.setCar(selected.ID)
+		refreshHistory()
+	})
+	carSelect.PlaceHolder = "Выберите автомобиль"
+
+	saveButton := ui.button("Сохранить", func() {
+		if selected.ID == 0 {
+			dialog.ShowError(fmt.Errorf("автомобиль не выбран"), editWindow)
+			return
+		}
+		car, err := form.car()
+		if err != nil {
+			dialog.ShowError(err, editWindow)
+			return
+		}
+		car.ID = selected.ID
+
+		changes, err := store.Cars.Update(car, ui.session.UserID)
+		if err != nil {
+			dialog.ShowError(err, editWindow)
+			return
+		}
+		if len(changes) == 0 {
+			dialog.ShowInformation("Редактирование", "Данные не изменились", editWindow)
+			return
+		}
+		car.Status = selected.Status
+		selected = car
+		refreshHistory()
+		dialog.ShowInformation("Успех", fmt.Sprintf("Изменено полей: %d", len(changes)), editWindow)
+	})
+
+	editWindow.SetContent(container.NewBorder(
+		container.NewVBox(
+			widget.NewLabel("Выберите автомобиль:"),
+			carSelect,
+			form.content(),
+			saveButton,
+		),
+		widget.NewButton("Закрыть", func() { editWindow.Close() }),
+		nil, nil,
+		container.NewAppTabs(
+			container.NewTabItem("История изменений", historyList),
+			container.NewTabItem("История цены", container.NewGridWithRows(2, priceChart, priceList)),
+			container.NewTabItem("Фотографии", container.NewVBox(
+				photos.content(),
+				container.NewHBox(uploadButton, openPhotoButton, removePhotoButton),
+			)),
+		),
+	))
+	editWindow.Show()
+}
+
+// uploadPhoto предлагает выбрать файл изображения, строит миниатюру и добавляет фотографию автомобилю
+func uploadPhoto(ui *sessionUI, parent fyne.Window, carID int, done func()) {
+	fileDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, parent)
+			return
+		}
+		if reader == nil {
+			return
+		}
+		defer reader.Close()
+
+		data, err := io.ReadAll(io.LimitReader(reader, photo.MaxSize+1))
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("ошибка чтения файла: %w", err), parent)
+			return
+		}
+		prepared, err := photo.Prepare(data)
+		if err != nil {
+			dialog.ShowError(err, parent)
+			return
+		}
+		p := db.CarPhoto{CarID: carID, ContentType: prepared.ContentType, Data: prepared.Data, Thumbnail: prepared.Thumbnail}
+		if err := ui.store.Photos.Add(&p); err != nil {
+			dialog.ShowError(err, parent)
+			return
+		}
+		done()
+	}, parent)
+	fileDialog.SetFilter(storage.NewExtensionFileFilter([]string{".jpg", ".jpeg", ".png", ".gif", ".webp"}))
+	fileDialog.Show()
+}
@@ -0,0 +1,168 @@
+package gui
+
+import (
+	"car-sales-system/internal/db"
+	"car-sales-system/internal/export"
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// allStatuses — пункт фильтра выгрузки без ограничения состоянием
+const allStatuses = "Все статусы"
+
+// openExportWindow выгружает автомобили, клиентов, администраторов или чеки в файл CSV, JSON или XLSX.
+// Автомобили можно ограничить статусом, чеки — статусом и периодом оформления.
+func openExportWindow(ui *sessionUI) {
+	exportWindow := ui.newWindow("Выгрузка данных")
+	exportWindow.Resize(fyne.NewSize(450, 350))
+
+	datasets := make(map[string]export.Dataset)
+	var datasetTitles []string
+	for _, d := range export.Datasets {
+		datasets[d.Title()] = d
+		datasetTitles = append(datasetTitles, d.Title())
+	}
+	var formatTitles []string
+	for _, f := range export.Formats {
+		formatTitles = append(formatTitles, strings.ToUpper(string(f)))
+	}
+	formatSelect := widget.NewSelect(formatTitles, nil)
+	formatSelect.SetSelected(formatTitles[0])
+
+	statusSelect := widget.NewSelect(nil, nil)
+	fromEntry := widget.NewEntry()
+	fromEntry.SetPlaceHolder("С (ДД.ММ.ГГГГ)")
+	toEntry := widget.NewEntry()
+	toEntry.SetPlaceHolder("По (ДД.ММ.ГГГГ)")
+	dateRange := container.NewGridWithColumns(2, fromEntry, toEntry)
+
+	// Фильтры зависят от таблицы: статус есть у автомобилей и чеков, период — только у чеков
+	carStatuses := make(map[string]db.CarStatus)
+	checkStatuses := make(map[string]db.CheckStatus)
+	datasetSelect := widget.NewSelect(datasetTitles, func(title string) {
+		options := []string{allStatuses}
+		switch datasets[title] {
+		case export.Cars:
+			for _, s := range db.CarStatuses {
+				carStatuses[s.Title()] = s
+				options = append(options, s.Title())
+			}
+		case export.Checks:
+			for _, s := range db.CheckStatuses {
+				checkStatuses[s.Title()] = s
+				options = append(options, s.Title())
+			}
+		}
+		statusSelect.Options = options
+		statusSelect.SetSelected(allStatuses)
+		if len(options) > 1 {
+			statusSelect.Show()
+		} else {
+			statusSelect.Hide()
+		}
+		if datasets[title] == export.Checks {
+			dateRange.Show()
+		} else {
+			dateRange.Hide()
+		}
+	})
+	datasetSelect.SetSelected(datasetTitles[0])
+
+	// filter собирает условия выгрузки из выбранных фильтров
+	filter := func(dataset export.Dataset) (export.Filter, error) {
+		var f export.Filter
+		switch dataset {
+		case export.Cars:
+			if s, ok := carStatuses[statusSelect.Selected]; ok {
+				f.CarStatuses = []db.CarStatus{s}
+			}
+		case export.Checks:
+			if s, ok := checkStatuses[statusSelect.Selected]; ok {
+				f.CheckStatuses = []db.CheckStatus{s}
+			}
+			first, err := parseOptionalDate(fromEntry.Text, "начала")
+			if err != nil {
+				return f, err
+			}
+			last, err := parseOptionalDate(toEntry.Text, "конца")
+			if err != nil {
+				return f, err
+			}
+			if f.From, f.To, err = export.DayRange(first, last); err != nil {
+				return f, err
+			}
+		}
+		return f, nil
+	}
+
+	saveButton := ui.button("Сохранить в файл…", func() {
+		dataset := datasets[datasetSelect.Selected]
+		format, err := export.ParseFormat(formatSelect.Selected)
+		if err != nil {
+			dialog.ShowError(err, exportWindow)
+			return
+		}
+		f, err := filter(dataset)
+		if err != nil {
+			dialog.ShowError(err, exportWindow)
+			return
+		}
+		table, err := export.Build(ui.store, dataset, f)
+		if err != nil {
+			dialog.ShowError(err, exportWindow)
+			return
+		}
+
+		saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, exportWindow)
+				return
+			}
+			if writer == nil {
+				return
+			}
+			err = export.Write(writer, format, table)
+			if closeErr := writer.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("ошибка записи файла: %w", err), exportWindow)
+				return
+			}
+			dialog.ShowInformation("Успех", fmt.Sprintf("Выгружено записей: %d", len(table.Rows)), exportWindow)
+		}, exportWindow)
+		saveDialog.SetFileName(fmt.Sprintf("%s-%s.%s", dataset, time.Now().Format("2006-01-02"), format))
+		saveDialog.Show()
+	})
+
+	exportWindow.SetContent(container.NewVBox(
+		widget.NewLabel("Что выгрузить:"),
+		datasetSelect,
+		statusSelect,
+		dateRange,
+		widget.NewLabel("Формат файла:"),
+		formatSelect,
+		widget.NewLabel("Пароли клиентов и администраторов не выгружаются."),
+		container.NewHBox(saveButton, widget.NewButton("Закрыть", func() { exportWindow.Close() })),
+	))
+	exportWindow.Show()
+}
+
+// parseOptionalDate разбирает дату в формате dateLayout; пустая строка — нулевая дата
+func parseOptionalDate(text, bound string) (time.Time, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.ParseInLocation(dateLayout, text, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("неверная дата %s периода, используйте формат ДД.ММ.ГГГГ", bound)
+	}
+	return t, nil
+}
@@ -0,0 +1,160 @@
+package gui
+
+import (
+	"car-sales-system/internal/db"
+	"fmt"
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// thumbnailSize — размер миниатюры в галерее и каталоге
+var thumbnailSize = fyne.NewSize(120, 90)
+
+// photoImage создаёт изображение из миниатюры или исходного файла фотографии
+func photoImage(name string, data []byte) *canvas.Image {
+	img := canvas.NewImageFromResource(fyne.NewStaticResource(name, data))
+	img.FillMode = canvas.ImageFillContain
+	return img
+}
+
+// photoThumb — миниатюра фотографии, реагирующая на нажатие
+type photoThumb struct {
+	widget.BaseWidget
+
+	photo    db.CarPhoto
+	frame    *canvas.Rectangle
+	onTapped func()
+}
+
+func newPhotoThumb(photo db.CarPhoto, onTapped func()) *photoThumb {
+	t := &photoThumb{photo: photo, onTapped: onTapped}
+	t.frame = canvas.NewRectangle(color.Transparent)
+	t.frame.StrokeWidth = 2
+	t.ExtendBaseWidget(t)
+	return t
+}
+
+func (t *photoThumb) CreateRenderer() fyne.WidgetRenderer {
+	img := photoImage(fmt.Sprintf("thumb-%d.jpg", t.photo.ID), t.photo.Thumbnail)
+	img.SetMinSize(thumbnailSize)
+	return widget.NewSimpleRenderer(container.NewStack(img, t.frame))
+}
+
+func (t *photoThumb) Tapped(*fyne.PointEvent) {
+	if t.onTapped != nil {
+		t.onTapped()
+	}
+}
+
+// setSelected выделяет миниатюру рамкой
+func (t *photoThumb) setSelected(selected bool) {
+	if selected {
+		t.frame.StrokeColor = theme.Color(theme.ColorNamePrimary)
+	} else {
+		t.frame.StrokeColor = color.Transparent
+	}
+	t.frame.Refresh()
+}
+
+// gallery — лента миниатюр фотографий автомобиля
+type gallery struct {
+	store  *db.Store
+	carID  int
+	parent fyne.Window
+
+	photos   []db.CarPhoto
+	thumbs   []*photoThumb
+	selected int
+	box      *fyne.Container
+	empty    *widget.Label
+	// onTapped вызывается при нажатии на миниатюру после её выделения
+	onTapped func(photo db.CarPhoto)
+}
+
+func newGallery(store *db.Store, carID int, parent fyne.Window) *gallery {
+	g := &gallery{
+		store:    store,
+		carID:    carID,
+		parent:   parent,
+		selected: -1,
+		box:      container.NewHBox(),
+		empty:    widget.NewLabel("Фотографии автомобиля пока не загружены"),
+	}
+	g.reload()
+	return g
+}
+
+// content возвращает прокручиваемую ленту миниатюр
+func (g *gallery) content() fyne.CanvasObject {
+	scroll := container.NewHScroll(g.box)
+	scroll.SetMinSize(fyne.NewSize(thumbnailSize.Width, thumbnailSize.Height+theme.Padding()*2))
+	return container.NewStack(scroll, container.NewCenter(g.empty))
+}
+
+// setCar показывает фотографии другого автомобиля
+func (g *gallery) setCar(carID int) {
+	g.carID = carID
+	g.reload()
+}
+
+// reload заново читает фотографии автомобиля
+func (g *gallery) reload() {
+	photos, err := g.store.Photos.List(g.carID)
+	if err != nil {
+		dialog.ShowError(err, g.parent)
+		return
+	}
+	g.photos = photos
+	g.selected = -1
+	g.thumbs = g.thumbs[:0]
+	g.box.RemoveAll()
+	for i, p := range photos {
+		index := i
+		thumb := newPhotoThumb(p, func() { g.tap(index) })
+		g.thumbs = append(g.thumbs, thumb)
+		g.box.Add(thumb)
+	}
+	if len(photos) == 0 {
+		g.empty.Show()
+	} else {
+		g.empty.Hide()
+	}
+	g.box.Refresh()
+}
+
+func (g *gallery) tap(index int) {
+	for i, t := range g.thumbs {
+		t.setSelected(i == index)
+	}
+	g.selected = index
+	if g.onTapped != nil {
+		g.onTapped(g.photos[index])
+	}
+}
+
+// selectedPhoto возвращает выделенную фотографию
+func (g *gallery) selectedPhoto() (db.CarPhoto, bool) {
+	if g.selected < 0 || g.selected >= len(g.photos) {
+		return db.CarPhoto{}, false
+	}
+	return g.photos[g.selected], true
+}
+
+// showPhotoWindow открывает фотографию в исходном размере
+func showPhotoWindow(ui *sessionUI, parent fyne.Window, photoID int) {
+	photo, err := ui.store.Photos.Get(photoID)
+	if err != nil {
+		dialog.ShowError(err, parent)
+		return
+	}
+	photoWindow := ui.newWindow(fmt.Sprintf("Фотография %d", photo.Position))
+	photoWindow.Resize(fyne.NewSize(800, 600))
+	photoWindow.SetContent(photoImage(fmt.Sprintf("photo-%d", photo.ID), photo.Data))
+	photoWindow.Show()
+}
@@ -0,0 +1,122 @@
+package gui
+
+import (
+	"car-sales-system/internal/importer"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// openImportWindow загружает автомобили из файла CSV или JSON.
+// Сначала показывается пробный импорт: какие записи приняты, а какие отклонены и почему;
+// принятые записи добавляются одной транзакцией только после подтверждения.
+func openImportWindow(ui *sessionUI) {
+	importWindow := ui.newWindow("Импорт автомобилей")
+	importWindow.Resize(fyne.NewSize(650, 550))
+
+	var report *importer.Report
+	summary := widget.NewLabel("Выберите файл CSV или JSON. Первая строка CSV — названия столбцов: " +
+		"марка, модель, год выпуска, цвет, цена и, при желании, VIN и характеристики.")
+	summary.Wrapping = fyne.TextWrapWord
+
+	rows := widget.NewList(
+		func() int {
+			if report == nil {
+				return 0
+			}
+			return len(report.Rows)
+		},
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(i widget.ListItemID, obj fyne.CanvasObject) {
+			obj.(*widget.Label).SetText(importRowTitle(report.Rows[i]))
+		},
+	)
+
+	var importButton *widget.Button
+	show := func(name string, r *importer.Report) {
+		report = r
+		text := fmt.Sprintf("Файл %s: принято записей — %d, отклонено — %d.",
+			name, len(r.Accepted()), len(r.Rejected()))
+		if len(r.Ignored) > 0 {
+			text += fmt.Sprintf(" Не распознаны и пропущены столбцы: %s.", strings.Join(r.Ignored, ", "))
+		}
+		summary.SetText(text)
+		rows.Refresh()
+		if len(r.Accepted()) > 0 {
+			importButton.Enable()
+		} else {
+			importButton.Disable()
+		}
+	}
+
+	chooseButton := ui.button("Выбрать файл…", func() {
+		fileDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, importWindow)
+				return
+			}
+			if reader == nil {
+				return
+			}
+			defer reader.Close()
+
+			name := reader.URI().Name()
+			format, err := importer.FormatFromPath(name)
+			if err != nil {
+				dialog.ShowError(err, importWindow)
+				return
+			}
+			r, err := importer.Prepare(reader, format, ui.store.Cars)
+			if err != nil {
+				dialog.ShowError(err, importWindow)
+				return
+			}
+			show(name, r)
+		}, importWindow)
+		fileDialog.SetFilter(storage.NewExtensionFileFilter([]string{".csv", ".json"}))
+		fileDialog.Show()
+	})
+
+	importButton = ui.button("Импортировать", func() {
+		accepted, rejected := len(report.Accepted()), len(report.Rejected())
+		message := fmt.Sprintf("Добавить в базу автомобилей: %d?", accepted)
+		if rejected > 0 {
+			message += fmt.Sprintf("\nОтклонённые записи (%d) будут пропущены.", rejected)
+		}
+		dialog.ShowConfirm("Импорт", message, func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			n, err := importer.Import(report, ui.store.Cars)
+			if err != nil {
+				dialog.ShowError(err, importWindow)
+				return
+			}
+			// Повторный импорт того же отчёта добавил бы автомобили дважды
+			importButton.Disable()
+			dialog.ShowInformation("Успех", fmt.Sprintf("Добавлено автомобилей: %d", n), importWindow)
+		}, importWindow)
+	})
+	importButton.Disable()
+
+	importWindow.SetContent(container.NewBorder(
+		container.NewVBox(chooseButton, summary),
+		container.NewHBox(importButton, widget.NewButton("Закрыть", func() { importWindow.Close() })),
+		nil, nil,
+		rows,
+	))
+	importWindow.Show()
+}
+
+// importRowTitle описывает запись пробного импорта и результат её проверки
+func importRowTitle(row importer.Row) string {
+	if row.Err != nil {
+		return fmt.Sprintf("%d. %s — отклонена: %v", row.Number, row.Title(), row.Err)
+	}
+	return fmt.Sprintf("%d. %s — принята", row.Number, row.Title())
+}
@@ -1,7 +1,8 @@
 package gui
 
 import (
-	"database/sql"
+	"car-sales-system/internal/auth"
+	"car-sales-system/internal/db"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
@@ -9,19 +10,27 @@ import (
 	"fyne.io/fyne/v2/widget"
 )
 
-// StartMainGUI запускает главное окно выбора роли
-func StartMainGUI(database *sql.DB) {
+// StartMainGUI запускает главное окно выбора роли с заданными настройками интерфейса.
+// authenticator проверяет пароли при входе: локально по store или на сервере carsales-server.
+func StartMainGUI(store *db.Store, authenticator auth.Authenticator, settings Settings) {
+	applySettings(settings)
 	application := app.New()
+	showMainWindow(store, authenticator, application)
+	application.Run()
+}
+
+// showMainWindow открывает окно выбора роли; к нему же возвращает выход из сессии
+func showMainWindow(store *db.Store, authenticator auth.Authenticator, application fyne.App) {
 	mainWindow := application.NewWindow("Car Sales System")
 	mainWindow.Resize(fyne.NewSize(400, 200))
 
 	// Кнопки для выбора роли
 	clientButton := widget.NewButton("Войти как Клиент", func() {
-		openClientLogin(database, application)
+		openClientLogin(store, authenticator, application)
 		mainWindow.Close()
 	})
 	adminButton := widget.NewButton("Войти как Администратор", func() {
-		openAdminLogin(database, application)
+		openAdminLogin(store, authenticator, application)
 		mainWindow.Close()
 	})
 
@@ -32,5 +41,5 @@ func StartMainGUI(database *sql.DB) {
 		adminButton,
 	))
 
-	mainWindow.ShowAndRun()
+	mainWindow.Show()
 }
@@ -0,0 +1,80 @@
+package gui
+
+import (
+	"car-sales-system/internal/db"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// openApprovalQueueWindow показывает заказы, ожидающие решения, и позволяет
+// администратору сессии подтвердить или отклонить выбранный заказ
+func openApprovalQueueWindow(ui *sessionUI) {
+	store := ui.store
+	queueWindow := ui.newWindow("Подтверждение заказов")
+	queueWindow.Resize(fyne.NewSize(500, 400))
+
+	var orders []db.PendingOrder
+	selected := -1
+
+	orderList := widget.NewList(
+		func() int { return len(orders) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(i widget.ListItemID, obj fyne.CanvasObject) {
+			o := orders[i]
+			obj.(*widget.Label).SetText(fmt.Sprintf("№%d: %s %s — %s %s (%d), %s",
+				o.CheckID, o.ClientName, o.ClientLastName, o.Brand, o.Model, o.Year, formatMoney(o.Price)))
+		},
+	)
+	orderList.OnSelected = func(id widget.ListItemID) { selected = id }
+
+	emptyLabel := widget.NewLabel("")
+	reload := func() {
+		var err error
+		orders, err = store.Checks.ListPending()
+		if err != nil {
+			dialog.ShowError(err, queueWindow)
+			return
+		}
+		selected = -1
+		orderList.UnselectAll()
+		orderList.Refresh()
+		if len(orders) == 0 {
+			emptyLabel.SetText("Нет заказов, ожидающих подтверждения")
+		} else {
+			emptyLabel.SetText(fmt.Sprintf("Заказов в очереди: %d", len(orders)))
+		}
+	}
+
+	// decide применяет решение к выбранному заказу от имени администратора сессии
+	decide := func(action func(checkID, adminID int) (*db.Check, error), done string) func() {
+		return func() {
+			if selected < 0 || selected >= len(orders) {
+				dialog.ShowError(fmt.Errorf("заказ не выбран"), queueWindow)
+				return
+			}
+			if _, err := action(orders[selected].CheckID, ui.session.UserID); err != nil {
+				dialog.ShowError(err, queueWindow)
+				reload()
+				return
+			}
+			dialog.ShowInformation("Успех", done, queueWindow)
+			reload()
+		}
+	}
+
+	approveButton := ui.button("Подтвердить", decide(store.Purchases.Approve, "Продажа подтверждена"))
+	rejectButton := ui.button("Отклонить", decide(store.Purchases.Reject, "Заказ отклонён, автомобиль возвращён в продажу"))
+
+	queueWindow.SetContent(container.NewBorder(
+		emptyLabel,
+		container.NewHBox(approveButton, rejectButton, ui.button("Обновить", reload), widget.NewButton("Закрыть", func() { queueWindow.Close() })),
+		nil, nil,
+		orderList,
+	))
+	reload()
+	queueWindow.Show()
+}
@@ -0,0 +1,232 @@
+package gui
+
+import (
+	"car-sales-system/internal/auth"
+	"car-sales-system/internal/db"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+// idleTimeout — время простоя, после которого сессия блокируется
+const idleTimeout = 10 * time.Minute
+
+// sessionUI связывает сессию пользователя с окнами его роли:
+// при выходе или блокировке они закрываются или скрываются все вместе.
+type sessionUI struct {
+	store   *db.Store
+	auth    auth.Authenticator
+	app     fyne.App
+	session *auth.Session
+
+	mu      sync.Mutex
+	windows []fyne.Window
+}
+
+func newSessionUI(store *db.Store, authenticator auth.Authenticator, app fyne.App, session *auth.Session) *sessionUI {
+	ui := &sessionUI{store: store, auth: authenticator, app: app, session: session}
+	go ui.watchIdle()
+	return ui
+}
+
+// newWindow создаёт окно, принадлежащее сессии
+func (ui *sessionUI) newWindow(title string) fyne.Window {
+	w := &sessionWindow{Window: ui.app.NewWindow(title), session: ui.session}
+	// Клавиши доходят до окна, только если ни одно поле ввода не в фокусе;
+	// ввод в поля отмечает watchInput
+	w.Canvas().SetOnTypedKey(func(*fyne.KeyEvent) { ui.session.Touch() })
+	w.Canvas().SetOnTypedRune(func(rune) { ui.session.Touch() })
+	w.SetOnClosed(func() { ui.forget(w) })
+
+	ui.mu.Lock()
+	ui.windows = append(ui.windows, w)
+	ui.mu.Unlock()
+
+	ui.session.Touch()
+	return w
+}
+
+// sessionWindow — окно сессии, содержимое которого обёрнуто в activityArea,
+// а изменения в полях ввода продлевают сессию
+type sessionWindow struct {
+	fyne.Window
+	session *auth.Session
+}
+
+func (w *sessionWindow) SetContent(content fyne.CanvasObject) {
+	watchInput(content, w.session.Touch)
+	w.Window.SetContent(newActivityArea(content, w.session))
+}
+
+// watchInput добавляет touch к обработчикам изменений полей ввода, списков выбора и флажков
+// в content. Обработчики, назначенные позже SetContent, нужно дополнять самостоятельно.
+func watchInput(content fyne.CanvasObject, touch func()) {
+	switch o := content.(type) {
+	case *fyne.Container:
+		for _, child := range o.Objects {
+			watchInput(child, touch)
+		}
+	case *container.Scroll:
+		watchInput(o.Content, touch)
+	case *container.Split:
+		watchInput(o.Leading, touch)
+		watchInput(o.Trailing, touch)
+	case *container.AppTabs:
+		for _, item := range o.Items {
+			watchInput(item.Content, touch)
+		}
+	case *widget.Form:
+		for _, item := range o.Items {
+			watchInput(item.Widget, touch)
+		}
+	case *widget.Entry:
+		chainOnChanged(o, touch)
+	case *widget.SelectEntry:
+		chainOnChanged(&o.Entry, touch)
+	case *widget.Select:
+		changed := o.OnChanged
+		o.OnChanged = func(value string) {
+			if changed != nil {
+				changed(value)
+			}
+			touch()
+		}
+	case *widget.Check:
+		changed := o.OnChanged
+		o.OnChanged = func(checked bool) {
+			if changed != nil {
+				changed(checked)
+			}
+			touch()
+		}
+	}
+}
+
+// activityArea отмечает движения мыши над свободными частями окна. Над кнопками, полями
+// и списками события получают они сами; действия с ними отмечают button и watchInput.
+type activityArea struct {
+	widget.BaseWidget
+	content fyne.CanvasObject
+	session *auth.Session
+}
+
+func newActivityArea(content fyne.CanvasObject, session *auth.Session) *activityArea {
+	a := &activityArea{content: content, session: session}
+	a.ExtendBaseWidget(a)
+	return a
+}
+
+func (a *activityArea) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(a.content)
+}
+
+func (a *activityArea) MouseIn(*desktop.MouseEvent)    { a.session.Touch() }
+func (a *activityArea) MouseMoved(*desktop.MouseEvent) { a.session.Touch() }
+func (a *activityArea) MouseOut()                      {}
+
+// button создаёт кнопку, нажатие которой считается действием пользователя
+func (ui *sessionUI) button(label string, tapped func()) *widget.Button {
+	return widget.NewButton(label, func() {
+		if !ui.session.Active() {
+			return
+		}
+		ui.session.Touch()
+		tapped()
+	})
+}
+
+func (ui *sessionUI) forget(w fyne.Window) {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+
+	for i, existing := range ui.windows {
+		if existing == w {
+			ui.windows = append(ui.windows[:i], ui.windows[i+1:]...)
+			return
+		}
+	}
+}
+
+// openWindows возвращает копию списка окон сессии
+func (ui *sessionUI) openWindows() []fyne.Window {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+
+	return append([]fyne.Window(nil), ui.windows...)
+}
+
+// logout завершает сессию, закрывает все окна роли и возвращает к выбору роли
+func (ui *sessionUI) logout() {
+	ui.session.Close()
+	if l, ok := ui.auth.(auth.Logouter); ok {
+		if err := l.Logout(); err != nil {
+			fyne.LogError("Не удалось завершить сессию на сервере", err)
+		}
+	}
+
+	// Окно выбора роли открывается до закрытия остальных, иначе приложение завершится
+	showMainWindow(ui.store, ui.auth, ui.app)
+	for _, w := range ui.openWindows() {
+		w.Close()
+	}
+}
+
+// watchIdle блокирует сессию после idleTimeout без действий пользователя
+func (ui *sessionUI) watchIdle() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if ui.session.Closed() {
+			return
+		}
+		if time.Since(ui.session.IdleSince()) >= idleTimeout && ui.session.Lock() {
+			ui.showLockWindow()
+		}
+	}
+}
+
+// showLockWindow скрывает окна сессии и просит повторно ввести пароль
+func (ui *sessionUI) showLockWindow() {
+	hidden := ui.openWindows()
+	for _, w := range hidden {
+		w.Hide()
+	}
+
+	lockWindow := ui.app.NewWindow("Сессия заблокирована")
+	lockWindow.Resize(fyne.NewSize(300, 200))
+
+	passwordEntry := widget.NewPasswordEntry()
+	passwordEntry.SetPlaceHolder("Пароль")
+
+	unlockButton := widget.NewButton("Разблокировать", func() {
+		if err := auth.Unlock(ui.auth, ui.session, passwordEntry.Text); err != nil {
+			dialog.ShowError(err, lockWindow)
+			return
+		}
+		for _, w := range hidden {
+			w.Show()
+		}
+		lockWindow.Close()
+	})
+
+	logout := func() {
+		ui.logout()
+		lockWindow.Close()
+	}
+	logoutButton := widget.NewButton("Выйти", logout)
+	// Иначе закрытое окно блокировки оставило бы окна сессии скрытыми без способа их вернуть
+	lockWindow.SetCloseIntercept(logout)
+
+	lockWindow.SetContent(container.NewVBox(
+		widget.NewLabel("Сессия "+ui.session.Login+" заблокирована из-за простоя."),
+		passwordEntry,
+		container.NewHBox(unlockButton, logoutButton),
+	))
+	lockWindow.Show()
+}
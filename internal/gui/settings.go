@@ -0,0 +1,52 @@
+package gui
+
+import (
+	"embed"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/lang"
+)
+
+// Settings — настройки интерфейса, передаваемые при запуске
+type Settings struct {
+	// Currency — обозначение валюты в ценах
+	Currency string
+}
+
+// currency — обозначение валюты во всех окнах; задаётся настройками при запуске
+var currency = "Р"
+
+// translations — переводы стандартных кнопок и диалогов Fyne, которых нет в самой библиотеке.
+// Fyne применяет их, если язык системы русский; иначе стандартные кнопки остаются на языке системы.
+//
+//go:embed translations
+var translations embed.FS
+
+// applySettings применяет настройки до создания первого окна
+func applySettings(s Settings) {
+	if s.Currency != "" {
+		currency = s.Currency
+	}
+	if err := lang.AddTranslationsFS(translations, "translations"); err != nil {
+		fyne.LogError("Не удалось загрузить переводы интерфейса", err)
+	}
+}
+
+// formatMoney выводит сумму с копейками и обозначением валюты
+func formatMoney(v float64) string {
+	return fmt.Sprintf("%.2f %s", v, currency)
+}
+
+// formatMoneyChange выводит изменение суммы со знаком, как скидку и наценку в карточке автомобиля
+func formatMoneyChange(v float64) string {
+	if v < 0 {
+		return "−" + formatMoney(-v)
+	}
+	return "+" + formatMoney(v)
+}
+
+// formatMoneyRounded выводит сумму, округлённую до целых, с обозначением валюты
+func formatMoneyRounded(v float64) string {
+	return fmt.Sprintf("%.0f %s", v, currency)
+}
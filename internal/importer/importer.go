@@ -0,0 +1,149 @@
+// Package importer загружает автомобили из файлов CSV и JSON.
+// Каждая запись проверяется по тем же правилам, что и форма добавления автомобиля;
+// принятые записи добавляются в базу одной транзакцией.
+package importer
+
+import (
+	"car-sales-system/internal/db"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// Format — формат файла импорта
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatJSON Format = "json"
+)
+
+// Formats — поддерживаемые форматы; совпадают с расширениями файлов
+var Formats = []Format{FormatCSV, FormatJSON}
+
+// ErrFormat возвращается для файлов неподдерживаемого формата
+var ErrFormat = errors.New("поддерживаются только файлы CSV и JSON")
+
+// FormatFromPath определяет формат по расширению файла
+func FormatFromPath(path string) (Format, error) {
+	ext := Format(strings.ToLower(strings.TrimPrefix(filepath.Ext(path), ".")))
+	for _, f := range Formats {
+		if f == ext {
+			return f, nil
+		}
+	}
+	return "", ErrFormat
+}
+
+// Row — запись файла импорта и результат её проверки
+type Row struct {
+	// Number — номер записи с единицы: для CSV — номер строки файла, для JSON — номер объекта в массиве
+	Number int
+	// Car — прочитанный автомобиль; после импорта в Car.ID записан присвоенный ID
+	Car db.Car
+	// Err — причина, по которой запись отклонена; nil у принятых записей
+	Err error
+}
+
+// Title возвращает краткое описание автомобиля из записи
+func (r Row) Title() string {
+	title := strings.TrimSpace(fmt.Sprintf("%s %s", r.Car.Brand, r.Car.Model))
+	if r.Car.Year != 0 {
+		title = strings.TrimSpace(fmt.Sprintf("%s %d", title, r.Car.Year))
+	}
+	if title == "" {
+		return "—"
+	}
+	return title
+}
+
+// Report — результат пробного импорта: все записи файла с итогом проверки
+type Report struct {
+	Rows []Row
+	// Ignored — столбцы файла, которые не соответствуют ни одному полю автомобиля
+	Ignored []string
+}
+
+// Accepted возвращает записи, прошедшие проверку
+func (r *Report) Accepted() []Row {
+	return r.filter(true)
+}
+
+// Rejected возвращает отклонённые записи
+func (r *Report) Rejected() []Row {
+	return r.filter(false)
+}
+
+func (r *Report) filter(accepted bool) []Row {
+	var rows []Row
+	for _, row := range r.Rows {
+		if (row.Err == nil) == accepted {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// Prepare читает файл и проверяет каждую запись, ничего не изменяя в базе.
+// Кроме правил формы проверяется, что VIN не повторяется в файле и не занят автомобилем из базы.
+// Ошибка возвращается, только если файл нельзя прочитать целиком; ошибки записей попадают в отчёт.
+func Prepare(r io.Reader, format Format, cars db.CarRepository) (*Report, error) {
+	report, err := parse(r, format)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := cars.List()
+	if err != nil {
+		return nil, err
+	}
+	taken := make(map[string]bool)
+	for _, car := range existing {
+		if car.VIN != "" {
+			taken[car.VIN] = true
+		}
+	}
+
+	seen := make(map[string]int)
+	for i := range report.Rows {
+		row := &report.Rows[i]
+		if row.Err == nil {
+			row.Err = db.ValidateCar(row.Car)
+		}
+		if row.Err != nil || row.Car.VIN == "" {
+			continue
+		}
+		if first, ok := seen[row.Car.VIN]; ok {
+			row.Err = fmt.Errorf("VIN повторяет запись %d", first)
+		} else if taken[row.Car.VIN] {
+			row.Err = db.ErrDuplicateVIN
+		} else {
+			seen[row.Car.VIN] = row.Number
+		}
+	}
+	return report, nil
+}
+
+// Import добавляет принятые записи отчёта одной транзакцией и возвращает их число.
+// Если хотя бы один автомобиль добавить не удалось, не добавляется ни один.
+func Import(report *Report, cars db.CarRepository) (int, error) {
+	var indexes []int
+	var batch []db.Car
+	for i, row := range report.Rows {
+		if row.Err == nil {
+			indexes = append(indexes, i)
+			batch = append(batch, row.Car)
+		}
+	}
+	if len(batch) == 0 {
+		return 0, errors.New("в файле нет записей, прошедших проверку")
+	}
+	if err := cars.CreateBatch(batch); err != nil {
+		return 0, err
+	}
+	for n, i := range indexes {
+		report.Rows[i].Car = batch[n]
+	}
+	return len(batch), nil
+}
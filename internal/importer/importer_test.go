@@ -0,0 +1,162 @@
+package importer
+
+import (
+	"car-sales-system/internal/db"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFormatFromPath(t *testing.T) {
+	for path, want := range map[string]Format{"cars.csv": FormatCSV, "C:/Данные/CARS.JSON": FormatJSON} {
+		if got, err := FormatFromPath(path); err != nil || got != want {
+			t.Errorf("FormatFromPath(%q) = %q, %v", path, got, err)
+		}
+	}
+	if _, err := FormatFromPath("cars.xlsx"); !errors.Is(err, ErrFormat) {
+		t.Errorf("xlsx: %v", err)
+	}
+}
+
+func TestPrepareCSV(t *testing.T) {
+	store := db.NewMemoryStore()
+	existing := db.Car{Brand: "Honda", Model: "Accord", Year: 2003, Color: "Серый", Price: 5000, VIN: "1HGCM82633A004352"}
+	if err := store.Cars.Create(&existing); err != nil {
+		t.Fatal(err)
+	}
+
+	// Заголовок и числа в том виде, в каком их сохраняет русская версия Excel
+	file := "\ufeffМарка;Модель;Год выпуска;Цвет;Цена;VIN;Пробег, км;Объём двигателя, л;Топливо;Коробка передач;Примечание\n" +
+		"Toyota;Camry;2020;Черный;24 000,50;;15000;2,5;бензин;automatic;новый\n" +
+		"BMW;X5;1800;Белый;50000;;;;;;\n" +
+		"Audi;A4;2019;Синий;30000;;;;керосин;;\n" +
+		";;;;;;;;;;\n" +
+		"Kia;Rio;2018;Красный;9000;1m8gdm9axkp042788;;;;;\n" +
+		"Kia;Ceed;2019;Красный;11000;1M8GDM9AXKP042788;;;;;\n" +
+		"Honda;Civic;2010;Серый;7000;1HGCM82633A004352;;;;;\n" +
+		"Lada;Vesta;2021;Белый;abc;;;;;;\n"
+	report, err := Prepare(strings.NewReader(file), FormatCSV, store.Cars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Ignored) != 1 || report.Ignored[0] != "Примечание" {
+		t.Errorf("пропущенные столбцы: %v", report.Ignored)
+	}
+
+	if len(report.Rows) != 7 {
+		t.Fatalf("записи: %+v", report.Rows)
+	}
+	accepted := report.Accepted()
+	if len(accepted) != 2 || accepted[0].Number != 2 || accepted[1].Number != 6 {
+		t.Fatalf("принятые записи: %+v", accepted)
+	}
+	camry := accepted[0].Car
+	if camry.Price != 24000.5 || camry.Mileage != 15000 || camry.EngineVolume != 2.5 ||
+		camry.Fuel != db.FuelPetrol || camry.Transmission != db.TransmissionAutomatic {
+		t.Errorf("Camry: %+v", camry)
+	}
+	if accepted[1].Car.VIN != "1M8GDM9AXKP042788" {
+		t.Errorf("VIN не нормализован: %q", accepted[1].Car.VIN)
+	}
+
+	rejected := map[int]error{}
+	for _, row := range report.Rejected() {
+		rejected[row.Number] = row.Err
+	}
+	if !errors.Is(rejected[3], db.ErrInvalidCar) {
+		t.Errorf("год 1800: %v", rejected[3])
+	}
+	if rejected[4] == nil || !strings.Contains(rejected[4].Error(), "топливо") {
+		t.Errorf("неизвестное топливо: %v", rejected[4])
+	}
+	if rejected[7] == nil || !strings.Contains(rejected[7].Error(), "запись 6") {
+		t.Errorf("повтор VIN в файле: %v", rejected[7])
+	}
+	if !errors.Is(rejected[8], db.ErrDuplicateVIN) {
+		t.Errorf("VIN из базы: %v", rejected[8])
+	}
+	if rejected[9] == nil {
+		t.Error("цена abc принята")
+	}
+
+	// Пробный импорт ничего не добавляет
+	cars, err := store.Cars.List()
+	if err != nil || len(cars) != 1 {
+		t.Fatalf("после проверки в базе %d автомобилей, %v", len(cars), err)
+	}
+
+	n, err := Import(report, store.Cars)
+	if err != nil || n != 2 {
+		t.Fatalf("импорт: %d, %v", n, err)
+	}
+	cars, err = store.Cars.List()
+	if err != nil || len(cars) != 3 {
+		t.Fatalf("после импорта в базе %d автомобилей, %v", len(cars), err)
+	}
+	if report.Accepted()[0].Car.ID == 0 {
+		t.Error("ID добавленного автомобиля не записан в отчёт")
+	}
+}
+
+func TestPrepareJSON(t *testing.T) {
+	store := db.NewMemoryStore()
+	file := `[
+		{"brand": "Toyota", "model": "Corolla", "year": 2019, "color": "White", "price": 15000.5, "body": "Седан", "drive": null, "id": 7},
+		{"brand": "Toyota", "model": "Corolla", "year": "2019", "color": "White", "price": 15000, "owners": true},
+		{"brand": "Toyota", "model": "Corolla", "year": 2019, "color": "White", "price": 15000.123}
+	]`
+	report, err := Prepare(strings.NewReader(file), FormatJSON, store.Cars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Ignored) != 1 || report.Ignored[0] != "id" {
+		t.Errorf("пропущенные столбцы: %v", report.Ignored)
+	}
+	if len(report.Rows) != 3 || report.Rows[0].Err != nil || report.Rows[1].Err == nil || report.Rows[2].Err == nil {
+		t.Fatalf("записи: %+v", report.Rows)
+	}
+	if car := report.Rows[0].Car; car.Body != db.BodySedan || car.Price != 15000.5 {
+		t.Errorf("Corolla: %+v", car)
+	}
+}
+
+func TestPrepareRejectsFile(t *testing.T) {
+	store := db.NewMemoryStore()
+	for name, tt := range map[string]struct {
+		format Format
+		file   string
+	}{
+		"нет столбца цены":   {FormatCSV, "brand,model,year,color\nToyota,Camry,2020,Black\n"},
+		"повтор столбца":     {FormatCSV, "brand,марка,model,year,color,price\n"},
+		"пустой файл":        {FormatCSV, ""},
+		"JSON не массив":     {FormatJSON, `{"brand": "Toyota"}`},
+		"формат не указан":   {"", "brand\n"},
+		"незакрытая кавычка": {FormatCSV, "brand,model,year,color,price\n\"Toyota,Camry,2020,Black,1\n"},
+	} {
+		if _, err := Prepare(strings.NewReader(tt.file), tt.format, store.Cars); err == nil {
+			t.Errorf("%s: файл принят", name)
+		}
+	}
+}
+
+func TestImportIsAtomic(t *testing.T) {
+	store := db.NewMemoryStore()
+	report, err := Prepare(strings.NewReader("brand,model,year,color,price,vin\n"+
+		"Kia,Rio,2018,Red,9000,1M8GDM9AXKP042788\n"+
+		"Kia,Ceed,2019,Red,11000,1HGCM82633A004352\n"), FormatCSV, store.Cars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// VIN занят после пробного импорта — импорт не должен добавить и первую запись
+	taken := db.Car{Brand: "Honda", Model: "Accord", Year: 2003, Color: "Grey", Price: 5000, VIN: "1HGCM82633A004352"}
+	if err := store.Cars.Create(&taken); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Import(report, store.Cars); !errors.Is(err, db.ErrDuplicateVIN) {
+		t.Fatalf("импорт: %v", err)
+	}
+	cars, err := store.Cars.List()
+	if err != nil || len(cars) != 1 {
+		t.Errorf("после неудачного импорта в базе %d автомобилей, %v", len(cars), err)
+	}
+}
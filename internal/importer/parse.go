@@ -0,0 +1,350 @@
+package importer
+
+import (
+	"bytes"
+	"car-sales-system/internal/db"
+	"car-sales-system/internal/vin"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Поля автомобиля, которым сопоставляются столбцы файла
+const (
+	fieldBrand        = "brand"
+	fieldModel        = "model"
+	fieldYear         = "year"
+	fieldColor        = "color"
+	fieldPrice        = "price"
+	fieldVIN          = "vin"
+	fieldMileage      = "mileage"
+	fieldEngineVolume = "engine_volume"
+	fieldFuel         = "fuel"
+	fieldTransmission = "transmission"
+	fieldBody         = "body"
+	fieldDrive        = "drive"
+	fieldOwners       = "owners"
+)
+
+// requiredFields — поля, без столбцов которых файл не импортируется
+var requiredFields = []string{fieldBrand, fieldModel, fieldYear, fieldColor, fieldPrice}
+
+// columnAliases сопоставляет названия столбцов (после columnKey) полям автомобиля.
+// Распознаются названия столбцов таблицы Cars и подписи полей формы.
+var columnAliases = map[string]string{
+	"brand": fieldBrand, "make": fieldBrand, "марка": fieldBrand,
+	"model": fieldModel, "модель": fieldModel,
+	"year": fieldYear, "yearofrelease": fieldYear, "год": fieldYear, "годвыпуска": fieldYear,
+	"color": fieldColor, "colour": fieldColor, "цвет": fieldColor,
+	"price": fieldPrice, "цена": fieldPrice,
+	"vin": fieldVIN, "вин": fieldVIN,
+	"mileage": fieldMileage, "пробег": fieldMileage, "пробегкм": fieldMileage,
+	"enginevolume": fieldEngineVolume, "объем": fieldEngineVolume, "объемдвигателя": fieldEngineVolume, "объемдвигателял": fieldEngineVolume,
+	"fuel": fieldFuel, "fueltype": fieldFuel, "топливо": fieldFuel,
+	"transmission": fieldTransmission, "коробка": fieldTransmission, "коробкапередач": fieldTransmission, "кпп": fieldTransmission,
+	"body": fieldBody, "bodytype": fieldBody, "кузов": fieldBody,
+	"drive": fieldDrive, "drivetype": fieldDrive, "привод": fieldDrive,
+	"owners": fieldOwners, "владельцы": fieldOwners, "числовладельцев": fieldOwners,
+}
+
+// columnKey приводит название столбца к виду ключей columnAliases:
+// нижний регистр, «ё» как «е», без пробелов и знаков препинания
+func columnKey(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r == 'ё':
+			b.WriteRune('е')
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// record — значения одной записи файла по полям автомобиля
+type record map[string]string
+
+// parse читает все записи файла; ошибки значений записываются в Row.Err
+func parse(r io.Reader, format Format) (*Report, error) {
+	switch format {
+	case FormatCSV:
+		return parseCSV(r)
+	case FormatJSON:
+		return parseJSON(r)
+	default:
+		return nil, ErrFormat
+	}
+}
+
+// parseCSV читает CSV с заголовком. Разделитель — запятая или точка с запятой
+// (как сохраняет таблицы русская версия Excel): выбирается тот, которого больше в заголовке.
+func parseCSV(r io.Reader) (*Report, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения файла: %w", err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	header, _, _ := bytes.Cut(data, []byte("\n"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	if bytes.Count(header, []byte(";")) > bytes.Count(header, []byte(",")) {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	names, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("файл пуст")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения заголовка CSV: %w", err)
+	}
+	report := &Report{}
+	fields, err := mapColumns(names, report)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		values, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("ошибка чтения CSV: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		if blank(values) {
+			continue
+		}
+		rec := make(record)
+		for i, v := range values {
+			if i < len(fields) && fields[i] != "" {
+				rec[fields[i]] = v
+			}
+		}
+		report.Rows = append(report.Rows, newRow(line, rec))
+	}
+	return report, nil
+}
+
+// parseJSON читает массив объектов; ключи объектов — названия столбцов, как в заголовке CSV
+func parseJSON(r io.Reader) (*Report, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	var objects []map[string]any
+	if err := decoder.Decode(&objects); err != nil {
+		return nil, fmt.Errorf("ошибка чтения JSON: ожидается массив объектов: %w", err)
+	}
+
+	report := &Report{}
+	names := make(map[string]bool)
+	for _, obj := range objects {
+		for name := range obj {
+			names[name] = true
+		}
+	}
+	columns := make([]string, 0, len(names))
+	for name := range names {
+		columns = append(columns, name)
+	}
+	sort.Strings(columns)
+	fields, err := mapColumns(columns, report)
+	if err != nil {
+		return nil, err
+	}
+	fieldOf := make(map[string]string, len(columns))
+	for i, name := range columns {
+		fieldOf[name] = fields[i]
+	}
+
+	for i, obj := range objects {
+		rec := make(record)
+		var valueErr error
+		for name, value := range obj {
+			field := fieldOf[name]
+			if field == "" {
+				continue
+			}
+			switch v := value.(type) {
+			case nil:
+			case string:
+				rec[field] = v
+			case json.Number:
+				rec[field] = v.String()
+			default:
+				valueErr = fmt.Errorf("значение «%s» должно быть строкой или числом", name)
+			}
+		}
+		row := newRow(i+1, rec)
+		if valueErr != nil {
+			row.Err = valueErr
+		}
+		report.Rows = append(report.Rows, row)
+	}
+	return report, nil
+}
+
+// mapColumns сопоставляет столбцы полям автомобиля; нераспознанные столбцы записываются в report.Ignored.
+// Для каждого столбца возвращается поле или пустая строка.
+func mapColumns(names []string, report *Report) ([]string, error) {
+	fields := make([]string, len(names))
+	used := make(map[string]string)
+	for i, name := range names {
+		field, ok := columnAliases[columnKey(name)]
+		if !ok {
+			report.Ignored = append(report.Ignored, name)
+			continue
+		}
+		if other, dup := used[field]; dup {
+			return nil, fmt.Errorf("столбцы «%s» и «%s» означают одно и то же поле", other, name)
+		}
+		used[field] = name
+		fields[i] = field
+	}
+	var missing []string
+	for _, field := range requiredFields {
+		if _, ok := used[field]; !ok {
+			missing = append(missing, db.CarFieldTitle(field))
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("в файле нет обязательных столбцов: %s", strings.Join(missing, ", "))
+	}
+	return fields, nil
+}
+
+// blank сообщает, что в строке CSV нет ни одного значения
+func blank(values []string) bool {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// newRow разбирает значения записи в автомобиль
+func newRow(number int, rec record) Row {
+	car, err := readCar(rec)
+	return Row{Number: number, Car: car, Err: err}
+}
+
+// readCar разбирает значения полей в порядке полей формы и возвращает первую ошибку.
+// Числа могут содержать пробелы между разрядами и десятичную запятую; характеристики
+// задаются кодом или названием. Прочитанные до ошибки поля остаются заполненными для отчёта.
+func readCar(rec record) (db.Car, error) {
+	car := db.Car{
+		Brand: strings.TrimSpace(rec[fieldBrand]),
+		Model: strings.TrimSpace(rec[fieldModel]),
+		Color: strings.TrimSpace(rec[fieldColor]),
+		VIN:   vin.Normalize(rec[fieldVIN]),
+	}
+	var err error
+	if car.Year, err = parseInt(rec[fieldYear], "год выпуска", true); err != nil {
+		return car, err
+	}
+	if car.Price, err = parseDecimal(rec[fieldPrice], "цена", 2, true); err != nil {
+		return car, err
+	}
+	if car.Mileage, err = parseInt(rec[fieldMileage], "пробег", false); err != nil {
+		return car, err
+	}
+	if car.EngineVolume, err = parseDecimal(rec[fieldEngineVolume], "объём двигателя", 1, false); err != nil {
+		return car, err
+	}
+	if car.Owners, err = parseInt(rec[fieldOwners], "число владельцев", false); err != nil {
+		return car, err
+	}
+	if car.Fuel, err = parseSpec(db.FuelTypes, rec[fieldFuel], "топливо"); err != nil {
+		return car, err
+	}
+	if car.Transmission, err = parseSpec(db.Transmissions, rec[fieldTransmission], "коробка передач"); err != nil {
+		return car, err
+	}
+	if car.Body, err = parseSpec(db.BodyTypes, rec[fieldBody], "кузов"); err != nil {
+		return car, err
+	}
+	car.Drive, err = parseSpec(db.DriveTypes, rec[fieldDrive], "привод")
+	return car, err
+}
+
+// numberText убирает пробелы между разрядами и заменяет десятичную запятую точкой
+func numberText(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+	return strings.Replace(s, ",", ".", 1)
+}
+
+// parseInt разбирает неотрицательное целое; пустое необязательное значение — ноль
+func parseInt(s, title string, required bool) (int, error) {
+	s = numberText(s)
+	if s == "" {
+		if required {
+			return 0, fmt.Errorf("не указано поле «%s»", title)
+		}
+		return 0, nil
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return 0, fmt.Errorf("поле «%s» должно содержать только цифры: %s", title, s)
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("поле «%s» должно быть числом: %s", title, s)
+	}
+	return n, nil
+}
+
+// parseDecimal разбирает неотрицательное число не более чем с decimals знаками после запятой
+func parseDecimal(s, title string, decimals int, required bool) (float64, error) {
+	s = numberText(s)
+	if s == "" {
+		if required {
+			return 0, fmt.Errorf("не указано поле «%s»", title)
+		}
+		return 0, nil
+	}
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" || len(frac) > decimals || strings.Trim(whole+frac, "0123456789") != "" {
+		return 0, fmt.Errorf("поле «%s» должно быть числом не более чем с %d знаками после запятой: %s", title, decimals, s)
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("поле «%s» должно быть числом: %s", title, s)
+	}
+	return v, nil
+}
+
+// titled — характеристика автомобиля с названием для интерфейса
+type titled interface {
+	~string
+	Title() string
+}
+
+// parseSpec находит характеристику по коду или названию без учёта регистра; пустое значение — не указана
+func parseSpec[T titled](values []T, s, title string) (T, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", nil
+	}
+	key := strings.ReplaceAll(strings.ToLower(s), "ё", "е")
+	for _, v := range values {
+		if key == string(v) || key == strings.ReplaceAll(v.Title(), "ё", "е") {
+			return v, nil
+		}
+	}
+	return "", fmt.Errorf("неизвестное значение поля «%s»: %s", title, s)
+}
@@ -0,0 +1,99 @@
+// Package photo проверяет загружаемые фотографии автомобилей и готовит их миниатюры.
+package photo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+
+	// Декодеры поддерживаемых форматов регистрируются в image.Decode
+	_ "image/gif"
+	_ "image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	// MaxSize — наибольший размер загружаемого файла
+	MaxSize = 10 << 20
+	// MaxPixels — наибольшее число пикселей изображения. Заголовок файла может обещать
+	// огромные размеры, и декодирование такого файла заняло бы гигабайты памяти.
+	MaxPixels = 50_000_000
+	// ThumbnailSide — наибольшая сторона миниатюры в пикселях
+	ThumbnailSide = 240
+
+	thumbnailQuality = 85
+)
+
+// ErrTooLarge возвращается для файлов больше MaxSize
+var ErrTooLarge = fmt.Errorf("файл изображения больше %d МБ", MaxSize>>20)
+
+// ErrTooManyPixels возвращается для изображений больше MaxPixels пикселей
+var ErrTooManyPixels = fmt.Errorf("изображение больше %d мегапикселей", MaxPixels/1_000_000)
+
+// ErrUnsupported возвращается, если файл не является изображением поддерживаемого формата
+var ErrUnsupported = errors.New("поддерживаются изображения JPEG, PNG, GIF и WebP")
+
+// Prepared — проверенное изображение и его миниатюра в JPEG
+type Prepared struct {
+	ContentType string
+	Data        []byte
+	Thumbnail   []byte
+}
+
+// Prepare проверяет формат и размер изображения и строит миниатюру.
+// Исходный файл сохраняется без изменений. Размеры в пикселях проверяются
+// по заголовку до декодирования.
+func Prepare(data []byte) (Prepared, error) {
+	if len(data) > MaxSize {
+		return Prepared{}, ErrTooLarge
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return Prepared{}, ErrUnsupported
+	}
+	if config.Width <= 0 || config.Height <= 0 || int64(config.Width)*int64(config.Height) > MaxPixels {
+		return Prepared{}, ErrTooManyPixels
+	}
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return Prepared{}, ErrUnsupported
+	}
+
+	thumbnail, err := Thumbnail(img, ThumbnailSide)
+	if err != nil {
+		return Prepared{}, err
+	}
+	return Prepared{ContentType: "image/" + format, Data: data, Thumbnail: thumbnail}, nil
+}
+
+// Thumbnail уменьшает изображение так, чтобы большая сторона не превышала side,
+// и кодирует его в JPEG. Маленькие изображения не увеличиваются.
+func Thumbnail(img image.Image, side int) ([]byte, error) {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w == 0 || h == 0 {
+		return nil, ErrUnsupported
+	}
+	if w > side || h > side {
+		if w >= h {
+			w, h = side, max(1, h*side/w)
+		} else {
+			w, h = max(1, w*side/h), side
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	// Прозрачные области становятся белыми: в JPEG нет альфа-канала
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Over, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return nil, fmt.Errorf("ошибка создания миниатюры: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,75 @@
+package photo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func encodePNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for x := 0; x < w; x++ {
+		img.Set(x, 0, color.NRGBA{R: 0xff, A: 0xff})
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestPrepare(t *testing.T) {
+	tests := []struct {
+		w, h         int
+		wantW, wantH int
+	}{
+		{1200, 800, ThumbnailSide, 160},
+		{300, 900, 80, ThumbnailSide},
+		{100, 50, 100, 50},
+	}
+	for _, tt := range tests {
+		data := encodePNG(t, tt.w, tt.h)
+		p, err := Prepare(data)
+		if err != nil {
+			t.Fatalf("%dx%d: %v", tt.w, tt.h, err)
+		}
+		if p.ContentType != "image/png" || !bytes.Equal(p.Data, data) {
+			t.Errorf("%dx%d: тип %s", tt.w, tt.h, p.ContentType)
+		}
+		thumb, err := jpeg.DecodeConfig(bytes.NewReader(p.Thumbnail))
+		if err != nil {
+			t.Fatalf("%dx%d: миниатюра не JPEG: %v", tt.w, tt.h, err)
+		}
+		if thumb.Width != tt.wantW || thumb.Height != tt.wantH {
+			t.Errorf("%dx%d: миниатюра %dx%d, ожидалась %dx%d", tt.w, tt.h, thumb.Width, thumb.Height, tt.wantW, tt.wantH)
+		}
+	}
+}
+
+func TestPrepareRejectsBadInput(t *testing.T) {
+	if _, err := Prepare([]byte("not an image")); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("не изображение: ожидалась ErrUnsupported, получено %v", err)
+	}
+	if _, err := Prepare(make([]byte, MaxSize+1)); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("большой файл: ожидалась ErrTooLarge, получено %v", err)
+	}
+}
+
+func TestPrepareRejectsHugeDimensions(t *testing.T) {
+	// Маленький PNG, в заголовке которого записаны размеры 50000×50000
+	data := encodePNG(t, 2, 2)
+	binary.BigEndian.PutUint32(data[16:], 50000)
+	binary.BigEndian.PutUint32(data[20:], 50000)
+	binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+	if _, err := Prepare(data); !errors.Is(err, ErrTooManyPixels) {
+		t.Errorf("ожидалась ErrTooManyPixels, получено %v", err)
+	}
+}
@@ -0,0 +1,160 @@
+package remote
+
+import (
+	"bytes"
+	"car-sales-system/internal/auth"
+	"car-sales-system/internal/db"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// requestTimeout ограничивает один запрос к серверу
+const requestTimeout = 30 * time.Second
+
+// Client подключается к серверу carsales-server. Он проверяет пароли на сервере
+// (auth.Authenticator) и после входа выполняет операции хранилища от имени вошедшего пользователя.
+type Client struct {
+	baseURL string
+	http    *http.Client
+
+	mu    sync.Mutex
+	token string
+}
+
+// NewClient создаёт клиента сервера по адресу вида http://сервер:8080
+func NewClient(baseURL string) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("неверный адрес сервера %q, ожидается http://сервер:порт", baseURL)
+	}
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		http:    &http.Client{Timeout: requestTimeout},
+	}, nil
+}
+
+// Store возвращает репозитории, выполняющие операции на сервере
+func (c *Client) Store() *db.Store {
+	return &db.Store{
+		Cars:         carRepository{c},
+		Clients:      clientRepository{c},
+		Admins:       adminRepository{c},
+		Checks:       checkRepository{c},
+		Photos:       photoRepository{c},
+		Purchases:    purchaseService{c},
+		Dictionaries: dictionaryRepository{c},
+	}
+}
+
+// RegisterClient регистрирует клиента на сервере; пароль хэшируется сервером
+func (c *Client) RegisterClient(client *db.Client) error {
+	var created db.Client
+	if err := c.call(http.MethodPost, "/api/register", client, &created); err != nil {
+		return err
+	}
+	client.ID = created.ID
+	return nil
+}
+
+// LoginClient входит на сервер клиентом; токен сессии сохраняется для следующих запросов
+func (c *Client) LoginClient(login, password string) (*db.Client, error) {
+	resp, err := c.login(auth.RoleClient, login, password)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Client, nil
+}
+
+// LoginAdmin входит на сервер администратором; токен сессии сохраняется для следующих запросов
+func (c *Client) LoginAdmin(login, password string) (*db.Admin, error) {
+	resp, err := c.login(auth.RoleAdmin, login, password)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Admin, nil
+}
+
+func (c *Client) login(role auth.Role, login, password string) (*loginResponse, error) {
+	var resp loginResponse
+	req := loginRequest{Role: role, Login: login, Password: password}
+	if err := c.call(http.MethodPost, "/api/login", req, &resp); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	previous := c.token
+	c.token = resp.Token
+	c.mu.Unlock()
+	// Повторный вход, например при разблокировке сессии, заменяет токен. Прежний отзывается,
+	// чтобы не оставаться действительным до конца срока; если сервер не ответил, токен истечёт сам.
+	if previous != "" && previous != resp.Token {
+		c.send(previous, http.MethodPost, "/api/logout", nil, nil)
+	}
+	return &resp, nil
+}
+
+// Logout завершает сессию на сервере
+func (c *Client) Logout() error {
+	err := c.call(http.MethodPost, "/api/logout", nil, nil)
+
+	c.mu.Lock()
+	c.token = ""
+	c.mu.Unlock()
+	return err
+}
+
+// call отправляет запрос с токеном текущей сессии
+func (c *Client) call(method, path string, in, out any) error {
+	c.mu.Lock()
+	token := c.token
+	c.mu.Unlock()
+	return c.send(token, method, path, in, out)
+}
+
+// send отправляет запрос с токеном token (пустой — без входа) и телом in (nil — без тела)
+// и декодирует ответ в out (nil — ответ не нужен)
+func (c *Client) send(token, method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("сервер недоступен: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var e errorBody
+		json.NewDecoder(resp.Body).Decode(&e) // тело без JSON — ошибка прокси, хватит кода ответа
+		return decodeError(resp.StatusCode, e)
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("неверный ответ сервера: %w", err)
+	}
+	return nil
+}
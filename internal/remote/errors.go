@@ -0,0 +1,92 @@
+package remote
+
+import (
+	"car-sales-system/internal/auth"
+	"car-sales-system/internal/db"
+	"car-sales-system/internal/photo"
+	"errors"
+	"net/http"
+)
+
+// Ошибки запросов к серверу
+var (
+	ErrUnauthorized = errors.New("требуется вход в систему")
+	ErrForbidden    = errors.New("недостаточно прав для этого действия")
+	ErrBadRequest   = errors.New("неверный запрос")
+	// ErrHashedPassword — рабочее место пытается передать серверу хэш вместо пароля
+	ErrHashedPassword = errors.New("серверу передаётся только пароль в открытом виде, хэширует его сервер")
+)
+
+// errorCode — известная ошибка, которая передаётся клиенту кодом
+// и восстанавливается на его стороне для errors.Is
+type errorCode struct {
+	code   string
+	err    error
+	status int
+}
+
+// errorCodes — известные ошибки в порядке проверки: более частные раньше общих
+var errorCodes = []errorCode{
+	{"invalid_credentials", auth.ErrInvalidCredentials, http.StatusUnauthorized},
+	{"unauthorized", ErrUnauthorized, http.StatusUnauthorized},
+	{"forbidden", ErrForbidden, http.StatusForbidden},
+	{"bad_request", ErrBadRequest, http.StatusBadRequest},
+	{"car_not_found", db.ErrCarNotFound, http.StatusNotFound},
+	{"not_found", db.ErrNotFound, http.StatusNotFound},
+	{"invalid_car", db.ErrInvalidCar, http.StatusUnprocessableEntity},
+	{"duplicate_vin", db.ErrDuplicateVIN, http.StatusConflict},
+	{"invalid_transition", db.ErrInvalidTransition, http.StatusConflict},
+	{"car_sold", db.ErrCarAlreadySold, http.StatusConflict},
+	{"car_reserved", db.ErrCarReserved, http.StatusConflict},
+	{"car_unavailable", db.ErrCarUnavailable, http.StatusConflict},
+	{"check_not_pending", db.ErrCheckNotPending, http.StatusConflict},
+	{"price_changed", db.ErrPriceChanged, http.StatusConflict},
+	{"dictionary_duplicate", db.ErrDictionaryDuplicate, http.StatusConflict},
+	{"dictionary_in_use", db.ErrDictionaryInUse, http.StatusConflict},
+	{"dictionary_merge", db.ErrDictionaryMerge, http.StatusConflict},
+	{"photo_too_large", photo.ErrTooLarge, http.StatusRequestEntityTooLarge},
+	{"photo_too_many_pixels", photo.ErrTooManyPixels, http.StatusRequestEntityTooLarge},
+	{"photo_unsupported", photo.ErrUnsupported, http.StatusUnsupportedMediaType},
+}
+
+// errorBody — тело ответа с ошибкой
+type errorBody struct {
+	Error string `json:"error"`
+	Code  string `json:"code,omitempty"`
+}
+
+// lookupError находит известную ошибку, которую оборачивает err
+func lookupError(err error) (errorCode, bool) {
+	for _, c := range errorCodes {
+		if errors.Is(err, c.err) {
+			return c, true
+		}
+	}
+	return errorCode{}, false
+}
+
+// Error — ошибка, полученная от сервера. Известные ошибки оборачивают исходную,
+// поэтому errors.Is(err, db.ErrCarReserved) работает так же, как с локальной базой.
+type Error struct {
+	Status  int
+	Message string
+	err     error
+}
+
+func (e *Error) Error() string { return e.Message }
+func (e *Error) Unwrap() error { return e.err }
+
+// decodeError восстанавливает ошибку из ответа сервера
+func decodeError(status int, body errorBody) error {
+	e := &Error{Status: status, Message: body.Error}
+	for _, c := range errorCodes {
+		if c.code == body.Code {
+			e.err = c.err
+			break
+		}
+	}
+	if e.Message == "" {
+		e.Message = http.StatusText(status)
+	}
+	return e
+}
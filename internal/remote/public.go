@@ -0,0 +1,277 @@
+package remote
+
+import (
+	"car-sales-system/internal/auth"
+	"car-sales-system/internal/db"
+	_ "embed"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// openAPISpec — описание открытого API для сайта
+//
+//go:embed openapi.yaml
+var openAPISpec []byte
+
+// Размер страницы каталога открытого API
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// Тела запросов и ответов открытого API. В отличие от /api для рабочих мест,
+// поля названы в стиле JSON и не повторяют модели пакета db.
+type (
+	tokenRequest struct {
+		Login    string `json:"login"`
+		Password string `json:"password"`
+	}
+	tokenResponse struct {
+		Token     string `json:"token"`
+		TokenType string `json:"token_type"`
+		// ExpiresIn — через сколько секунд простоя токен перестанет действовать
+		ExpiresIn int `json:"expires_in"`
+	}
+	publicCar struct {
+		ID           int                `json:"id"`
+		Brand        string             `json:"brand"`
+		Model        string             `json:"model"`
+		Year         int                `json:"year"`
+		Color        string             `json:"color"`
+		Price        float64            `json:"price"`
+		Status       db.CarStatus       `json:"status"`
+		Mileage      int                `json:"mileage,omitempty"`
+		EngineVolume float64            `json:"engine_volume,omitempty"`
+		Fuel         db.FuelType        `json:"fuel,omitempty"`
+		Transmission db.Transmission    `json:"transmission,omitempty"`
+		Body         db.BodyType        `json:"body,omitempty"`
+		Drive        db.DriveType       `json:"drive,omitempty"`
+		Owners       int                `json:"owners,omitempty"`
+		PriceDetails *publicPriceDetail `json:"price_details,omitempty"`
+	}
+	publicPriceDetail struct {
+		ListedPrice float64 `json:"listed_price"`
+		Discount    float64 `json:"discount"`
+	}
+	carPage struct {
+		Items   []publicCar `json:"items"`
+		Page    int         `json:"page"`
+		PerPage int         `json:"per_page"`
+		Total   int         `json:"total"`
+	}
+	orderRequest struct {
+		CarID int `json:"car_id"`
+		// Price — цена, которую видел и подтвердил покупатель
+		Price float64 `json:"price"`
+	}
+	publicOrder struct {
+		ID         int            `json:"id"`
+		CarID      int            `json:"car_id,omitempty"`
+		Brand      string         `json:"brand"`
+		Model      string         `json:"model"`
+		Year       int            `json:"year"`
+		Price      float64        `json:"price"`
+		Status     db.CheckStatus `json:"status"`
+		CreatedAt  *time.Time     `json:"created_at,omitempty"`
+		DecidedAt  *time.Time     `json:"decided_at,omitempty"`
+		CarDeleted bool           `json:"car_deleted,omitempty"`
+	}
+)
+
+// publicStatuses — состояния автомобилей, которые открываются по ссылке GET /v1/cars/{id}:
+// забронированные и проданные тоже, чтобы ссылка из заказа покупателя продолжала работать.
+// Каталог GET /v1/cars, как и окно клиента, показывает только автомобили в наличии.
+var publicStatuses = []db.CarStatus{db.StatusInStock, db.StatusReserved, db.StatusSold}
+
+// publicRoutes регистрирует открытое API для сайта. Каталог доступен без входа,
+// заказы — только клиентам; правила проверки и покупки те же, что в окне клиента.
+func (s *Server) publicRoutes() {
+	s.mux.HandleFunc("GET /v1/openapi.yaml", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/yaml; charset=utf-8")
+		w.Write(openAPISpec)
+	})
+
+	s.handle("POST /v1/tokens", public, func(r *http.Request, _ *sessionUser) (any, error) {
+		var req tokenRequest
+		if err := decode(r, &req); err != nil {
+			return nil, err
+		}
+		client, err := s.auth.LoginClient(req.Login, req.Password)
+		if err != nil {
+			return nil, err
+		}
+		token, err := s.sessions.open(sessionUser{Role: auth.RoleClient, UserID: client.ID, Login: client.Login})
+		if err != nil {
+			return nil, err
+		}
+		return created{tokenResponse{Token: token, TokenType: "Bearer", ExpiresIn: int(sessionTTL.Seconds())}}, nil
+	})
+
+	s.handle("GET /v1/cars", public, func(r *http.Request, _ *sessionUser) (any, error) {
+		filter, err := catalogFilter(r)
+		if err != nil {
+			return nil, err
+		}
+		page, err := queryNumber(r, "page", 1, 0)
+		if err != nil {
+			return nil, err
+		}
+		perPage, err := queryNumber(r, "per_page", defaultPageSize, maxPageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		total, err := s.store.Cars.Count(filter)
+		if err != nil {
+			return nil, err
+		}
+		result := carPage{Items: []publicCar{}, Page: page, PerPage: perPage, Total: total}
+		// Страница за концом каталога пуста; база читается только для существующих страниц
+		if pages := (total + perPage - 1) / perPage; page <= pages {
+			filter.Limit, filter.Offset = perPage, (page-1)*perPage
+			cars, err := s.store.Cars.Search(filter)
+			if err != nil {
+				return nil, err
+			}
+			for _, car := range cars {
+				result.Items = append(result.Items, newPublicCar(car))
+			}
+		}
+		return result, nil
+	})
+
+	s.handle("GET /v1/cars/{id}", public, func(r *http.Request, _ *sessionUser) (any, error) {
+		id, err := pathID(r, "id")
+		if err != nil {
+			return nil, err
+		}
+		car, err := s.store.Cars.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(publicStatuses, car.Status) {
+			return nil, db.ErrNotFound
+		}
+		history, err := s.store.Cars.PriceHistory(id)
+		if err != nil {
+			return nil, err
+		}
+		price := db.BreakdownPrice(*car, history)
+
+		result := newPublicCar(*car)
+		result.PriceDetails = &publicPriceDetail{
+			ListedPrice: price.ListedPrice,
+			Discount:    price.Discount,
+		}
+		return result, nil
+	})
+
+	s.handle("POST /v1/orders", clientOnly, func(r *http.Request, user *sessionUser) (any, error) {
+		var req orderRequest
+		if err := decode(r, &req); err != nil {
+			return nil, err
+		}
+		if req.Price <= 0 {
+			return nil, fmt.Errorf("%w: укажите в поле price цену, которую подтвердил покупатель", ErrBadRequest)
+		}
+		check, err := s.store.Purchases.Purchase(user.UserID, req.CarID, req.Price)
+		if err != nil {
+			return nil, err
+		}
+		order := publicOrder{ID: check.ID, CarID: check.CarID, Price: check.Price, Status: check.Status, CreatedAt: timeOrNil(check.CreatedAt)}
+		if car, err := s.store.Cars.Get(check.CarID); err == nil {
+			order.Brand, order.Model, order.Year = car.Brand, car.Model, car.Year
+		}
+		return created{order}, nil
+	})
+
+	s.handle("GET /v1/clients/me/orders", clientOnly, func(r *http.Request, user *sessionUser) (any, error) {
+		purchases, err := s.store.Checks.ListByClient(user.UserID)
+		if err != nil {
+			return nil, err
+		}
+		orders := make([]publicOrder, len(purchases))
+		for i, p := range purchases {
+			orders[i] = publicOrder{
+				ID: p.CheckID, Brand: p.Brand, Model: p.Model, Year: p.Year, Price: p.Price, Status: p.Status,
+				CreatedAt: timeOrNil(p.CreatedAt), DecidedAt: timeOrNil(p.DecidedAt), CarDeleted: p.CarDeleted,
+			}
+		}
+		return orders, nil
+	})
+}
+
+// catalogFilter разбирает условия каталога из параметров запроса по тем же правилам, что окно клиента
+func catalogFilter(r *http.Request) (db.CarFilter, error) {
+	q := r.URL.Query()
+	filter := db.CarFilter{
+		Statuses: []db.CarStatus{db.StatusInStock},
+		Brand:    q.Get("brand"),
+		Model:    q.Get("model"),
+		Color:    q.Get("color"),
+		Search:   q.Get("q"),
+		Sort:     db.CarSort(q.Get("sort")),
+	}
+	if filter.Sort == "" {
+		filter.Sort = db.SortNewest
+	} else if !slices.Contains(db.CarSorts, filter.Sort) {
+		return filter, fmt.Errorf("%w: неизвестный порядок сортировки %q", ErrBadRequest, filter.Sort)
+	}
+
+	for _, f := range []struct {
+		name string
+		dest *int
+	}{{"year_from", &filter.YearFrom}, {"year_to", &filter.YearTo}} {
+		year, err := db.ParseYearBound(q.Get(f.name))
+		if err != nil {
+			return filter, fmt.Errorf("%w: параметр %s должен содержать год", ErrBadRequest, f.name)
+		}
+		*f.dest = year
+	}
+	for _, f := range []struct {
+		name string
+		dest *float64
+	}{{"price_from", &filter.PriceFrom}, {"price_to", &filter.PriceTo}} {
+		price, err := db.ParsePriceBound(q.Get(f.name))
+		if err != nil {
+			return filter, fmt.Errorf("%w: параметр %s должен содержать число", ErrBadRequest, f.name)
+		}
+		*f.dest = price
+	}
+	return filter, nil
+}
+
+// queryNumber разбирает положительное целое из параметра запроса; max 0 — без ограничения сверху
+func queryNumber(r *http.Request, name string, def, max int) (int, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 || (max > 0 && n > max) {
+		if max > 0 {
+			return 0, fmt.Errorf("%w: параметр %s должен быть числом от 1 до %d", ErrBadRequest, name, max)
+		}
+		return 0, fmt.Errorf("%w: параметр %s должен быть положительным числом", ErrBadRequest, name)
+	}
+	return n, nil
+}
+
+func newPublicCar(car db.Car) publicCar {
+	return publicCar{
+		ID: car.ID, Brand: car.Brand, Model: car.Model, Year: car.Year, Color: car.Color, Price: car.Price, Status: car.Status,
+		Mileage: car.Mileage, EngineVolume: car.EngineVolume, Fuel: car.Fuel, Transmission: car.Transmission,
+		Body: car.Body, Drive: car.Drive, Owners: car.Owners,
+	}
+}
+
+// timeOrNil опускает в ответе нулевое время: у старых чеков время оформления неизвестно
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
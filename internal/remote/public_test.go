@@ -0,0 +1,201 @@
+package remote
+
+import (
+	"bytes"
+	"car-sales-system/internal/db"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// doJSON выполняет запрос к открытому API и декодирует ответ в out; возвращает код ответа
+func doJSON(t *testing.T, method, url, token string, in, out any) int {
+	t.Helper()
+
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("%s %s: ответ не JSON: %v", method, url, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestPublicCatalog(t *testing.T) {
+	store, url, first := newTestServer(t)
+	first.VIN = "JTDKB20U093000001"
+	if _, err := store.Cars.Update(first, 0); err != nil {
+		t.Fatal(err)
+	}
+	for _, car := range []db.Car{
+		{Brand: "BMW", Model: "X5", Year: 2021, Color: "White", Price: 50000},
+		{Brand: "Audi", Model: "A4", Year: 2019, Color: "Red", Price: 30000},
+		{Brand: "Lada", Model: "Vesta", Year: 2022, Color: "Grey", Price: 12000},
+	} {
+		if err := store.Cars.Create(&car); err != nil {
+			t.Fatal(err)
+		}
+	}
+	withdrawn := db.Car{Brand: "Kia", Model: "Rio", Year: 2018, Color: "Blue", Price: 9000}
+	if err := store.Cars.Create(&withdrawn); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Cars.SetStatus(withdrawn.ID, db.StatusWithdrawn); err != nil {
+		t.Fatal(err)
+	}
+
+	var page carPage
+	if status := doJSON(t, "GET", url+"/v1/cars?sort=price_asc&per_page=2&page=2", "", nil, &page); status != http.StatusOK {
+		t.Fatalf("каталог: код %d", status)
+	}
+	if page.Total != 4 || len(page.Items) != 2 || page.Items[0].Brand != "Audi" || page.Items[1].Brand != "BMW" {
+		t.Errorf("вторая страница по возрастанию цены: %+v", page)
+	}
+
+	page = carPage{}
+	doJSON(t, "GET", url+"/v1/cars?brand=bmw&year_from=2020", "", nil, &page)
+	if page.Total != 1 || page.Items[0].Model != "X5" {
+		t.Errorf("фильтр по марке и году: %+v", page)
+	}
+	page = carPage{}
+	doJSON(t, "GET", url+"/v1/cars?page=9", "", nil, &page)
+	if page.Total != 4 || page.Items == nil || len(page.Items) != 0 {
+		t.Errorf("страница за концом каталога: %+v", page)
+	}
+
+	for _, query := range []string{"year_from=давно", "price_to=-5", "sort=random", "per_page=1000", "page=0"} {
+		var e errorBody
+		if status := doJSON(t, "GET", url+"/v1/cars?"+query, "", nil, &e); status != http.StatusBadRequest || e.Code != "bad_request" {
+			t.Errorf("%s: код %d, ошибка %+v", query, status, e)
+		}
+	}
+
+	var car publicCar
+	if status := doJSON(t, "GET", url+"/v1/cars/"+strconv.Itoa(first.ID), "", nil, &car); status != http.StatusOK {
+		t.Fatalf("автомобиль: код %d", status)
+	}
+	if car.PriceDetails == nil || car.PriceDetails.ListedPrice != first.Price {
+		t.Errorf("состав цены: %+v", car.PriceDetails)
+	}
+	var raw map[string]any
+	doJSON(t, "GET", url+"/v1/cars/"+strconv.Itoa(first.ID), "", nil, &raw)
+	if _, ok := raw["vin"]; ok {
+		t.Errorf("VIN передан в открытом API: %v", raw)
+	}
+	var e errorBody
+	if status := doJSON(t, "GET", url+"/v1/cars/"+strconv.Itoa(withdrawn.ID), "", nil, &e); status != http.StatusNotFound || e.Code != "not_found" {
+		t.Errorf("снятый с продажи автомобиль: код %d, ошибка %+v", status, e)
+	}
+}
+
+func TestPublicOrders(t *testing.T) {
+	store, url, car := newTestServer(t)
+	for _, login := range []string{"ivanov", "petrov"} {
+		client := db.Client{Name: "Иван", LastName: "Иванов", Phone: "123", Login: login, Password: "password"}
+		if err := store.Clients.Create(&client); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var e errorBody
+	if status := doJSON(t, "POST", url+"/v1/orders", "", orderRequest{CarID: car.ID, Price: car.Price}, &e); status != http.StatusUnauthorized || e.Code != "unauthorized" {
+		t.Errorf("заказ без токена: код %d, ошибка %+v", status, e)
+	}
+	if status := doJSON(t, "POST", url+"/v1/tokens", "", tokenRequest{Login: "ivanov", Password: "wrong"}, &e); status != http.StatusUnauthorized || e.Code != "invalid_credentials" {
+		t.Errorf("неверный пароль: код %d, ошибка %+v", status, e)
+	}
+
+	token := func(login string) string {
+		var tok tokenResponse
+		if status := doJSON(t, "POST", url+"/v1/tokens", "", tokenRequest{Login: login, Password: "password"}, &tok); status != http.StatusCreated || tok.Token == "" {
+			t.Fatalf("токен %s: код %d, ответ %+v", login, status, tok)
+		}
+		return tok.Token
+	}
+	buyer, other := token("ivanov"), token("petrov")
+
+	if status := doJSON(t, "POST", url+"/v1/orders", buyer, orderRequest{CarID: car.ID}, &e); status != http.StatusBadRequest || e.Code != "bad_request" {
+		t.Errorf("заказ без цены: код %d, ошибка %+v", status, e)
+	}
+	if status := doJSON(t, "POST", url+"/v1/orders", buyer, orderRequest{CarID: car.ID, Price: car.Price - 1000}, &e); status != http.StatusConflict || e.Code != "price_changed" {
+		t.Errorf("заказ по устаревшей цене: код %d, ошибка %+v", status, e)
+	}
+
+	var order publicOrder
+	if status := doJSON(t, "POST", url+"/v1/orders", buyer, orderRequest{CarID: car.ID, Price: car.Price}, &order); status != http.StatusCreated {
+		t.Fatalf("заказ: код %d", status)
+	}
+	if order.Status != db.CheckPending || order.CarID != car.ID || order.Brand != car.Brand || order.Price != car.Price || order.CreatedAt == nil {
+		t.Errorf("заказ: %+v", order)
+	}
+	if status := doJSON(t, "POST", url+"/v1/orders", other, orderRequest{CarID: car.ID, Price: car.Price}, &e); status != http.StatusConflict || e.Code != "car_reserved" {
+		t.Errorf("заказ забронированного автомобиля: код %d, ошибка %+v", status, e)
+	}
+	if status := doJSON(t, "POST", url+"/v1/orders", other, orderRequest{CarID: car.ID + 100, Price: car.Price}, &e); status != http.StatusNotFound || e.Code != "car_not_found" {
+		t.Errorf("заказ несуществующего автомобиля: код %d, ошибка %+v", status, e)
+	}
+
+	var orders []publicOrder
+	doJSON(t, "GET", url+"/v1/clients/me/orders", buyer, nil, &orders)
+	if len(orders) != 1 || orders[0].ID != order.ID || orders[0].Status != db.CheckPending {
+		t.Errorf("заказы покупателя: %+v", orders)
+	}
+	orders = nil
+	doJSON(t, "GET", url+"/v1/clients/me/orders", other, nil, &orders)
+	if len(orders) != 0 {
+		t.Errorf("заказы другого клиента: %+v", orders)
+	}
+
+	var login loginResponse
+	doJSON(t, "POST", url+"/api/login", "", loginRequest{Role: "admin", Login: "admin", Password: "secret"}, &login)
+	if status := doJSON(t, "GET", url+"/v1/clients/me/orders", login.Token, nil, &e); status != http.StatusForbidden {
+		t.Errorf("заказы по токену администратора: код %d", status)
+	}
+}
+
+func TestPublicSpecServed(t *testing.T) {
+	ts := httptest.NewServer(NewServer(db.NewMemoryStore()))
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/v1/openapi.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	spec, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(string(spec), "openapi: 3") {
+		t.Fatalf("описание API: код %d", resp.StatusCode)
+	}
+	for _, path := range []string{"/tokens:", "/cars:", "/cars/{id}:", "/orders:", "/clients/me/orders:"} {
+		if !strings.Contains(string(spec), "\n  "+path) {
+			t.Errorf("в описании API нет пути %s", path)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package remote
+
+import (
+	"bytes"
+	"car-sales-system/internal/auth"
+	"car-sales-system/internal/db"
+	"car-sales-system/internal/photo"
+	"errors"
+	"image"
+	"image/png"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTestServer запускает сервер поверх хранилища в памяти с администратором admin/secret
+// и автомобилем; возвращает хранилище сервера, его адрес и автомобиль
+func newTestServer(t *testing.T) (*db.Store, string, db.Car) {
+	t.Helper()
+
+	store := db.NewMemoryStore()
+	admin := db.Admin{Name: "Михаил", LastName: "Филин", Login: "admin", Password: "secret"}
+	if err := store.Admins.Create(&admin); err != nil {
+		t.Fatal(err)
+	}
+	car := db.Car{Brand: "Toyota", Model: "Camry", Year: 2020, Color: "Black", Price: 24000}
+	if err := store.Cars.Create(&car); err != nil {
+		t.Fatal(err)
+	}
+
+	ts := httptest.NewServer(NewServer(store))
+	t.Cleanup(ts.Close)
+	return store, ts.URL, car
+}
+
+func newTestClient(t *testing.T, url string) *Client {
+	t.Helper()
+
+	c, err := NewClient(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// registerAndLogin регистрирует клиента через сервер и входит им
+func registerAndLogin(t *testing.T, url, login string) (*Client, *db.Client) {
+	t.Helper()
+
+	c := newTestClient(t, url)
+	client := db.Client{Name: "Иван", LastName: "Иванов", Phone: "123", Login: login, Password: "password"}
+	if err := c.RegisterClient(&client); err != nil {
+		t.Fatal(err)
+	}
+	signedIn, err := c.LoginClient(login, "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signedIn.ID != client.ID || signedIn.Password != "" {
+		t.Fatalf("вход клиента: %+v, ожидался ID %d без пароля", signedIn, client.ID)
+	}
+	return c, signedIn
+}
+
+func TestRemotePurchaseFlow(t *testing.T) {
+	serverStore, url, car := newTestServer(t)
+
+	buyer, client := registerAndLogin(t, url, "ivanov")
+	other, otherClient := registerAndLogin(t, url, "petrov")
+
+	check, err := buyer.Store().Purchases.Purchase(client.ID, car.ID, car.Price)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if check.Status != db.CheckPending || check.Price != car.Price {
+		t.Fatalf("заказ: %+v", check)
+	}
+	if _, err := other.Store().Purchases.Purchase(otherClient.ID, car.ID, car.Price); !errors.Is(err, db.ErrCarReserved) {
+		t.Fatalf("покупка забронированного автомобиля: ожидалась ErrCarReserved, получено %v", err)
+	}
+
+	admin := newTestClient(t, url)
+	signedIn, err := admin.LoginAdmin("admin", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	approved, err := admin.Store().Purchases.Approve(check.ID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if approved.Status != db.CheckApproved || approved.AdminID != signedIn.ID {
+		t.Errorf("подтверждённый заказ: %+v, ожидался администратор %d", approved, signedIn.ID)
+	}
+	if _, err := admin.Store().Purchases.Reject(check.ID, 0); !errors.Is(err, db.ErrCheckNotPending) {
+		t.Errorf("повторное решение: ожидалась ErrCheckNotPending, получено %v", err)
+	}
+
+	stored, err := serverStore.Cars.Get(car.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Status != db.StatusSold {
+		t.Errorf("статус автомобиля на сервере: %s, ожидался %s", stored.Status, db.StatusSold)
+	}
+	purchases, err := buyer.Store().Checks.ListByClient(client.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(purchases) != 1 || purchases[0].Status != db.CheckApproved {
+		t.Errorf("история покупок: %+v", purchases)
+	}
+}
+
+func TestRemoteAccessControl(t *testing.T) {
+	_, url, car := newTestServer(t)
+
+	anonymous := newTestClient(t, url)
+	if _, err := anonymous.Store().Cars.Get(car.ID); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("запрос без входа: ожидалась ErrUnauthorized, получено %v", err)
+	}
+	if _, err := anonymous.LoginAdmin("admin", "wrong"); !errors.Is(err, auth.ErrInvalidCredentials) {
+		t.Errorf("неверный пароль: ожидалась ErrInvalidCredentials, получено %v", err)
+	}
+
+	buyer, client := registerAndLogin(t, url, "ivanov")
+	_, otherClient := registerAndLogin(t, url, "petrov")
+	store := buyer.Store()
+	if _, err := store.Clients.List(); !errors.Is(err, ErrForbidden) {
+		t.Errorf("список клиентов от клиента: ожидалась ErrForbidden, получено %v", err)
+	}
+	if err := store.Cars.SetStatus(car.ID, db.StatusWithdrawn); !errors.Is(err, ErrForbidden) {
+		t.Errorf("смена статуса клиентом: ожидалась ErrForbidden, получено %v", err)
+	}
+	if _, err := store.Checks.ListByClient(otherClient.ID); !errors.Is(err, ErrForbidden) {
+		t.Errorf("чужая история покупок: ожидалась ErrForbidden, получено %v", err)
+	}
+	if _, err := store.Purchases.Purchase(otherClient.ID, car.ID, car.Price); !errors.Is(err, ErrForbidden) {
+		t.Errorf("покупка от имени другого клиента: ожидалась ErrForbidden, получено %v", err)
+	}
+	if _, err := store.Checks.ListByClient(client.ID); err != nil {
+		t.Errorf("своя история покупок: %v", err)
+	}
+
+	admin := newTestClient(t, url)
+	if _, err := admin.LoginAdmin("admin", "secret"); err != nil {
+		t.Fatal(err)
+	}
+	clients, err := admin.Store().Clients.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range clients {
+		if c.Password != "" {
+			t.Errorf("пароль клиента %s передан по сети", c.Login)
+		}
+	}
+
+	// Повторный вход, как при разблокировке сессии, отзывает прежний токен
+	admin.mu.Lock()
+	previous := admin.token
+	admin.mu.Unlock()
+	if _, err := admin.LoginAdmin("admin", "secret"); err != nil {
+		t.Fatal(err)
+	}
+	if err := admin.send(previous, "GET", "/api/clients", nil, nil); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("прежний токен после повторного входа: ожидалась ErrUnauthorized, получено %v", err)
+	}
+	if _, err := admin.Store().Clients.List(); err != nil {
+		t.Errorf("запрос после повторного входа: %v", err)
+	}
+
+	if err := admin.Logout(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := admin.Store().Clients.List(); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("запрос после выхода: ожидалась ErrUnauthorized, получено %v", err)
+	}
+}
+
+func TestRemotePasswordsHashedByServer(t *testing.T) {
+	serverStore, url, _ := newTestServer(t)
+
+	admin := newTestClient(t, url)
+	if _, err := admin.LoginAdmin("admin", "secret"); err != nil {
+		t.Fatal(err)
+	}
+	hash, err := auth.HashPassword("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := db.Admin{Name: "Анна", LastName: "Орлова", Login: "orlova", Password: hash}
+	if err := admin.Store().Admins.Create(&created); !errors.Is(err, ErrHashedPassword) {
+		t.Errorf("создание с хэшем: ожидалась ErrHashedPassword, получено %v", err)
+	}
+
+	// Сервер хэширует пароль, даже если тот похож на bcrypt-хэш: войти можно только им самим
+	body := db.Admin{Name: "Анна", LastName: "Орлова", Login: "orlova", Password: hash}
+	if err := admin.call("POST", "/api/admins", body, &created); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := serverStore.Admins.GetByLogin("orlova")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Password == hash || !auth.IsHashed(stored.Password) {
+		t.Errorf("пароль сохранён без хэширования: %q", stored.Password)
+	}
+	if _, err := newTestClient(t, url).LoginAdmin("orlova", "password"); !errors.Is(err, auth.ErrInvalidCredentials) {
+		t.Errorf("вход паролем, чей хэш передан: ожидалась ErrInvalidCredentials, получено %v", err)
+	}
+	if _, err := newTestClient(t, url).LoginAdmin("orlova", hash); err != nil {
+		t.Errorf("вход переданной строкой: %v", err)
+	}
+
+	if err := admin.Store().Admins.UpdatePassword(created.ID, "newsecret"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newTestClient(t, url).LoginAdmin("orlova", "newsecret"); err != nil {
+		t.Errorf("вход после смены пароля: %v", err)
+	}
+}
+
+func TestRemoteCarsAndPhotos(t *testing.T) {
+	_, url, car := newTestServer(t)
+
+	admin := newTestClient(t, url)
+	if _, err := admin.LoginAdmin("admin", "secret"); err != nil {
+		t.Fatal(err)
+	}
+	store := admin.Store()
+
+	if _, err := store.Cars.Get(car.ID + 100); !errors.Is(err, db.ErrNotFound) {
+		t.Errorf("несуществующий автомобиль: ожидалась ErrNotFound, получено %v", err)
+	}
+	invalid := db.Car{Brand: "Lada", Model: "Vesta", Year: 2020, Color: "White", Price: -1}
+	if err := store.Cars.Create(&invalid); !errors.Is(err, db.ErrInvalidCar) || err.Error() == db.ErrInvalidCar.Error() {
+		t.Errorf("неверный автомобиль: ожидалась ErrInvalidCar с подробностями, получено %v", err)
+	}
+
+	updated := car
+	updated.Price = 22000
+	changes, err := store.Cars.Update(updated, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].Field != db.FieldPrice || changes[0].AdminID == 0 {
+		t.Errorf("изменения: %+v, ожидалась цена от имени администратора", changes)
+	}
+
+	if err := store.Photos.Add(&db.CarPhoto{CarID: car.ID, ContentType: "image/jpeg", Data: []byte{0xff, 0xd8, 0x00, 0x01}}); !errors.Is(err, photo.ErrUnsupported) {
+		t.Errorf("не изображение: ожидалась photo.ErrUnsupported, получено %v", err)
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 4, 3))); err != nil {
+		t.Fatal(err)
+	}
+	// Тип и миниатюра рабочего места заменяются определёнными сервером
+	added := db.CarPhoto{CarID: car.ID, ContentType: "image/jpeg", Data: buf.Bytes(), Thumbnail: []byte("forged")}
+	if err := store.Photos.Add(&added); err != nil {
+		t.Fatal(err)
+	}
+	if added.ID == 0 || added.Position != 1 || added.ContentType != "image/png" || bytes.Equal(added.Thumbnail, []byte("forged")) {
+		t.Fatalf("добавленная фотография: ID %d, позиция %d, тип %s", added.ID, added.Position, added.ContentType)
+	}
+	got, err := store.Photos.Get(added.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.Data, added.Data) || !bytes.Equal(got.Thumbnail, added.Thumbnail) {
+		t.Errorf("фотография искажена при передаче: %v, %v", got.Data, got.Thumbnail)
+	}
+	covers, err := store.Photos.Covers([]int{car.ID, car.ID + 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(covers) != 1 || covers[car.ID].ID != added.ID {
+		t.Errorf("обложки: %+v", covers)
+	}
+}
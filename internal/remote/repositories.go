@@ -0,0 +1,337 @@
+package remote
+
+import (
+	"car-sales-system/internal/auth"
+	"car-sales-system/internal/db"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Репозитории поверх сервера. Они ведут себя так же, как репозитории над базой, с тремя
+// отличиями: пароли в ответах не передаются; при создании пользователя и смене пароля
+// передаётся пароль в открытом виде, а хэширует его сервер; изменения автомобилей и решения
+// по заказам записываются от имени вошедшего администратора, а не переданного adminID.
+
+type carRepository struct{ c *Client }
+
+func (r carRepository) Create(car *db.Car) error {
+	return r.c.call(http.MethodPost, "/api/cars", car, car)
+}
+
+func (r carRepository) CreateBatch(cars []db.Car) error {
+	var created []db.Car
+	if err := r.c.call(http.MethodPost, "/api/cars/batch", cars, &created); err != nil {
+		return err
+	}
+	copy(cars, created)
+	return nil
+}
+
+func (r carRepository) Get(id int) (*db.Car, error) {
+	var car db.Car
+	if err := r.c.call(http.MethodGet, fmt.Sprintf("/api/cars/%d", id), nil, &car); err != nil {
+		return nil, err
+	}
+	return &car, nil
+}
+
+func (r carRepository) List(statuses ...db.CarStatus) ([]db.Car, error) {
+	query := url.Values{}
+	for _, s := range statuses {
+		query.Add("status", string(s))
+	}
+	var cars []db.Car
+	err := r.c.call(http.MethodGet, withQuery("/api/cars", query), nil, &cars)
+	return cars, err
+}
+
+func (r carRepository) Search(filter db.CarFilter) ([]db.Car, error) {
+	var cars []db.Car
+	err := r.c.call(http.MethodPost, "/api/cars/search", filter, &cars)
+	return cars, err
+}
+
+func (r carRepository) Count(filter db.CarFilter) (int, error) {
+	var count int
+	err := r.c.call(http.MethodPost, "/api/cars/count", filter, &count)
+	return count, err
+}
+
+func (r carRepository) SetStatus(id int, to db.CarStatus) error {
+	return r.c.call(http.MethodPut, fmt.Sprintf("/api/cars/%d/status", id), statusRequest{Status: to}, nil)
+}
+
+func (r carRepository) StatusHistory(id int) ([]db.CarStatusChange, error) {
+	var history []db.CarStatusChange
+	err := r.c.call(http.MethodGet, fmt.Sprintf("/api/cars/%d/status-history", id), nil, &history)
+	return history, err
+}
+
+func (r carRepository) Update(car db.Car, adminID int) ([]db.CarFieldChange, error) {
+	var changes []db.CarFieldChange
+	err := r.c.call(http.MethodPut, fmt.Sprintf("/api/cars/%d", car.ID), car, &changes)
+	return changes, err
+}
+
+func (r carRepository) ChangeHistory(id int) ([]db.CarFieldChange, error) {
+	var history []db.CarFieldChange
+	err := r.c.call(http.MethodGet, fmt.Sprintf("/api/cars/%d/changes", id), nil, &history)
+	return history, err
+}
+
+func (r carRepository) PriceHistory(id int) ([]db.CarPriceChange, error) {
+	var history []db.CarPriceChange
+	err := r.c.call(http.MethodGet, fmt.Sprintf("/api/cars/%d/prices", id), nil, &history)
+	return history, err
+}
+
+// plainPassword не даёт отправить на сервер уже готовый хэш: сервер хэширует
+// любой полученный пароль, и хэш превратился бы в пароль для входа
+func plainPassword(password string) error {
+	if auth.IsHashed(password) {
+		return ErrHashedPassword
+	}
+	return nil
+}
+
+type clientRepository struct{ c *Client }
+
+func (r clientRepository) Create(client *db.Client) error {
+	if err := plainPassword(client.Password); err != nil {
+		return err
+	}
+	var created db.Client
+	if err := r.c.call(http.MethodPost, "/api/clients", client, &created); err != nil {
+		return err
+	}
+	client.ID = created.ID
+	return nil
+}
+
+func (r clientRepository) GetByLogin(login string) (*db.Client, error) {
+	var client db.Client
+	if err := r.c.call(http.MethodGet, withQuery("/api/clients/by-login", url.Values{"login": {login}}), nil, &client); err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
+
+func (r clientRepository) UpdatePassword(id int, password string) error {
+	if err := plainPassword(password); err != nil {
+		return err
+	}
+	return r.c.call(http.MethodPut, fmt.Sprintf("/api/clients/%d/password", id), passwordRequest{Password: password}, nil)
+}
+
+func (r clientRepository) List() ([]db.Client, error) {
+	var clients []db.Client
+	err := r.c.call(http.MethodGet, "/api/clients", nil, &clients)
+	return clients, err
+}
+
+func (r clientRepository) Delete(id int) error {
+	return r.c.call(http.MethodDelete, fmt.Sprintf("/api/clients/%d", id), nil, nil)
+}
+
+type adminRepository struct{ c *Client }
+
+func (r adminRepository) Create(admin *db.Admin) error {
+	if err := plainPassword(admin.Password); err != nil {
+		return err
+	}
+	var created db.Admin
+	if err := r.c.call(http.MethodPost, "/api/admins", admin, &created); err != nil {
+		return err
+	}
+	admin.ID = created.ID
+	return nil
+}
+
+func (r adminRepository) GetByLogin(login string) (*db.Admin, error) {
+	var admin db.Admin
+	if err := r.c.call(http.MethodGet, withQuery("/api/admins/by-login", url.Values{"login": {login}}), nil, &admin); err != nil {
+		return nil, err
+	}
+	return &admin, nil
+}
+
+func (r adminRepository) UpdatePassword(id int, password string) error {
+	if err := plainPassword(password); err != nil {
+		return err
+	}
+	return r.c.call(http.MethodPut, fmt.Sprintf("/api/admins/%d/password", id), passwordRequest{Password: password}, nil)
+}
+
+func (r adminRepository) List() ([]db.Admin, error) {
+	var admins []db.Admin
+	err := r.c.call(http.MethodGet, "/api/admins", nil, &admins)
+	return admins, err
+}
+
+type checkRepository struct{ c *Client }
+
+func (r checkRepository) Create(check *db.Check) error {
+	return r.c.call(http.MethodPost, "/api/checks", check, check)
+}
+
+func (r checkRepository) Get(id int) (*db.Check, error) {
+	var check db.Check
+	if err := r.c.call(http.MethodGet, fmt.Sprintf("/api/checks/%d", id), nil, &check); err != nil {
+		return nil, err
+	}
+	return &check, nil
+}
+
+func (r checkRepository) List(filter db.CheckFilter) ([]db.Check, error) {
+	var checks []db.Check
+	err := r.c.call(http.MethodPost, "/api/checks/search", filter, &checks)
+	return checks, err
+}
+
+func (r checkRepository) ListByClient(clientID int) ([]db.Purchase, error) {
+	var purchases []db.Purchase
+	err := r.c.call(http.MethodGet, fmt.Sprintf("/api/clients/%d/purchases", clientID), nil, &purchases)
+	return purchases, err
+}
+
+func (r checkRepository) ListPending() ([]db.PendingOrder, error) {
+	var orders []db.PendingOrder
+	err := r.c.call(http.MethodGet, "/api/checks/pending", nil, &orders)
+	return orders, err
+}
+
+func (r checkRepository) SalesSummary(from, to time.Time) (db.SalesSummary, error) {
+	query := url.Values{}
+	if !from.IsZero() {
+		query.Set("from", from.Format(time.RFC3339Nano))
+	}
+	if !to.IsZero() {
+		query.Set("to", to.Format(time.RFC3339Nano))
+	}
+	var summary db.SalesSummary
+	err := r.c.call(http.MethodGet, withQuery("/api/sales/summary", query), nil, &summary)
+	return summary, err
+}
+
+func (r checkRepository) ListSales(includeArchived bool) ([]db.Sale, error) {
+	query := url.Values{}
+	if includeArchived {
+		query.Set("archived", "true")
+	}
+	var sales []db.Sale
+	err := r.c.call(http.MethodGet, withQuery("/api/sales", query), nil, &sales)
+	return sales, err
+}
+
+type photoRepository struct{ c *Client }
+
+// Add отправляет на сервер только файл; формат и миниатюру сервер определяет сам
+func (r photoRepository) Add(photo *db.CarPhoto) error {
+	var created db.CarPhoto
+	upload := db.CarPhoto{CarID: photo.CarID, Data: photo.Data}
+	if err := r.c.call(http.MethodPost, "/api/photos", upload, &created); err != nil {
+		return err
+	}
+	photo.ID, photo.Position, photo.CreatedAt = created.ID, created.Position, created.CreatedAt
+	photo.ContentType, photo.Thumbnail = created.ContentType, created.Thumbnail
+	return nil
+}
+
+func (r photoRepository) Get(id int) (*db.CarPhoto, error) {
+	var photo db.CarPhoto
+	if err := r.c.call(http.MethodGet, fmt.Sprintf("/api/photos/%d", id), nil, &photo); err != nil {
+		return nil, err
+	}
+	return &photo, nil
+}
+
+func (r photoRepository) List(carID int) ([]db.CarPhoto, error) {
+	var photos []db.CarPhoto
+	err := r.c.call(http.MethodGet, fmt.Sprintf("/api/cars/%d/photos", carID), nil, &photos)
+	return photos, err
+}
+
+func (r photoRepository) Delete(id int) error {
+	return r.c.call(http.MethodDelete, fmt.Sprintf("/api/photos/%d", id), nil, nil)
+}
+
+func (r photoRepository) Covers(carIDs []int) (map[int]db.CarPhoto, error) {
+	covers := make(map[int]db.CarPhoto)
+	if len(carIDs) == 0 {
+		return covers, nil
+	}
+	query := url.Values{}
+	for _, id := range carIDs {
+		query.Add("car", strconv.Itoa(id))
+	}
+	err := r.c.call(http.MethodGet, withQuery("/api/photos/covers", query), nil, &covers)
+	return covers, err
+}
+
+type purchaseService struct{ c *Client }
+
+func (s purchaseService) Purchase(clientID, carID int, price float64) (*db.Check, error) {
+	var check db.Check
+	if err := s.c.call(http.MethodPost, "/api/purchases", purchaseRequest{ClientID: clientID, CarID: carID, Price: price}, &check); err != nil {
+		return nil, err
+	}
+	return &check, nil
+}
+
+func (s purchaseService) Approve(checkID, adminID int) (*db.Check, error) {
+	return s.decide(checkID, "approve")
+}
+
+func (s purchaseService) Reject(checkID, adminID int) (*db.Check, error) {
+	return s.decide(checkID, "reject")
+}
+
+func (s purchaseService) decide(checkID int, decision string) (*db.Check, error) {
+	var check db.Check
+	if err := s.c.call(http.MethodPost, fmt.Sprintf("/api/checks/%d/%s", checkID, decision), nil, &check); err != nil {
+		return nil, err
+	}
+	return &check, nil
+}
+
+type dictionaryRepository struct{ c *Client }
+
+func (r dictionaryRepository) List(kind db.DictionaryKind, brandID int) ([]db.DictionaryEntry, error) {
+	query := url.Values{}
+	if brandID != 0 {
+		query.Set("brand", strconv.Itoa(brandID))
+	}
+	var entries []db.DictionaryEntry
+	err := r.c.call(http.MethodGet, withQuery(dictionaryPath(kind), query), nil, &entries)
+	return entries, err
+}
+
+func (r dictionaryRepository) Add(entry *db.DictionaryEntry) error {
+	return r.c.call(http.MethodPost, dictionaryPath(entry.Kind), entry, entry)
+}
+
+func (r dictionaryRepository) Rename(kind db.DictionaryKind, id int, name string) error {
+	return r.c.call(http.MethodPut, fmt.Sprintf("%s/%d", dictionaryPath(kind), id), renameRequest{Name: name}, nil)
+}
+
+func (r dictionaryRepository) Merge(kind db.DictionaryKind, fromID, intoID int) error {
+	return r.c.call(http.MethodPost, fmt.Sprintf("%s/%d/merge", dictionaryPath(kind), fromID), mergeRequest{Into: intoID}, nil)
+}
+
+func (r dictionaryRepository) Delete(kind db.DictionaryKind, id int) error {
+	return r.c.call(http.MethodDelete, fmt.Sprintf("%s/%d", dictionaryPath(kind), id), nil, nil)
+}
+
+func dictionaryPath(kind db.DictionaryKind) string {
+	return "/api/dictionaries/" + url.PathEscape(string(kind))
+}
+
+func withQuery(path string, query url.Values) string {
+	if len(query) == 0 {
+		return path
+	}
+	return path + "?" + query.Encode()
+}
@@ -0,0 +1,446 @@
+package remote
+
+import (
+	"car-sales-system/internal/auth"
+	"car-sales-system/internal/db"
+	"car-sales-system/internal/photo"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// Тела запросов и ответов, для которых нет модели в пакете db
+type (
+	loginRequest struct {
+		Role     auth.Role
+		Login    string
+		Password string
+	}
+	loginResponse struct {
+		Token  string
+		Client *db.Client `json:",omitempty"`
+		Admin  *db.Admin  `json:",omitempty"`
+	}
+	statusRequest   struct{ Status db.CarStatus }
+	passwordRequest struct{ Password string }
+	purchaseRequest struct {
+		ClientID, CarID int
+		Price           float64
+	}
+	renameRequest struct{ Name string }
+	mergeRequest  struct{ Into int }
+)
+
+// routes регистрирует операции сервера. Каждой операции репозитория соответствует один запрос.
+// Пароли в ответах никогда не передаются, а присланные в открытом виде хэшируются на сервере.
+func (s *Server) routes() {
+	// Вход и регистрация
+	s.handle("POST /api/login", public, s.login)
+	s.handle("POST /api/logout", signedIn, func(r *http.Request, _ *sessionUser) (any, error) {
+		s.sessions.close(bearerToken(r))
+		return nil, nil
+	})
+	s.handle("POST /api/register", public, func(r *http.Request, _ *sessionUser) (any, error) {
+		var client db.Client
+		if err := decode(r, &client); err != nil {
+			return nil, err
+		}
+		if err := s.auth.RegisterClient(&client); err != nil {
+			return nil, err
+		}
+		client.Password = ""
+		return client, nil
+	})
+
+	// Автомобили
+	s.handle("GET /api/cars", signedIn, func(r *http.Request, _ *sessionUser) (any, error) {
+		var statuses []db.CarStatus
+		for _, v := range r.URL.Query()["status"] {
+			statuses = append(statuses, db.CarStatus(v))
+		}
+		return s.store.Cars.List(statuses...)
+	})
+	s.handle("POST /api/cars", adminOnly, func(r *http.Request, _ *sessionUser) (any, error) {
+		var car db.Car
+		if err := decode(r, &car); err != nil {
+			return nil, err
+		}
+		if err := s.store.Cars.Create(&car); err != nil {
+			return nil, err
+		}
+		return car, nil
+	})
+	s.handle("POST /api/cars/batch", adminOnly, func(r *http.Request, _ *sessionUser) (any, error) {
+		var cars []db.Car
+		if err := decode(r, &cars); err != nil {
+			return nil, err
+		}
+		if err := s.store.Cars.CreateBatch(cars); err != nil {
+			return nil, err
+		}
+		return cars, nil
+	})
+	s.handle("POST /api/cars/search", signedIn, func(r *http.Request, _ *sessionUser) (any, error) {
+		var filter db.CarFilter
+		if err := decode(r, &filter); err != nil {
+			return nil, err
+		}
+		return s.store.Cars.Search(filter)
+	})
+	s.handle("POST /api/cars/count", signedIn, func(r *http.Request, _ *sessionUser) (any, error) {
+		var filter db.CarFilter
+		if err := decode(r, &filter); err != nil {
+			return nil, err
+		}
+		return s.store.Cars.Count(filter)
+	})
+	s.handle("GET /api/cars/{id}", signedIn, withID(s.store.Cars.Get))
+	s.handle("PUT /api/cars/{id}", adminOnly, func(r *http.Request, user *sessionUser) (any, error) {
+		id, err := pathID(r, "id")
+		if err != nil {
+			return nil, err
+		}
+		var car db.Car
+		if err := decode(r, &car); err != nil {
+			return nil, err
+		}
+		car.ID = id
+		return s.store.Cars.Update(car, user.UserID)
+	})
+	s.handle("PUT /api/cars/{id}/status", adminOnly, func(r *http.Request, _ *sessionUser) (any, error) {
+		id, err := pathID(r, "id")
+		if err != nil {
+			return nil, err
+		}
+		var req statusRequest
+		if err := decode(r, &req); err != nil {
+			return nil, err
+		}
+		return nil, s.store.Cars.SetStatus(id, req.Status)
+	})
+	s.handle("GET /api/cars/{id}/status-history", signedIn, withID(s.store.Cars.StatusHistory))
+	s.handle("GET /api/cars/{id}/changes", adminOnly, withID(s.store.Cars.ChangeHistory))
+	s.handle("GET /api/cars/{id}/prices", signedIn, withID(s.store.Cars.PriceHistory))
+	s.handle("GET /api/cars/{id}/photos", signedIn, withID(s.store.Photos.List))
+
+	// Клиенты
+	s.handle("GET /api/clients", adminOnly, func(*http.Request, *sessionUser) (any, error) {
+		clients, err := s.store.Clients.List()
+		for i := range clients {
+			clients[i].Password = ""
+		}
+		return clients, err
+	})
+	s.handle("POST /api/clients", adminOnly, func(r *http.Request, _ *sessionUser) (any, error) {
+		var client db.Client
+		if err := decode(r, &client); err != nil {
+			return nil, err
+		}
+		var err error
+		if client.Password, err = auth.HashPassword(client.Password); err != nil {
+			return nil, err
+		}
+		if err := s.store.Clients.Create(&client); err != nil {
+			return nil, err
+		}
+		client.Password = ""
+		return client, nil
+	})
+	s.handle("GET /api/clients/by-login", adminOnly, func(r *http.Request, _ *sessionUser) (any, error) {
+		client, err := s.store.Clients.GetByLogin(r.URL.Query().Get("login"))
+		if err != nil {
+			return nil, err
+		}
+		client.Password = ""
+		return client, nil
+	})
+	s.handle("PUT /api/clients/{id}/password", signedIn, func(r *http.Request, user *sessionUser) (any, error) {
+		id, err := pathID(r, "id")
+		if err != nil {
+			return nil, err
+		}
+		if err := selfOrAdmin(user, id); err != nil {
+			return nil, err
+		}
+		hash, err := decodePassword(r)
+		if err != nil {
+			return nil, err
+		}
+		return nil, s.store.Clients.UpdatePassword(id, hash)
+	})
+	s.handle("DELETE /api/clients/{id}", adminOnly, func(r *http.Request, _ *sessionUser) (any, error) {
+		id, err := pathID(r, "id")
+		if err != nil {
+			return nil, err
+		}
+		return nil, s.store.Clients.Delete(id)
+	})
+	s.handle("GET /api/clients/{id}/purchases", signedIn, func(r *http.Request, user *sessionUser) (any, error) {
+		id, err := pathID(r, "id")
+		if err != nil {
+			return nil, err
+		}
+		if err := selfOrAdmin(user, id); err != nil {
+			return nil, err
+		}
+		return s.store.Checks.ListByClient(id)
+	})
+
+	// Администраторы
+	s.handle("GET /api/admins", adminOnly, func(*http.Request, *sessionUser) (any, error) {
+		return s.store.Admins.List()
+	})
+	s.handle("POST /api/admins", adminOnly, func(r *http.Request, _ *sessionUser) (any, error) {
+		var admin db.Admin
+		if err := decode(r, &admin); err != nil {
+			return nil, err
+		}
+		var err error
+		if admin.Password, err = auth.HashPassword(admin.Password); err != nil {
+			return nil, err
+		}
+		if err := s.store.Admins.Create(&admin); err != nil {
+			return nil, err
+		}
+		admin.Password = ""
+		return admin, nil
+	})
+	s.handle("GET /api/admins/by-login", adminOnly, func(r *http.Request, _ *sessionUser) (any, error) {
+		admin, err := s.store.Admins.GetByLogin(r.URL.Query().Get("login"))
+		if err != nil {
+			return nil, err
+		}
+		admin.Password = ""
+		return admin, nil
+	})
+	s.handle("PUT /api/admins/{id}/password", adminOnly, func(r *http.Request, _ *sessionUser) (any, error) {
+		id, err := pathID(r, "id")
+		if err != nil {
+			return nil, err
+		}
+		hash, err := decodePassword(r)
+		if err != nil {
+			return nil, err
+		}
+		return nil, s.store.Admins.UpdatePassword(id, hash)
+	})
+
+	// Чеки и продажи
+	s.handle("POST /api/checks", adminOnly, func(r *http.Request, _ *sessionUser) (any, error) {
+		var check db.Check
+		if err := decode(r, &check); err != nil {
+			return nil, err
+		}
+		if err := s.store.Checks.Create(&check); err != nil {
+			return nil, err
+		}
+		return check, nil
+	})
+	s.handle("POST /api/checks/search", adminOnly, func(r *http.Request, _ *sessionUser) (any, error) {
+		var filter db.CheckFilter
+		if err := decode(r, &filter); err != nil {
+			return nil, err
+		}
+		return s.store.Checks.List(filter)
+	})
+	s.handle("GET /api/checks/pending", adminOnly, func(*http.Request, *sessionUser) (any, error) {
+		return s.store.Checks.ListPending()
+	})
+	s.handle("GET /api/checks/{id}", signedIn, func(r *http.Request, user *sessionUser) (any, error) {
+		id, err := pathID(r, "id")
+		if err != nil {
+			return nil, err
+		}
+		check, err := s.store.Checks.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		if err := selfOrAdmin(user, check.ClientID); err != nil {
+			return nil, err
+		}
+		return check, nil
+	})
+	s.handle("POST /api/checks/{id}/approve", adminOnly, func(r *http.Request, user *sessionUser) (any, error) {
+		id, err := pathID(r, "id")
+		if err != nil {
+			return nil, err
+		}
+		return s.store.Purchases.Approve(id, user.UserID)
+	})
+	s.handle("POST /api/checks/{id}/reject", adminOnly, func(r *http.Request, user *sessionUser) (any, error) {
+		id, err := pathID(r, "id")
+		if err != nil {
+			return nil, err
+		}
+		return s.store.Purchases.Reject(id, user.UserID)
+	})
+	s.handle("POST /api/purchases", signedIn, func(r *http.Request, user *sessionUser) (any, error) {
+		var req purchaseRequest
+		if err := decode(r, &req); err != nil {
+			return nil, err
+		}
+		if err := selfOrAdmin(user, req.ClientID); err != nil {
+			return nil, err
+		}
+		return s.store.Purchases.Purchase(req.ClientID, req.CarID, req.Price)
+	})
+	s.handle("GET /api/sales", adminOnly, func(r *http.Request, _ *sessionUser) (any, error) {
+		includeArchived, _ := strconv.ParseBool(r.URL.Query().Get("archived"))
+		return s.store.Checks.ListSales(includeArchived)
+	})
+	s.handle("GET /api/sales/summary", adminOnly, func(r *http.Request, _ *sessionUser) (any, error) {
+		from, err := queryTime(r, "from")
+		if err != nil {
+			return nil, err
+		}
+		to, err := queryTime(r, "to")
+		if err != nil {
+			return nil, err
+		}
+		return s.store.Checks.SalesSummary(from, to)
+	})
+
+	// Фотографии
+	s.handle("POST /api/photos", adminOnly, func(r *http.Request, _ *sessionUser) (any, error) {
+		var upload db.CarPhoto
+		if err := decode(r, &upload); err != nil {
+			return nil, err
+		}
+		// Формат и миниатюру определяет сервер: присланным рабочим местом не доверяем
+		prepared, err := photo.Prepare(upload.Data)
+		if err != nil {
+			return nil, err
+		}
+		p := db.CarPhoto{CarID: upload.CarID, ContentType: prepared.ContentType, Data: prepared.Data, Thumbnail: prepared.Thumbnail}
+		if err := s.store.Photos.Add(&p); err != nil {
+			return nil, err
+		}
+		p.Data = nil // файл у клиента уже есть
+		return p, nil
+	})
+	s.handle("GET /api/photos/covers", signedIn, func(r *http.Request, _ *sessionUser) (any, error) {
+		ids, err := queryIDs(r, "car")
+		if err != nil {
+			return nil, err
+		}
+		return s.store.Photos.Covers(ids)
+	})
+	s.handle("GET /api/photos/{id}", signedIn, withID(s.store.Photos.Get))
+	s.handle("DELETE /api/photos/{id}", adminOnly, func(r *http.Request, _ *sessionUser) (any, error) {
+		id, err := pathID(r, "id")
+		if err != nil {
+			return nil, err
+		}
+		return nil, s.store.Photos.Delete(id)
+	})
+
+	// Справочники
+	s.handle("GET /api/dictionaries/{kind}", signedIn, func(r *http.Request, _ *sessionUser) (any, error) {
+		brandID := 0
+		if v := r.URL.Query().Get("brand"); v != "" {
+			var err error
+			if brandID, err = strconv.Atoi(v); err != nil {
+				return nil, fmt.Errorf("%w: неверный номер марки %q", ErrBadRequest, v)
+			}
+		}
+		return s.store.Dictionaries.List(db.DictionaryKind(r.PathValue("kind")), brandID)
+	})
+	s.handle("POST /api/dictionaries/{kind}", adminOnly, func(r *http.Request, _ *sessionUser) (any, error) {
+		var entry db.DictionaryEntry
+		if err := decode(r, &entry); err != nil {
+			return nil, err
+		}
+		entry.Kind = db.DictionaryKind(r.PathValue("kind"))
+		if err := s.store.Dictionaries.Add(&entry); err != nil {
+			return nil, err
+		}
+		return entry, nil
+	})
+	s.handle("PUT /api/dictionaries/{kind}/{id}", adminOnly, func(r *http.Request, _ *sessionUser) (any, error) {
+		id, err := pathID(r, "id")
+		if err != nil {
+			return nil, err
+		}
+		var req renameRequest
+		if err := decode(r, &req); err != nil {
+			return nil, err
+		}
+		return nil, s.store.Dictionaries.Rename(db.DictionaryKind(r.PathValue("kind")), id, req.Name)
+	})
+	s.handle("POST /api/dictionaries/{kind}/{id}/merge", adminOnly, func(r *http.Request, _ *sessionUser) (any, error) {
+		id, err := pathID(r, "id")
+		if err != nil {
+			return nil, err
+		}
+		var req mergeRequest
+		if err := decode(r, &req); err != nil {
+			return nil, err
+		}
+		return nil, s.store.Dictionaries.Merge(db.DictionaryKind(r.PathValue("kind")), id, req.Into)
+	})
+	s.handle("DELETE /api/dictionaries/{kind}/{id}", adminOnly, func(r *http.Request, _ *sessionUser) (any, error) {
+		id, err := pathID(r, "id")
+		if err != nil {
+			return nil, err
+		}
+		return nil, s.store.Dictionaries.Delete(db.DictionaryKind(r.PathValue("kind")), id)
+	})
+}
+
+// login проверяет пароль и открывает сессию в запрошенной роли
+func (s *Server) login(r *http.Request, _ *sessionUser) (any, error) {
+	var req loginRequest
+	if err := decode(r, &req); err != nil {
+		return nil, err
+	}
+
+	var resp loginResponse
+	var user sessionUser
+	switch req.Role {
+	case auth.RoleClient:
+		client, err := s.auth.LoginClient(req.Login, req.Password)
+		if err != nil {
+			return nil, err
+		}
+		client.Password = ""
+		resp.Client = client
+		user = sessionUser{Role: auth.RoleClient, UserID: client.ID, Login: client.Login}
+	case auth.RoleAdmin:
+		admin, err := s.auth.LoginAdmin(req.Login, req.Password)
+		if err != nil {
+			return nil, err
+		}
+		admin.Password = ""
+		resp.Admin = admin
+		user = sessionUser{Role: auth.RoleAdmin, UserID: admin.ID, Login: admin.Login}
+	default:
+		return nil, fmt.Errorf("%w: неизвестная роль %q", ErrBadRequest, req.Role)
+	}
+
+	var err error
+	if resp.Token, err = s.sessions.open(user); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// withID оборачивает чтение по числовому параметру пути {id}
+func withID[T any](get func(id int) (T, error)) handlerFunc {
+	return func(r *http.Request, _ *sessionUser) (any, error) {
+		id, err := pathID(r, "id")
+		if err != nil {
+			return nil, err
+		}
+		return get(id)
+	}
+}
+
+// decodePassword читает новый пароль из тела запроса и возвращает его хэш.
+// Пароль всегда хэшируется заново, даже если уже похож на bcrypt-хэш.
+func decodePassword(r *http.Request) (string, error) {
+	var req passwordRequest
+	if err := decode(r, &req); err != nil {
+		return "", err
+	}
+	return auth.HashPassword(req.Password)
+}
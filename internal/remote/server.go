@@ -0,0 +1,182 @@
+// Package remote открывает хранилище по сети: сервер carsales-server работает с базой
+// и отвечает на запросы HTTP/JSON, а клиент предоставляет рабочим местам те же
+// репозитории db.Store и вход через auth.Authenticator.
+package remote
+
+import (
+	"car-sales-system/internal/auth"
+	"car-sales-system/internal/db"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxBodySize ограничивает тело запроса; самое большое — фотография с миниатюрой в base64
+const maxBodySize = 64 << 20
+
+// Server отвечает на запросы рабочих мест (/api) и сайта (/v1, см. openapi.yaml).
+// Операции рабочих мест, кроме входа и регистрации, требуют токена сессии, а изменяющие
+// каталог и чужие данные — роли администратора. Каталог сайта открыт без входа.
+type Server struct {
+	store    *db.Store
+	auth     *auth.Service
+	sessions *sessionStore
+	mux      *http.ServeMux
+}
+
+// NewServer создаёт сервер поверх хранилища
+func NewServer(store *db.Store) *Server {
+	s := &Server{
+		store:    store,
+		auth:     auth.NewService(store),
+		sessions: newSessionStore(sessionTTL),
+		mux:      http.NewServeMux(),
+	}
+	s.routes()
+	s.publicRoutes()
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// access — кому доступна операция
+type access int
+
+const (
+	public     access = iota // без входа
+	signedIn                 // любому вошедшему пользователю
+	adminOnly                // только администратору
+	clientOnly               // только клиенту
+)
+
+// handlerFunc выполняет операцию от имени вошедшего пользователя (nil для public)
+// и возвращает тело ответа; nil — ответ без тела
+type handlerFunc func(r *http.Request, user *sessionUser) (any, error)
+
+// created — тело ответа о созданной записи (201 Created)
+type created struct{ body any }
+
+// handle регистрирует операцию с проверкой доступа и кодированием ответа
+func (s *Server) handle(pattern string, level access, h handlerFunc) {
+	s.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+
+		var result any
+		user, err := s.authorize(r, level)
+		if err == nil {
+			result, err = h(r, user)
+		}
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		switch result := result.(type) {
+		case nil:
+			w.WriteHeader(http.StatusNoContent)
+		case created:
+			writeJSON(w, http.StatusCreated, result.body)
+		default:
+			writeJSON(w, http.StatusOK, result)
+		}
+	})
+}
+
+// authorize находит сессию по токену и проверяет роль
+func (s *Server) authorize(r *http.Request, level access) (*sessionUser, error) {
+	if level == public {
+		return nil, nil
+	}
+	user, ok := s.sessions.lookup(bearerToken(r))
+	if !ok {
+		return nil, ErrUnauthorized
+	}
+	if (level == adminOnly && user.Role != auth.RoleAdmin) || (level == clientOnly && user.Role != auth.RoleClient) {
+		return nil, ErrForbidden
+	}
+	return user, nil
+}
+
+// writeError отвечает известной ошибкой с её кодом; остальные записываются в журнал,
+// а клиенту уходит общее сообщение, чтобы не раскрывать подробности базы
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeJSON(w, http.StatusRequestEntityTooLarge, errorBody{Error: "слишком большой запрос", Code: "bad_request"})
+		return
+	}
+	if c, ok := lookupError(err); ok {
+		writeJSON(w, c.status, errorBody{Error: err.Error(), Code: c.code})
+		return
+	}
+	slog.Error("Ошибка обработки запроса", "method", r.Method, "path", r.URL.Path, "error", err)
+	writeJSON(w, http.StatusInternalServerError, errorBody{Error: "внутренняя ошибка сервера"})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Warn("Не удалось отправить ответ", "error", err)
+	}
+}
+
+// decode читает тело запроса JSON в v
+func decode(r *http.Request, v any) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return err
+		}
+		return fmt.Errorf("%w: %v", ErrBadRequest, err)
+	}
+	return nil
+}
+
+// pathID разбирает числовой параметр пути
+func pathID(r *http.Request, name string) (int, error) {
+	id, err := strconv.Atoi(r.PathValue(name))
+	if err != nil {
+		return 0, fmt.Errorf("%w: неверный номер %q", ErrBadRequest, r.PathValue(name))
+	}
+	return id, nil
+}
+
+// queryIDs разбирает повторяющийся числовой параметр запроса
+func queryIDs(r *http.Request, name string) ([]int, error) {
+	var ids []int
+	for _, v := range r.URL.Query()[name] {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: неверный номер %q", ErrBadRequest, v)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// queryTime разбирает время в формате RFC 3339; пустой параметр — нулевое время
+func queryTime(r *http.Request, name string) (time.Time, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: неверное время %q", ErrBadRequest, v)
+	}
+	return t, nil
+}
+
+// selfOrAdmin разрешает операцию над данными клиента clientID только ему самому и администратору
+func selfOrAdmin(user *sessionUser, clientID int) error {
+	if user.Role == auth.RoleAdmin || (user.Role == auth.RoleClient && user.UserID == clientID) {
+		return nil
+	}
+	return ErrForbidden
+}
@@ -0,0 +1,99 @@
+package remote
+
+import (
+	"car-sales-system/internal/auth"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sessionTTL — время простоя, после которого токен сессии перестаёт действовать
+const sessionTTL = 12 * time.Hour
+
+// sessionUser — пользователь, вошедший на сервер
+type sessionUser struct {
+	Role   auth.Role
+	UserID int
+	Login  string
+}
+
+type serverSession struct {
+	user     sessionUser
+	lastSeen time.Time
+}
+
+// sessionStore хранит сессии в памяти сервера: после перезапуска пользователи входят заново
+type sessionStore struct {
+	ttl time.Duration
+
+	mu       sync.Mutex
+	sessions map[string]*serverSession
+}
+
+func newSessionStore(ttl time.Duration) *sessionStore {
+	return &sessionStore{ttl: ttl, sessions: make(map[string]*serverSession)}
+}
+
+// open создаёт сессию и возвращает её токен
+func (s *sessionStore) open(user sessionUser) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.purge(time.Now())
+	s.sessions[token] = &serverSession{user: user, lastSeen: time.Now()}
+	return token, nil
+}
+
+// lookup возвращает пользователя действующей сессии и продлевает её
+func (s *sessionStore) lookup(token string) (*sessionUser, bool) {
+	if token == "" {
+		return nil, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[token]
+	now := time.Now()
+	if !ok || now.Sub(session.lastSeen) >= s.ttl {
+		delete(s.sessions, token)
+		return nil, false
+	}
+	session.lastSeen = now
+	user := session.user
+	return &user, true
+}
+
+// close завершает сессию
+func (s *sessionStore) close(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sessions, token)
+}
+
+// purge удаляет просроченные сессии; вызывается под s.mu
+func (s *sessionStore) purge(now time.Time) {
+	for token, session := range s.sessions {
+		if now.Sub(session.lastSeen) >= s.ttl {
+			delete(s.sessions, token)
+		}
+	}
+}
+
+// bearerToken возвращает токен из заголовка Authorization: Bearer …
+func bearerToken(r *http.Request) string {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
@@ -0,0 +1,107 @@
+package vin
+
+import (
+	_ "embed"
+	"strings"
+	"unicode"
+)
+
+// Info — сведения, извлечённые из VIN без обращения к внешним сервисам
+type Info struct {
+	WMI string
+	// Brand и Country пусты, если производитель не найден во встроенной таблице
+	Brand   string
+	Country string
+	// Year — наиболее вероятный модельный год; Years — все годы, которым соответствует символ года
+	Year  int
+	Years []int
+}
+
+//go:embed wmi.csv
+var wmiCSV string
+
+// wmiEntry — строка встроенной таблицы производителей
+type wmiEntry struct {
+	brand, country string
+}
+
+// wmiTable — встроенная таблица WMI (первые три символа VIN) → производитель
+var wmiTable = parseWMI(wmiCSV)
+
+func parseWMI(csv string) map[string]wmiEntry {
+	table := make(map[string]wmiEntry)
+	for _, line := range strings.Split(csv, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, ";")
+		if len(fields) != 3 {
+			continue
+		}
+		table[fields[0]] = wmiEntry{brand: fields[1], country: fields[2]}
+	}
+	return table
+}
+
+// yearCodes — символы модельного года (10-я позиция) в порядке, начиная с 1980 года; цикл 30 лет
+const yearCodes = "ABCDEFGHJKLMNPRSTVWXY123456789"
+
+const (
+	firstModelYear = 1980
+	yearCycle      = len(yearCodes)
+	// yearPos — позиция символа модельного года (с нуля)
+	yearPos = 9
+	// cycleHintPos — позиция, по которой у североамериканских VIN различаются циклы:
+	// буква означает модели с 2010 года, цифра — до 2009 года
+	cycleHintPos = 6
+)
+
+// Decode извлекает из VIN производителя по встроенной таблице WMI и модельный год.
+// refYear ограничивает возможные годы сверху (обычно текущий год плюс один).
+// Проверяются только длина и набор символов, контрольная цифра не требуется:
+// у европейских VIN её часто нет.
+func Decode(v string, refYear int) (Info, error) {
+	v = Normalize(v)
+	if len(v) != Length {
+		return Info{}, ErrLength
+	}
+	for i := 0; i < Length; i++ {
+		if _, ok := transliterate(v[i]); !ok {
+			return Info{}, ErrCharset
+		}
+	}
+
+	info := Info{WMI: v[:3]}
+	if entry, ok := wmiTable[info.WMI]; ok {
+		info.Brand, info.Country = entry.brand, entry.country
+	}
+
+	code := strings.IndexByte(yearCodes, v[yearPos])
+	if code < 0 {
+		// Символы 0, U и Z не используются для обозначения года
+		return info, nil
+	}
+	for year := firstModelYear + code; year <= refYear; year += yearCycle {
+		info.Years = append(info.Years, year)
+	}
+	info.Year = pickYear(info.Years, v)
+	return info, nil
+}
+
+// pickYear выбирает год из кандидатов: для североамериканских VIN — по седьмому символу,
+// для остальных — самый поздний
+func pickYear(years []int, v string) int {
+	if len(years) == 0 {
+		return 0
+	}
+	if NorthAmerican(v) {
+		newCycle := unicode.IsLetter(rune(v[cycleHintPos]))
+		for _, year := range years {
+			if (year >= 2010) == newCycle {
+				return year
+			}
+		}
+	}
+	return years[len(years)-1]
+}
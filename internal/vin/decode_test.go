@@ -0,0 +1,38 @@
+package vin
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		vin     string
+		brand   string
+		country string
+		year    int
+	}{
+		{"1HGCM82633A004352", "Honda", "США", 2003},
+		{"5YJ3E1EA7KF317000", "Tesla", "США", 2019},
+		{"WBA3A5C51CF256651", "BMW", "Германия", 2012},
+		{"XTA210930Y2696789", "Lada", "Россия", 2000},
+		{"1M8GDM9AXKP042788", "", "", 1989},
+	}
+	for _, tt := range tests {
+		info, err := Decode(tt.vin, 2026)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.vin, err)
+		}
+		if info.Brand != tt.brand || info.Country != tt.country || info.Year != tt.year {
+			t.Errorf("%s: %+v, ожидалось %s %s %d", tt.vin, info, tt.brand, tt.country, tt.year)
+		}
+	}
+
+	info, err := Decode("wba3a5c51cf256651", 2026)
+	if err != nil || info.WMI != "WBA" || len(info.Years) != 2 || info.Years[0] != 1982 {
+		t.Errorf("VIN в нижнем регистре: %+v, %v", info, err)
+	}
+	if _, err := Decode("WBA3A5C51CF2566", 2026); !errors.Is(err, ErrLength) {
+		t.Errorf("короткий VIN: ожидалась ErrLength, получено %v", err)
+	}
+}
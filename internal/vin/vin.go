@@ -0,0 +1,97 @@
+// Package vin проверяет идентификационные номера транспортных средств (VIN, ISO 3779).
+package vin
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Length — длина VIN
+const Length = 17
+
+// Ошибки проверки VIN
+var (
+	ErrLength   = fmt.Errorf("VIN должен состоять из %d символов", Length)
+	ErrCharset  = errors.New("VIN может содержать только латинские буквы, кроме I, O и Q, и цифры")
+	ErrChecksum = errors.New("неверная контрольная цифра VIN")
+)
+
+// checkDigitPos — позиция контрольной цифры (с нуля)
+const checkDigitPos = 8
+
+// weights — веса позиций при подсчёте контрольной цифры
+var weights = [Length]int{8, 7, 6, 5, 4, 3, 2, 10, 0, 9, 8, 7, 6, 5, 4, 3, 2}
+
+// Normalize убирает пробелы по краям и переводит VIN в верхний регистр
+func Normalize(s string) string {
+	return strings.ToUpper(strings.TrimSpace(s))
+}
+
+// Validate проверяет длину и набор символов нормализованного VIN.
+// Контрольная цифра проверяется только для регионов, где она обязательна
+// (см. checkDigitRegions): европейские производители её обычно не ставят.
+func Validate(v string) error {
+	if len(v) != Length {
+		return ErrLength
+	}
+	for i := 0; i < Length; i++ {
+		if _, ok := transliterate(v[i]); !ok {
+			return ErrCharset
+		}
+	}
+	if !CheckDigitRequired(v) {
+		return nil
+	}
+	if want := CheckDigit(v); v[checkDigitPos] != want {
+		return fmt.Errorf("%w: ожидалась %c", ErrChecksum, want)
+	}
+	return nil
+}
+
+// NorthAmerican сообщает, что VIN выдан в Северной Америке (WMI начинается с 1–5)
+func NorthAmerican(v string) bool {
+	return v != "" && v[0] >= '1' && v[0] <= '5'
+}
+
+// checkDigitRegions — первые символы WMI регионов, где контрольная цифра обязательна:
+// 1–5 — Северная Америка (FMVSS 115), L — Китай (GB 16735)
+const checkDigitRegions = "12345L"
+
+// CheckDigitRequired сообщает, что VIN выдан в регионе с обязательной контрольной цифрой
+func CheckDigitRequired(v string) bool {
+	return v != "" && strings.IndexByte(checkDigitRegions, v[0]) >= 0
+}
+
+// CheckDigit вычисляет контрольную цифру VIN ('0'–'9' или 'X').
+// Символ на позиции контрольной цифры не учитывается.
+func CheckDigit(v string) byte {
+	sum := 0
+	for i := 0; i < len(v) && i < Length; i++ {
+		value, _ := transliterate(v[i])
+		sum += value * weights[i]
+	}
+	if rem := sum % 11; rem != 10 {
+		return byte('0' + rem)
+	}
+	return 'X'
+}
+
+// transliterate возвращает числовое значение символа VIN; I, O и Q недопустимы
+func transliterate(c byte) (int, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0'), true
+	case c >= 'A' && c <= 'H':
+		return int(c-'A') + 1, true
+	case c >= 'J' && c <= 'N':
+		return int(c-'J') + 1, true
+	case c == 'P':
+		return 7, true
+	case c == 'R':
+		return 9, true
+	case c >= 'S' && c <= 'Z':
+		return int(c-'S') + 2, true
+	}
+	return 0, false
+}
@@ -0,0 +1,60 @@
+package vin
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		vin  string
+		want error
+	}{
+		{"1M8GDM9AXKP042788", nil},
+		{"11111111111111111", nil},
+		{"1HGCM82633A004352", nil},
+		{"1HGCM82633A00435", ErrLength},
+		{"1HGCM82633A0043520", ErrLength},
+		{"1HGCM82633O004352", ErrCharset},
+		{"1hgcm82633a004352", ErrCharset},
+		{"1HGCM82683A004352", ErrChecksum},
+		{"5YJSA1E27HF000337", nil},
+		{"5YJSA1E26HF000337", ErrChecksum},
+		// Европейские VIN без контрольной цифры
+		{"WVWZZZ1JZ3W386752", nil},
+		{"WBA3A5C51CF256651", nil},
+		{"WVWZZZ1JZ3O386752", ErrCharset},
+		// Китайские VIN: контрольная цифра обязательна
+		{"LSVAU218XN2183294", nil},
+		{"LSVAU2180N2183294", ErrChecksum},
+		// Японские VIN: контрольная цифра не проверяется
+		{"JTDKB20U093000001", nil},
+	}
+	for _, tt := range tests {
+		if err := Validate(tt.vin); !errors.Is(err, tt.want) {
+			t.Errorf("Validate(%q) = %v, ожидалось %v", tt.vin, err, tt.want)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	if got := Normalize("  1hgcm82633a004352 "); got != "1HGCM82633A004352" {
+		t.Errorf("Normalize = %q", got)
+	}
+	if err := Validate(Normalize("1hgcm82633a004352")); err != nil {
+		t.Errorf("нормализованный VIN: %v", err)
+	}
+}
+
+func TestCheckDigitRequired(t *testing.T) {
+	for _, v := range []string{"1HGCM82633A004352", "5YJSA1E27HF000337", "LSVAU218XN2183294"} {
+		if !CheckDigitRequired(v) {
+			t.Errorf("CheckDigitRequired(%q) = false", v)
+		}
+	}
+	for _, v := range []string{"WVWZZZ1JZ3W386752", "JTDKB20U093000001", "KMHCT41D0BU000001", ""} {
+		if CheckDigitRequired(v) {
+			t.Errorf("CheckDigitRequired(%q) = true", v)
+		}
+	}
+}